@@ -3,11 +3,15 @@ package collector
 
 import (
 	// collector 只依赖于 simulation 包中定义的类型和接口，不关心其内部逻辑
+	"Air-Simulator/config"
 	"Air-Simulator/simulation"
+	"encoding/csv"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,10 +27,17 @@ type DataCollector struct {
 	aircrafts      []*simulation.Aircraft
 	channels       []*simulation.Channel
 	groundStations []*simulation.GroundControlCenter
+	airspace       *simulation.Airspace
+	sectors        []*simulation.Sector // 未划分扇区时为空
+	mediumAccess   string               // 本次模拟使用的信道接入策略，用于标注报告
 	filename       string
 	wg             *sync.WaitGroup
 	done           <-chan struct{}
 	startTime      time.Time
+
+	lastGroundReceived map[string]uint64 // 上一次记录时各地面站的累计接收数，用于计算本周期接收
+
+	saveErr error // 最终保存 Excel 报告时遇到的错误
 }
 
 // NewDataCollector 创建一个新的数据收集器实例。
@@ -37,6 +48,9 @@ func NewDataCollector(
 	aircrafts []*simulation.Aircraft,
 	channels []*simulation.Channel, // 直接接收信道列表
 	groundStations []*simulation.GroundControlCenter,
+	airspace *simulation.Airspace,
+	sectors []*simulation.Sector,
+	mediumAccess string,
 ) *DataCollector {
 	// 创建带有时间戳的唯一文件名
 	baseFilename := fmt.Sprintf("simulation_report_%s.xlsx", time.Now().Format("20060102_150405"))
@@ -46,10 +60,15 @@ func NewDataCollector(
 		aircrafts:      aircrafts,
 		channels:       channels,
 		groundStations: groundStations,
+		airspace:       airspace,
+		sectors:        sectors,
+		mediumAccess:   mediumAccess,
 		filename:       fullPath,
 		wg:             wg,
 		done:           done,
 		startTime:      time.Now(),
+
+		lastGroundReceived: make(map[string]uint64),
 	}
 }
 
@@ -61,7 +80,7 @@ func (dc *DataCollector) Run() {
 	f := excelize.NewFile()
 	defer func() {
 		if err := f.Close(); err != nil {
-			log.Printf("❌ 关闭Excel文件时出错: %v", err)
+			slog.Error("❌ 关闭Excel文件时出错", "err", err)
 		}
 	}()
 
@@ -70,22 +89,26 @@ func (dc *DataCollector) Run() {
 	f.NewSheet(aircraftSheet)
 	f.NewSheet(channelSheet)
 	f.NewSheet(groundSheet)
+	sectorSheet, deadLetterSheet, summarySheet := "Sector_Stats", "DeadLetters", "Summary"
+	f.NewSheet(sectorSheet)
+	f.NewSheet(deadLetterSheet)
+	f.NewSheet(summarySheet)
 	f.DeleteSheet("Sheet1") // 删除默认创建的Sheet1
 
 	// --- 写入所有工作表的表头 ---
-	dc.writeHeaders(f, aircraftSheet, channelSheet, groundSheet)
+	dc.writeHeaders(f, aircraftSheet, channelSheet, groundSheet, sectorSheet, deadLetterSheet)
 
 	// 初始化行计数器
-	aircraftRow, channelRow, groundRow := 2, 2, 2
+	aircraftRow, channelRow, groundRow, sectorRow := 2, 2, 2, 2
 
-	ticker := time.NewTicker(collectionInterval)
+	ticker := time.NewTicker(simulation.Scaled(collectionInterval))
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
 			// --- 定时记录数据快照 ---
-			simMinutes := int(time.Since(dc.startTime).Minutes())
+			simMinutes := int(simulation.Unscaled(time.Since(dc.startTime)).Minutes())
 			log.Printf("📊 正在记录模拟时间 %d 分钟时的数据快照...", simMinutes)
 
 			// 记录所有飞机的数据
@@ -94,36 +117,66 @@ func (dc *DataCollector) Run() {
 			channelRow = dc.recordChannelStats(f, channelSheet, channelRow, simMinutes)
 			// 记录所有地面站的数据
 			groundRow = dc.recordGroundStationStats(f, groundSheet, groundRow, simMinutes)
+			// 记录所有扇区的负载
+			sectorRow = dc.recordSectorStats(f, sectorSheet, sectorRow, simMinutes)
 
 		case <-dc.done:
 
-			simMinutes := int(time.Since(dc.startTime).Minutes())
+			simMinutes := int(simulation.Unscaled(time.Since(dc.startTime)).Minutes())
 			aircraftRow = dc.recordAircraftStats(f, aircraftSheet, aircraftRow, simMinutes)
 			// 记录所有信道的数据
 			channelRow = dc.recordChannelStats(f, channelSheet, channelRow, simMinutes)
 			// 记录所有地面站的数据
 			groundRow = dc.recordGroundStationStats(f, groundSheet, groundRow, simMinutes)
+			// 记录所有扇区的负载
+			sectorRow = dc.recordSectorStats(f, sectorSheet, sectorRow, simMinutes)
 
-			// --- 接收到停止信号，执行最终保存 ---
+			// --- 接收到停止信号，记录所有未能送达的报文并执行最终保存 ---
+			dc.recordDeadLetters(f, deadLetterSheet)
+			dc.recordSummary(f, summarySheet, simMinutes)
 			log.Println("✅ 模拟结束，正在整理并保存所有数据到Excel文件...")
-			dc.saveReport(f)
+			dc.saveErr = dc.saveReport(f)
 			return // 结束 goroutine
 		}
 	}
 }
 
 // writeHeaders 负责向Excel文件写入表头。
-func (dc *DataCollector) writeHeaders(f *excelize.File, aircraftSheet, channelSheet, groundSheet string) {
-	headersAircraft := []string{"SimTime (min)", "航班号", "成功传输", "重传", "尝试传输", "碰撞次数", "碰撞率 (%)",
-		"平均等待时间 (ms)", "请求信道", "失败请求信道", "请求信道失败率 (%)"}
+func (dc *DataCollector) writeHeaders(f *excelize.File, aircraftSheet, channelSheet, groundSheet, sectorSheet, deadLetterSheet string) {
+	headersAircraft := []string{"SimTime (min)", "航班号", "机型配置", "成功传输", "重传", "尝试传输", "碰撞次数", "碰撞率 (%)",
+		"平均等待时间 (ms)", "请求信道", "失败请求信道", "请求信道失败率 (%)",
+		"链路测试次数", "链路RTT最小 (ms)", "链路RTT平均 (ms)", "链路RTT最大 (ms)", "强制切换", "永久失败",
+		"ACK RTT最小 (ms)", "ACK RTT平均 (ms)", "ACK RTT P95 (ms)", "收件箱溢出丢弃",
+		"D-ATIS接收", "D-ATIS版本", "被拒绝 (NACK)", "发射功率 (dBm)", "校验失败", "跨扇区", "发送队列溢出", "发送中错过ACK",
+		"时延P50 (ms)", "时延P95 (ms)", "时延P99 (ms)", "链路质量", "链路质量损坏帧", "气象请求", "气象回复", "盲区停留 (s)", "盲区丢失帧",
+		"接收机换频", "换频错过ACK", "异频错过ACK"}
 	_ = f.SetSheetRow(aircraftSheet, "A1", &headersAircraft)
 
-	headersChannel := []string{"SimTime (min)", "信道", "是否启用", "成功传输", "信道使用时间 (ms)", "信道使用率 (%)"}
+	headersChannel := []string{"SimTime (min)", "信道", "是否启用", "成功传输", "信道使用时间 (ms)", "信道使用率 (%)", "接入策略",
+		"天气影响程度", "误帧率", "速率系数", "损坏帧数", "碰撞检测", "重叠碰撞", "节省信道时间 (ms)",
+		"容量", "平均占用 (路)", "尝试传输", "提供负载 G", "承载负载 S", "抢占次数", "抢占浪费时间 (ms)", "弱信号丢帧", "最近接收功率 (dBm)", "优先级反转", "时隙 (ms)",
+		"CRITICAL传输", "非CRITICAL传输", "空闲 (%)", "成功占用 (%)", "损坏占用 (%)"}
+	// 空闲间隔分布：每个区间一列
+	lower := "0"
+	for _, bound := range simulation.IdleGapBounds {
+		headersChannel = append(headersChannel, fmt.Sprintf("空闲间隔 %s~%s", lower, bound))
+		lower = bound.String()
+	}
+	headersChannel = append(headersChannel, fmt.Sprintf("空闲间隔 >%s", lower))
 	_ = f.SetSheetRow(channelSheet, "A1", &headersChannel)
 
 	headersGround := []string{"SimTime (min)", "地面站名", "成功传输", "尝试传输", "碰撞次数", "碰撞率 (%)",
-		"平均等待时间 (ms)", "请求信道", "失败请求信道", "请求信道失败率 (%)"}
+		"平均等待时间 (ms)", "请求信道", "失败请求信道", "请求信道失败率 (%)", "覆盖内接收", "覆盖外忽略", "移交接入", "负载占比 (%)", "强制切换", "重复报文",
+		"分片组", "重组完成", "重组成功率 (%)", "收件箱溢出丢弃", "D-ATIS广播",
+		"在线", "离线缓存", "离线丢弃", "本周期接收", "ACK帧", "确认报文", "NACK", "校验失败", "发送队列溢出",
+		"处理中", "处理积压", "气象回复"}
 	_ = f.SetSheetRow(groundSheet, "A1", &headersGround)
+
+	headersSector := []string{"SimTime (min)", "扇区", "地面站", "信道", "当前飞机", "进入次数", "成功传输", "信道使用率 (%)", "地面站接收"}
+	_ = f.SetSheetRow(sectorSheet, "A1", &headersSector)
+
+	headersDeadLetter := []string{"发送方", "报文ID", "报文类型", "优先级", "原因", "开始发送时间"}
+	_ = f.SetSheetRow(deadLetterSheet, "A1", &headersDeadLetter)
 }
 
 // recordAircraftStats 记录所有飞机的统计数据。
@@ -144,8 +197,16 @@ func (dc *DataCollector) recordAircraftStats(f *excelize.File, sheet string, sta
 		}
 
 		rowData := []interface{}{
-			simMinutes, ac.CurrentFlightID, stats.SuccessfulTx, stats.TotalRetries, stats.TotalTxAttempts, stats.TotalCollisions, collisionRate,
+			simMinutes, ac.CurrentFlightID, ac.Profile.Name, stats.SuccessfulTx, stats.TotalRetries, stats.TotalTxAttempts, stats.TotalCollisions, collisionRate,
 			avgWaitTimeMs, stats.TotalRqTunnel, stats.TotalFailRqTunnel, rqFailRate,
+			stats.LinkTestCount, stats.LinkTestRTTMin.Milliseconds(), stats.LinkTestRTTAvg.Milliseconds(), stats.LinkTestRTTMax.Milliseconds(),
+			stats.ForcedSwitchovers, stats.PermanentFailures,
+			stats.AckRTTMin.Milliseconds(), stats.AckRTTAvg.Milliseconds(), stats.AckRTTP95.Milliseconds(),
+			stats.ListenerDrops, stats.DATISReceived, stats.DATISEdition, stats.NacksReceived, ac.TxPowerDBm, stats.ChecksumFailures, stats.SectorCrossings, stats.QueueOverflowDrops, stats.AckMissedDueToTx,
+			stats.Latency.P50.Milliseconds(), stats.Latency.P95.Milliseconds(), stats.Latency.P99.Milliseconds(),
+			ac.LinkQuality, stats.LinkQualityLosses, stats.WeatherRequests, stats.WeatherReplies,
+			stats.DeadZoneTime.Seconds(), stats.DeadZoneLosses,
+			stats.ReceiverRetunes, stats.AckMissedTuning, stats.AckMissedOffTuned,
 		}
 		_ = f.SetSheetRow(sheet, fmt.Sprintf("A%d", row), &rowData)
 		row++
@@ -156,25 +217,48 @@ func (dc *DataCollector) recordAircraftStats(f *excelize.File, sheet string, sta
 // recordChannelStats 记录所有信道的统计数据。
 func (dc *DataCollector) recordChannelStats(f *excelize.File, sheet string, startRow int, simMinutes int) int {
 	row := startRow
-	totalSimDuration := time.Since(dc.startTime)
+	totalSimDuration := simulation.Unscaled(time.Since(dc.startTime))
 
 	for _, ch := range dc.channels {
 		// 核心要求：即使信道未启用(nil)，也要忠实记录其状态
 		if ch == nil {
-			rowData := []interface{}{simMinutes, "Backup (Disabled)", "Disabled", 0, 0, 0.0}
+			rowData := []interface{}{simMinutes, "Backup (Disabled)", "Disabled", 0, 0, 0.0, dc.mediumAccess, 0.0, 0.0, 0.0, 0, false, 0, 0, 0, 0.0, 0, 0.0, 0.0, 0, 0, 0, 0.0, 0, 0, 0, 0,
+				0.0, 0.0, 0.0}
+			for range len(simulation.IdleGapBounds) + 1 {
+				rowData = append(rowData, 0)
+			}
 			_ = f.SetSheetRow(sheet, fmt.Sprintf("A%d", row), &rowData)
 			row++
 			continue
 		}
 
 		stats := ch.GetRawStats() // 调用接口获取原始数据
-		var utilization float64
+		var utilization, averageOccupancy float64
+		// 信道时间的构成：完全空闲、被成功传输占用、被损坏 (碰撞、抢占) 的传输占用；后两者以容量为单位
+		var idleShare, successShare, corruptedShare float64
 		if totalSimDuration > 0 {
 			utilization = (float64(stats.TotalBusyTime) / float64(totalSimDuration)) * 100
+			averageOccupancy = float64(stats.OccupancyTime) / float64(totalSimDuration)
+			capacityTime := float64(totalSimDuration) * float64(stats.Capacity)
+			idleShare = float64(stats.IdleTime) / float64(totalSimDuration) * 100
+			successShare = float64(stats.Efficiency.SuccessfulAirTime) / capacityTime * 100
+			corruptedShare = float64(stats.OccupancyTime-stats.Efficiency.SuccessfulAirTime) / capacityTime * 100
 		}
 
 		rowData := []interface{}{
-			simMinutes, ch.ID, "Enabled", stats.TotalMessagesTransmitted, stats.TotalBusyTime.Milliseconds(), utilization,
+			simMinutes, ch.ID, "Enabled", stats.TotalMessagesTransmitted, stats.TotalBusyTime.Milliseconds(), utilization, dc.mediumAccess,
+			stats.ConditionFactor, stats.FrameErrorRate, stats.DataRateFactor, stats.FramesCorrupted,
+			ch.CollisionDetection, stats.OverlapCollisions, stats.RecoveredTime.Milliseconds(),
+			stats.Capacity, averageOccupancy,
+			stats.Efficiency.TransmitAttempts, stats.Efficiency.OfferedLoad, stats.Efficiency.CarriedLoad,
+			stats.Preemptions, stats.PreemptedTime.Milliseconds(),
+			stats.WeakSignalLosses, stats.LastReceivedPowerDBm, stats.PriorityInversions,
+			stats.TimeSlot.Milliseconds(),
+			stats.CriticalTransmitted, stats.TotalMessagesTransmitted - stats.CriticalTransmitted,
+			idleShare, successShare, corruptedShare,
+		}
+		for _, count := range stats.IdleGaps {
+			rowData = append(rowData, count)
 		}
 		_ = f.SetSheetRow(sheet, fmt.Sprintf("A%d", row), &rowData)
 		row++
@@ -185,6 +269,13 @@ func (dc *DataCollector) recordChannelStats(f *excelize.File, sheet string, star
 // recordGroundStationStats 记录所有地面站的统计数据。
 func (dc *DataCollector) recordGroundStationStats(f *excelize.File, sheet string, startRow int, simMinutes int) int {
 	row := startRow
+
+	// 先统计所有地面站接收的报文总数，用于计算各站的负载占比
+	var totalReceived uint64
+	for _, gcc := range dc.groundStations {
+		totalReceived += gcc.GetRawStats().TotalReceived
+	}
+
 	for _, gcc := range dc.groundStations {
 		stats := gcc.GetRawStats() // 调用接口获取原始数据
 		var collisionRate float64
@@ -200,9 +291,30 @@ func (dc *DataCollector) recordGroundStationStats(f *excelize.File, sheet string
 			rqFailRate = (float64(stats.TotalFailRqTunnel) / float64(stats.TotalRqTunnel)) * 100
 		}
 
+		var loadShare float64
+		if totalReceived > 0 {
+			loadShare = (float64(stats.TotalReceived) / float64(totalReceived)) * 100
+		}
+
+		var reassemblyRate float64
+		if stats.FragmentGroupsStarted > 0 {
+			reassemblyRate = (float64(stats.FragmentGroupsCompleted) / float64(stats.FragmentGroupsStarted)) * 100
+		}
+
+		// 本周期接收数：地面站恢复在线后补处理缓存报文、发送方集中重传时会出现明显的峰值
+		periodReceived := stats.TotalReceived - dc.lastGroundReceived[gcc.ID]
+		dc.lastGroundReceived[gcc.ID] = stats.TotalReceived
+
 		rowData := []interface{}{
 			simMinutes, gcc.ID, stats.SuccessfulTx, stats.TotalTxAttempts, stats.TotalCollisions, collisionRate,
 			avgWaitTimeMs, stats.TotalRqTunnel, stats.TotalFailRqTunnel, rqFailRate,
+			stats.TotalReceived, stats.OutOfCoverageIgnored, stats.HandoversIn, loadShare,
+			stats.ForcedSwitchovers, stats.DuplicatesReceived,
+			stats.FragmentGroupsStarted, stats.FragmentGroupsCompleted, reassemblyRate,
+			stats.ListenerDrops, stats.DATISBroadcasts,
+			stats.Available, stats.OutageBuffered, stats.OutageDropped, periodReceived,
+			stats.AckFramesSent, stats.AcksSent, stats.NacksSent, stats.ChecksumFailures, stats.QueueOverflowDrops,
+			stats.Processing, stats.ProcessingBacklog, stats.WeatherReplies,
 		}
 		_ = f.SetSheetRow(sheet, fmt.Sprintf("A%d", row), &rowData)
 		row++
@@ -210,19 +322,268 @@ func (dc *DataCollector) recordGroundStationStats(f *excelize.File, sheet string
 	return row
 }
 
+// recordSectorStats 记录所有扇区的负载：扇区内的飞机数、扇区各信道的传输与使用率之和，以及扇区地面站的接收数。
+func (dc *DataCollector) recordSectorStats(f *excelize.File, sheet string, startRow int, simMinutes int) int {
+	row := startRow
+	totalSimDuration := simulation.Unscaled(time.Since(dc.startTime))
+
+	for _, sector := range dc.sectors {
+		stats := sector.GetRawStats()
+		var channelIDs []string
+		var transmitted uint64
+		var busyTime time.Duration
+		for _, ch := range sector.Channels() {
+			chStats := ch.GetRawStats()
+			channelIDs = append(channelIDs, ch.ID)
+			transmitted += chStats.TotalMessagesTransmitted
+			busyTime += chStats.TotalBusyTime
+		}
+		var utilization float64
+		if totalSimDuration > 0 {
+			// 各信道使用率的平均值
+			utilization = (float64(busyTime) / float64(totalSimDuration) / float64(len(channelIDs))) * 100
+		}
+
+		rowData := []interface{}{
+			simMinutes, sector.ID, sector.Station.ID, strings.Join(channelIDs, ", "), stats.Aircraft, stats.Entries,
+			transmitted, utilization, sector.Station.GetRawStats().TotalReceived,
+		}
+		_ = f.SetSheetRow(sheet, fmt.Sprintf("A%d", row), &rowData)
+		row++
+	}
+	return row
+}
+
+// recordDeadLetters 在模拟结束时记录所有飞机和地面站未能送达的报文及其原因。
+func (dc *DataCollector) recordDeadLetters(f *excelize.File, sheet string) {
+	row := 2
+	writeLetters := func(sender string, letters []simulation.DeadLetter) {
+		for _, dl := range letters {
+			rowData := []interface{}{
+				sender, dl.MessageID, string(dl.Type), string(dl.Priority), string(dl.Reason), dl.EnqueuedAt.Format("15:04:05.000"),
+			}
+			_ = f.SetSheetRow(sheet, fmt.Sprintf("A%d", row), &rowData)
+			row++
+		}
+	}
+
+	for _, ac := range dc.aircrafts {
+		writeLetters(ac.CurrentFlightID, ac.DeadLetters())
+	}
+	for _, gcc := range dc.groundStations {
+		writeLetters(gcc.ID, gcc.DeadLetters())
+	}
+	log.Printf("📊 共记录 %d 条未送达报文。", row-2)
+}
+
+// recordSummary 在模拟结束时写入整个模拟的汇总指标。
+func (dc *DataCollector) recordSummary(f *excelize.File, sheet string, simMinutes int) {
+	// 每个发送方 (飞机与地面站) 的成功传输数，用于评估信道接入的公平性
+	throughputs := make([]float64, 0, len(dc.aircrafts)+len(dc.groundStations))
+	for _, ac := range dc.aircrafts {
+		throughputs = append(throughputs, float64(ac.GetRawStats().SuccessfulTx))
+	}
+	for _, gcc := range dc.groundStations {
+		throughputs = append(throughputs, float64(gcc.GetRawStats().SuccessfulTx))
+	}
+	fairness := computeJainIndex(throughputs)
+
+	// 所有地面站的分片重组成功率，以及合并 ACK 节省的 ACK 帧比例
+	var groupsStarted, groupsCompleted, ackFrames, acksSent uint64
+	for _, gcc := range dc.groundStations {
+		stats := gcc.GetRawStats()
+		groupsStarted += stats.FragmentGroupsStarted
+		groupsCompleted += stats.FragmentGroupsCompleted
+		ackFrames += stats.AckFramesSent
+		acksSent += stats.AcksSent
+	}
+	var reassemblyRate, ackFrameSaving float64
+	if groupsStarted > 0 {
+		reassemblyRate = (float64(groupsCompleted) / float64(groupsStarted)) * 100
+	}
+	if acksSent > 0 {
+		ackFrameSaving = (1 - float64(ackFrames)/float64(acksSent)) * 100
+	}
+
+	// 所有飞机在各优先级下观察到的最大等待时间，用于评估优先级老化对尾部时延的影响
+	maxWait := make(map[config.Priority]time.Duration)
+	for _, ac := range dc.aircrafts {
+		for priority, wait := range ac.GetRawStats().MaxWaitByPriority {
+			maxWait[priority] = max(maxWait[priority], wait)
+		}
+	}
+
+	// 飞机与地面站各自的整体碰撞率，与共享信道的基线运行对比可观察上下行分频的效果
+	var aircraftAttempts, aircraftCollisions, groundAttempts, groundCollisions uint64
+	for _, ac := range dc.aircrafts {
+		stats := ac.GetRawStats()
+		aircraftAttempts += stats.TotalTxAttempts
+		aircraftCollisions += stats.TotalCollisions
+	}
+	for _, gcc := range dc.groundStations {
+		stats := gcc.GetRawStats()
+		groundAttempts += stats.TotalTxAttempts
+		groundCollisions += stats.TotalCollisions
+	}
+	var aircraftCollisionRate, groundCollisionRate float64
+	if aircraftAttempts > 0 {
+		aircraftCollisionRate = (float64(aircraftCollisions) / float64(aircraftAttempts)) * 100
+	}
+	if groundAttempts > 0 {
+		groundCollisionRate = (float64(groundCollisions) / float64(groundAttempts)) * 100
+	}
+	linkMode := "共享信道"
+	if config.EnableLinkSplit {
+		linkMode = "上下行分频"
+	}
+
+	airspaceStats := dc.airspace.GetRawStats()
+
+	rows := [][]interface{}{
+		{"指标", "值"},
+		{"SimTime (min)", simMinutes},
+		{"信道接入策略", dc.mediumAccess},
+		{"碰撞模型", config.CollisionModel},
+		{"时隙抖动 (时隙)", config.SlotJitter},
+		{"帧间间隔", config.InterFrameSpace.String()},
+		{"发送方数量", len(throughputs)},
+		{"公平性指数 (Jain)", fairness},
+		{"信道模式", linkMode},
+		{"飞机碰撞率 (%)", aircraftCollisionRate},
+		{"地面站碰撞率 (%)", groundCollisionRate},
+		{"分片重组成功率 (%)", reassemblyRate},
+		{"ACK合并窗口", config.AckCoalesceWindow.String()},
+		{"ACK发送方式", config.AckSendMode},
+		{"按地址投递报文", config.AddressedDelivery},
+		{"飞机接收机", config.AircraftReceiver},
+		{"时间缩放系数", simulation.TimeScale()},
+		{"地面站处理槽位 (0=不限)", config.GroundStationProcessingSlots},
+		{"ACK帧节省率 (%)", ackFrameSaving},
+		{"优先级老化步长", config.PriorityAgingStep.String()},
+		{"负载倍数", config.LoadMultiplier},
+		{"报文轨迹模式", config.TraceMode},
+		{"空域容量 (0=不限)", airspaceStats.Capacity},
+		{"最大同时活动航班", airspaceStats.PeakActive},
+		{"等待进入空域航班", airspaceStats.Held},
+		{"备降航班", airspaceStats.Diverted},
+		{"累计等待时间 (s)", airspaceStats.TotalHoldTime.Seconds()},
+	}
+	for _, priority := range []config.Priority{config.CriticalPriority, config.HighPriority, config.MediumPriority, config.LowPriority} {
+		rows = append(rows, []interface{}{fmt.Sprintf("最大等待 %s (ms)", priority), maxWait[priority].Milliseconds()})
+	}
+	// 所有飞机的端到端时延 (开始发送到收到 ACK) 分位数，尾部时延对 CRITICAL 报文尤为重要
+	for _, priority := range []config.Priority{config.CriticalPriority, config.HighPriority, config.MediumPriority, config.LowPriority} {
+		latency := simulation.FleetLatencyPercentiles(dc.aircrafts, priority)
+		rows = append(rows,
+			[]interface{}{fmt.Sprintf("时延P50 %s (ms)", priority), latency.P50.Milliseconds()},
+			[]interface{}{fmt.Sprintf("时延P95 %s (ms)", priority), latency.P95.Milliseconds()},
+			[]interface{}{fmt.Sprintf("时延P99 %s (ms)", priority), latency.P99.Milliseconds()},
+		)
+	}
+	// 故障风暴期间开始发送的报文时延：CRITICAL 时延应保持有界，低优先级报文被推迟
+	if storm := simulation.GetFaultStormStats(); !storm.Started.IsZero() {
+		rows = append(rows,
+			[]interface{}{"故障风暴触发 (min)", simulation.Unscaled(storm.Started.Sub(dc.startTime)).Minutes()},
+			[]interface{}{"故障风暴注入故障数", storm.Injected},
+		)
+		for _, priority := range []config.Priority{config.CriticalPriority, config.HighPriority, config.MediumPriority, config.LowPriority} {
+			latency := simulation.FleetStormLatencyPercentiles(dc.aircrafts, priority)
+			rows = append(rows,
+				[]interface{}{fmt.Sprintf("风暴期间送达 %s", priority), latency.Count},
+				[]interface{}{fmt.Sprintf("风暴期间时延P95 %s (ms)", priority), latency.P95.Milliseconds()},
+			)
+		}
+	}
+	// 地面站离线时段 (相对模拟开始的分钟数)，与地面站工作表中的本周期接收对照可观察恢复后的吞吐峰值
+	for _, gcc := range dc.groundStations {
+		for _, outage := range gcc.Outages() {
+			end := "未恢复"
+			if !outage.End.IsZero() {
+				end = fmt.Sprintf("%.1f", simulation.Unscaled(outage.End.Sub(dc.startTime)).Minutes())
+			}
+			rows = append(rows, []interface{}{fmt.Sprintf("离线时段 %s (min)", gcc.ID),
+				fmt.Sprintf("%.1f - %s", simulation.Unscaled(outage.Start.Sub(dc.startTime)).Minutes(), end)})
+		}
+	}
+	for i, rowData := range rows {
+		_ = f.SetSheetRow(sheet, fmt.Sprintf("A%d", i+1), &rowData)
+	}
+	log.Printf("📊 信道接入公平性指数 (Jain): %.4f", fairness)
+}
+
+// computeJainIndex 计算 Jain 公平性指数: (Σx)² / (n·Σx²)。
+// 结果在 [1/n, 1] 之间，1 表示所有发送方获得完全相同的吞吐量；没有任何吞吐量时返回 0。
+func computeJainIndex(values []float64) float64 {
+	var sum, sumSquares float64
+	for _, v := range values {
+		sum += v
+		sumSquares += v * v
+	}
+	if sumSquares == 0 {
+		return 0
+	}
+	return (sum * sum) / (float64(len(values)) * sumSquares)
+}
+
+// Err 返回最终保存 Excel 报告时遇到的错误 (此时数据已改存为 CSV，见 saveCSVFallback)。
+// 必须在 Run 结束 (其 WaitGroup 返回) 之后调用。
+func (dc *DataCollector) Err() error {
+	return dc.saveErr
+}
+
 // saveReport 负责创建目录并保存最终的Excel文件。
-func (dc *DataCollector) saveReport(f *excelize.File) {
+// 保存失败 (例如报告目录只读，或文件正被 Excel 打开) 时，将所有工作表改存为 CSV，避免丢失整次模拟的数据。
+func (dc *DataCollector) saveReport(f *excelize.File) error {
 	// 在保存文件之前，确保目标目录存在
 	reportDir := filepath.Dir(dc.filename)
-	if err := os.MkdirAll(reportDir, 0755); err != nil {
-		log.Printf("❌ 错误: 无法创建报告目录 '%s': %v", reportDir, err)
-		return
+	err := os.MkdirAll(reportDir, 0755)
+	if err != nil {
+		slog.Error("❌ 无法创建报告目录", "dir", reportDir, "err", err)
+	} else if err = f.SaveAs(dc.filename); err != nil {
+		slog.Error("❌ 无法保存 Excel 报告文件", "err", err)
 	}
+	if err == nil {
+		slog.Info("✅ 模拟数据报告已成功保存", "file", dc.filename)
+		return nil
+	}
+
+	csvDir, csvErr := saveCSVFallback(f)
+	if csvErr != nil {
+		slog.Error("❌ 无法将报告改存为 CSV，本次模拟数据已丢失", "err", csvErr)
+		return fmt.Errorf("保存 Excel 报告失败: %w (改存 CSV 也失败: %v)", err, csvErr)
+	}
+	slog.Warn("⚠️ Excel 报告保存失败，数据已改存为 CSV", "dir", csvDir)
+	return fmt.Errorf("保存 Excel 报告失败，数据已改存为 CSV (%s): %w", csvDir, err)
+}
 
-	// 保存文件
-	if err := f.SaveAs(dc.filename); err != nil {
-		log.Printf("❌ 错误: 无法保存 Excel 报告文件: %v", err)
-	} else {
-		log.Printf("✅ 模拟数据报告已成功保存到: %s", dc.filename)
+// saveCSVFallback 将每个工作表写入临时目录下的同名 CSV 文件，返回该目录。
+func saveCSVFallback(f *excelize.File) (string, error) {
+	dir, err := os.MkdirTemp("", "simulation_report_*")
+	if err != nil {
+		return "", err
+	}
+	for _, sheet := range f.GetSheetList() {
+		rows, err := f.GetRows(sheet)
+		if err != nil {
+			return dir, err
+		}
+		if err := writeCSV(filepath.Join(dir, sheet+".csv"), rows); err != nil {
+			return dir, err
+		}
+	}
+	return dir, nil
+}
+
+// writeCSV 将 rows 写入 path 处的 CSV 文件。
+func writeCSV(path string, rows [][]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(file)
+	if err := w.WriteAll(rows); err != nil {
+		file.Close()
+		return err
 	}
+	return file.Close()
 }
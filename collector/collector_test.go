@@ -0,0 +1,159 @@
+package collector
+
+import (
+	"Air-Simulator/simulation"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// newTestCollector 创建一个监控 aircraftCount 架飞机、一个信道与一个地面站的收集器，报告写入 filename。
+func newTestCollector(aircraftCount int, filename string) (*DataCollector, *sync.WaitGroup, chan struct{}) {
+	aircraftList := make([]*simulation.Aircraft, aircraftCount)
+	for i := range aircraftList {
+		aircraftList[i] = simulation.NewAircraft(fmt.Sprintf("A%05d", i), fmt.Sprintf("B-%d", i), "A320", "Airbus", fmt.Sprintf("SN%d", i), "CCA")
+		aircraftList[i].CurrentFlightID = fmt.Sprintf("CCA%d", 1000+i)
+	}
+	channel := simulation.NewChannel("Primary", nil, 0)
+	station := simulation.NewGroundControlCenter("GND", simulation.CoverageRegion{})
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	dc := NewDataCollector(&wg, done, aircraftList, []*simulation.Channel{channel}, []*simulation.GroundControlCenter{station},
+		simulation.NewAirspace(0, ""), nil, "test")
+	dc.filename = filename
+	return dc, &wg, done
+}
+
+// runCollector 运行收集器并立即结束模拟，等待最终报告保存完毕。
+func runCollector(dc *DataCollector, wg *sync.WaitGroup, done chan struct{}) {
+	wg.Add(1)
+	go dc.Run()
+	close(done)
+	wg.Wait()
+}
+
+// 5 架与 100 架飞机的模拟都生成与飞机数量相符的报告：飞机工作表每架飞机一行。
+func TestReportSizedByFleet(t *testing.T) {
+	for _, n := range []int{5, 100} {
+		filename := filepath.Join(t.TempDir(), "report", "simulation_report.xlsx")
+		dc, wg, done := newTestCollector(n, filename)
+		runCollector(dc, wg, done)
+		if err := dc.Err(); err != nil {
+			t.Fatalf("%d 架飞机: 保存报告失败: %v", n, err)
+		}
+
+		f, err := excelize.OpenFile(filename)
+		if err != nil {
+			t.Fatalf("%d 架飞机: 无法打开报告: %v", n, err)
+		}
+		rows, err := f.GetRows("Aircraft_Stats")
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != n+1 {
+			t.Errorf("%d 架飞机: 飞机工作表有 %d 行，期望表头加 %d 行", n, len(rows), n)
+		}
+	}
+}
+
+func TestComputeJainIndex(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"各发送方吞吐量相等", []float64{3, 3, 3, 3}, 1},
+		{"只有一个发送方有吞吐量", []float64{5, 0, 0, 0}, 0.25},
+		{"所有发送方吞吐量为零", []float64{0, 0, 0}, 0},
+		{"没有发送方", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := computeJainIndex(tt.values); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("computeJainIndex(%v) = %v, 期望 %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+// Excel 报告无法保存时 (报告目录只读，或目录路径被文件占用)，所有工作表改存为临时目录中的 CSV，且 Err 返回错误。
+func TestReportFallsBackToCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename func(t *testing.T) string
+	}{
+		{"报告目录只读", func(t *testing.T) string {
+			if os.Geteuid() == 0 {
+				t.Skip("root 用户不受目录权限限制")
+			}
+			dir := filepath.Join(t.TempDir(), "report")
+			if err := os.Mkdir(dir, 0555); err != nil {
+				t.Fatal(err)
+			}
+			return filepath.Join(dir, "simulation_report.xlsx")
+		}},
+		{"报告目录被文件占用", func(t *testing.T) string {
+			blocker := filepath.Join(t.TempDir(), "report")
+			if err := os.WriteFile(blocker, nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+			return filepath.Join(blocker, "simulation_report.xlsx")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := tt.filename(t)
+			t.Setenv("TMPDIR", t.TempDir()) // saveCSVFallback 在 os.TempDir() 下创建目录
+
+			dc, wg, done := newTestCollector(3, filename)
+			runCollector(dc, wg, done)
+			if dc.Err() == nil {
+				t.Fatal("Excel 报告保存失败时 Err 应返回错误")
+			}
+			if _, err := os.Stat(filename); err == nil {
+				t.Errorf("不应存在 Excel 报告 %s", filename)
+			}
+
+			dirs, err := filepath.Glob(filepath.Join(os.TempDir(), "simulation_report_*"))
+			if err != nil || len(dirs) != 1 {
+				t.Fatalf("期望一个 CSV 目录，找到 %v (err=%v)", dirs, err)
+			}
+			if !strings.Contains(dc.Err().Error(), dirs[0]) {
+				t.Errorf("错误信息 %q 未包含 CSV 目录 %s", dc.Err(), dirs[0])
+			}
+			for _, sheet := range []string{"Aircraft_Stats", "Channel_Stats", "GroundControl_Stats", "Summary"} {
+				data, err := os.ReadFile(filepath.Join(dirs[0], sheet+".csv"))
+				if err != nil {
+					t.Errorf("缺少工作表 %s 的 CSV: %v", sheet, err)
+				} else if len(data) == 0 {
+					t.Errorf("工作表 %s 的 CSV 为空", sheet)
+				}
+			}
+			rows, err := csv.NewReader(strings.NewReader(mustReadFile(t, filepath.Join(dirs[0], "Aircraft_Stats.csv")))).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != 4 {
+				t.Errorf("飞机工作表的 CSV 有 %d 行，期望表头加 3 行", len(rows))
+			}
+		})
+	}
+}
+
+// mustReadFile 读取 path 处的文件内容，失败时终止测试。
+func mustReadFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
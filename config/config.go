@@ -25,10 +25,49 @@ type PriorityPMap map[Priority]float64
 // false: 恢复为传统的单信道模式。
 const EnableBackupChannel = true
 
+// EnableCollisionDetection 控制信道是否启用碰撞检测 (CSMA/CD)。
+// true: 发生重叠碰撞的双方在发送阻塞信号后立即中止传输，提前释放信道。
+// false: 仅碰撞避免，重叠的传输都会完整地占用信道。
+const EnableCollisionDetection = false
+
+// EnableCriticalPreemption 控制 CRITICAL 报文是否可以抢占信道。
+// true: CRITICAL 报文 (ACK/NACK 除外) 遇到满载信道时，中止其上一个低优先级的进行中传输并立即占用腾出的容量，被中止的报文由其发送方重传。
+// false: CRITICAL 报文与其他报文一样等待信道空闲。
+const EnableCriticalPreemption = false
+
+// EnableHalfDuplex 控制飞机电台是否为半双工 (真实 VHF 电台即为半双工)。
+// true: 飞机在自己的报文占用信道期间收不到任何报文，此时到达的 ACK 会被错过，导致额外的重传。
+// false: 飞机在发送的同时也能接收。
+const EnableHalfDuplex = false
+
+// AircraftReceiver 选择飞机的接收机模型。
+// "multi" (默认): 每个信道各有一部接收机，飞机同时收听它注册的所有信道。
+// "tuned": 单部可调谐接收机，飞机只收听当前调谐的信道；在另一个信道上发出报文时调谐到该信道，
+// 调谐后的 ReceiverRetuneTime 内收不到任何报文。地面站仍在其他信道上回复的 ACK 会被错过。
+const AircraftReceiver = "multi"
+
+// ReceiverRetuneTime 是可调谐接收机切换信道后的稳定时间，期间收不到任何报文。
+const ReceiverRetuneTime = 50 * time.Millisecond
+
+// TimeScale 是全局时间缩放系数：模拟中的所有时长 (传输时间、时隙、报告间隔、飞行时长、超时等) 都乘以该系数后再实际等待。
+// 1 为实时运行；1.0 / 60 时 68 分钟的模拟约 68 秒跑完。各时长按同一比例缩放，报告中的时长与时间均换算回模拟时间。
+// 注意缩放后的时长过短时 (接近调度器精度)，goroutine 调度延迟在相对时序中的占比会变大。
+// 模拟启动时读取一次 (见 simulation.TimeScale)。
+const TimeScale = 1.0
+
 // ===================================================================
 //                       P-Persistence & Channel Switching
 // ===================================================================
 
+// MediumAccessScheme 选择所有发送方使用的信道接入策略。
+// 可选: "p-persistent-CSMA" (默认), "1-persistent-CSMA", "pure-ALOHA", "slotted-ALOHA"。
+// 时隙 ALOHA 以信道的 p 值作为每个时隙的传输概率。
+const MediumAccessScheme = "p-persistent-CSMA"
+
+// PriorityAgingStep 定义了优先级老化的步长：报文每等待一个步长，其有效优先级提升一级 (最高至 CRITICAL)，
+// 有效优先级用于选择信道和 p 值，避免低优先级报文被持续抢占。为 0 时关闭老化。
+const PriorityAgingStep time.Duration = 0
+
 // PrimaryPMap 定义了主信道的 p-坚持 概率。
 var PrimaryPMap = PriorityPMap{
 	CriticalPriority: 0.9,
@@ -47,6 +86,7 @@ var BackupPMap = PriorityPMap{
 }
 
 // SwitchoverProbs 定义了当主信道忙碌时，不同优先级的消息切换到备用信道的概率。
+// 每次发送只判断一次，不随等待的时隙数累积。
 var SwitchoverProbs = map[Priority]float64{
 	CriticalPriority: 1.0,  // 紧急报文: 100% 尝试切换
 	HighPriority:     0.8,  // 高优先级报文: 80% 尝试切换
@@ -54,6 +94,137 @@ var SwitchoverProbs = map[Priority]float64{
 	LowPriority:      0.05, // 低优先级报文: 几乎不切换
 }
 
+// ForcedSwitchoverBudget 定义了各优先级报文的“等待预算”：
+// 当发送方连续观察到主信道忙的次数达到该值时，无论切换概率如何都强制切换到备用信道，并留在备用信道上直到报文发出。
+// 值为 0 表示不启用强制切换，完全依赖切换概率。
+var ForcedSwitchoverBudget = map[Priority]int{
+	CriticalPriority: 2,
+	HighPriority:     4,
+	MediumPriority:   8,
+	LowPriority:      16,
+}
+
+// MessageTypePriorities 定义了飞机生成的各类报告使用的优先级 (键为报文类型)，
+// 使报文类别与优先级解耦，便于研究优先级分配对时延与成功率的影响。未列出的报文类型使用其默认优先级。
+var MessageTypePriorities = map[string]Priority{
+	"AIRCRAFT_FAULT":  CriticalPriority,
+	"ATC_MESSAGE":     CriticalPriority,
+	"OOOI_REPORT":     HighPriority,
+	"POSITION_REPORT": HighPriority,
+	"FUEL_REPORT":     HighPriority,
+	"ENGINE_REPORT":   MediumPriority,
+	"WEATHER_REPORT":  MediumPriority,
+	"WEATHER_REQUEST": MediumPriority,
+	"LINK_TEST":       LowPriority,
+}
+
+// MessageTypeTransmissionTimes 定义了各类报文的标称传输时间 (键为报文类型)，用于在不逐字节建模的情况下
+// 体现内容多少的差异，例如 "ENGINE_REPORT": 120 * time.Millisecond、"OOOI_REPORT": 40 * time.Millisecond。
+// 未列出的报文类型使用 TransmissionTime (设置了 LinkBitRate 时按报文长度计算)；TransmissionTimeDistribution 的波动叠加在标称传输时间之上。
+var MessageTypeTransmissionTimes = map[string]time.Duration{}
+
+// ===================================================================
+//                           地面站与空域
+// ===================================================================
+
+// GroundStationSpec 描述一个地面站的部署位置与覆盖半径。
+type GroundStationSpec struct {
+	ID        string
+	Latitude  float64
+	Longitude float64
+	RadiusKM  float64 // 覆盖半径 (公里)，<=0 表示不限覆盖范围
+}
+
+// GroundStations 定义了模拟中部署的所有地面站。
+// 各地面站的覆盖区域互不重叠，飞机在飞行过程中会从一个地面站的覆盖区移交到另一个。
+var GroundStations = []GroundStationSpec{
+	{ID: "GND_CTL_MAIN", Latitude: 39.9, Longitude: 116.3, RadiusKM: 200},
+	{ID: "GND_CTL_NE", Latitude: 42.6, Longitude: 119.9, RadiusKM: 200},
+}
+
+// DeadZoneSpec 描述一个通信盲区 (例如地形遮挡或天线覆盖缺口) 的位置与半径。
+type DeadZoneSpec struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKM  float64
+}
+
+// DeadZones 定义了空域中的通信盲区。飞机位于盲区内时发出的帧照常占用信道，但到不了任何地面站 (视同丢失)，
+// 报文在飞机离开盲区前只能不断重传。为空时没有盲区。
+var DeadZones = []DeadZoneSpec{}
+
+// EnableSectors 控制是否将空域划分为扇区，每个地面站负责一个扇区并拥有独立的主/备信道 (不同频率)。
+// 飞机属于距其最近的地面站所在的扇区，使用该扇区的信道发送报文，跨越扇区时切换信道。
+// false: 所有地面站与飞机共用同一组主/备信道。
+const EnableSectors = false
+
+// EnableLinkSplit 控制上行与下行是否使用不同频率的信道。
+// true: 飞机只在下行信道 (Downlink) 上发送，地面站的 ACK 与上行报文只在上行信道 (Uplink) 上发送，两个方向互不争用；
+// 此时不使用备用信道，也不能与扇区同时启用。
+// false: 飞机与地面站共用主/备信道。
+const EnableLinkSplit = false
+
+// EnableGuardChannel 控制是否启用保护 (应急) 信道。保护信道只供 CRITICAL 报文使用：
+// 发送方的常规信道忙时 CRITICAL 报文直接改用保护信道，不受切换概率影响；其他优先级的报文永远不会使用它。
+// 与是否启用备用信道、上下行分频无关。
+const EnableGuardChannel = false
+
+const (
+	// AirportLatitude / AirportLongitude 定义了模拟机场的位置，离港飞机由此出发，进港飞机飞向此处。
+	AirportLatitude  = 39.9
+	AirportLongitude = 116.3
+
+	// CruiseSpeedKnots 定义了飞机在空域内的平均地速 (节)。
+	CruiseSpeedKnots = 450.0
+
+	// CruiseAltitudeFt 定义了飞机在空域内的巡航高度 (英尺)。
+	CruiseAltitudeFt = 35000.0
+
+	// ArrivalEntryDistanceKM 定义了进港飞机进入空域时距机场的距离 (公里)。
+	ArrivalEntryDistanceKM = 420.0
+)
+
+const (
+	// GroundStationOutageStation 指定模拟中途离线的地面站ID，用于研究地面站故障时的重传与恢复。空字符串表示不模拟离线。
+	GroundStationOutageStation = ""
+
+	// GroundStationOutageStart / GroundStationOutageDuration 定义了离线开始的时间 (相对模拟开始) 与持续时长。
+	GroundStationOutageStart    = 20 * time.Minute
+	GroundStationOutageDuration = 5 * time.Minute
+
+	// GroundStationOutagePolicy 定义了离线期间对收到的报文的处理策略:
+	// "buffer" (缓存，恢复在线后补处理并回复 ACK，默认) 或 "drop" (丢弃，由发送方重传)。
+	GroundStationOutagePolicy = "buffer"
+
+	// GroundStationOutageBufferSize 定义了 "buffer" 策略下最多缓存的报文数，超出部分被丢弃。
+	GroundStationOutageBufferSize = 200
+)
+
+// ===================================================================
+//                           无线电链路
+// ===================================================================
+
+const (
+	// AircraftTxPowerDBm / GroundStationTxPowerDBm 定义了飞机与地面站的默认发射功率 (dBm)，
+	// 例如 44 dBm 约为 25 W 的机载 VHF 电台。可在创建后按飞机/地面站单独修改。
+	AircraftTxPowerDBm      = 44.0
+	GroundStationTxPowerDBm = 47.0
+
+	// AircraftLinkQuality 定义了飞机的默认链路质量系数 (0, 1]，反映天线与机载设备安装的优劣，可按机型或按飞机单独修改。
+	// 每个下行帧额外以 1 - 链路质量 的概率在传输中损坏 (叠加在信道条件造成的误帧之上)，地面站因校验失败不回复 ACK，
+	// 因此链路质量较差的飞机即使在相同流量下也会经历更多重传。1.0 表示不额外损坏。
+	AircraftLinkQuality = 1.0
+
+	// CarrierFrequencyMHz 定义了计算自由空间路径损耗所用的载波频率 (MHz)。
+	CarrierFrequencyMHz = 131.55
+
+	// ReceiverSensitivityDBm 定义了接收机的灵敏度，接收功率低于此值的帧必定无法解调。
+	ReceiverSensitivityDBm = -100.0
+
+	// LinkFadeMarginDB 定义了衰落余量：接收功率高出灵敏度不足该值时，帧按余量线性增加的概率因信号过弱而丢失。
+	LinkFadeMarginDB = 10.0
+)
+
 // ===================================================================
 //                           通信参数
 // ===================================================================
@@ -64,24 +235,150 @@ const (
 	PrimaryTimeSlot = 320 * time.Millisecond
 	BackupTimeSlot  = 320 * time.Millisecond
 
-	// TransmissionTime 定义了发送一个标准ACARS报文所需的物理时间。
+	// SlotJitter 定义了发送方在时隙间等待时附加的随机抖动，以时隙长度为单位：
+	// 每次等待 waitSlots 个时隙后再额外等待 uniform[0, SlotJitter) 个时隙 (1.0 即在 [时隙, 2×时隙) 内均匀分布)，
+	// 使退避中的发送方不在同一时隙边界同时重试而形成同步碰撞。为 0 时所有发送方严格按时隙边界同步。
+	// 抖动序列由 FlightPlanSeed、发送方与报文ID决定，同一配置下每次运行相同。
+	SlotJitter = 0.0
+
+	// TransmissionTime 定义了发送一个标准ACARS报文所需的物理时间 (可按报文类型覆盖，见 MessageTypeTransmissionTimes)。
 	TransmissionTime = 80 * time.Millisecond
 
-	// AckTimeout 定义了发送方等待一个ACK报文的最大超时时间。
+	// TransmissionTimeDistribution 定义了每个报文传输时间的分布，用于模拟报文长度的差异:
+	// "fixed" (始终为 TransmissionTime)、"uniform" (TransmissionTime ± TransmissionTimeJitter 均匀分布)、
+	// "normal" (以 TransmissionTime 为均值、TransmissionTimeJitter 为标准差的正态分布)。
+	TransmissionTimeDistribution = "uniform"
+
+	// TransmissionTimeJitter 定义了传输时间的波动范围，0 表示固定传输时间。
+	TransmissionTimeJitter = 0 * time.Millisecond
+
+	// AckTimeout 定义了发送方等待一个ACK报文的基础超时时间，实际超时还会按优先级、信道与距离调整 (见下方的倍数配置)。
 	AckTimeout = 3 * time.Second // 增加了一些余量
 
+	// AckTimeoutBackupChannelFactor 定义了经备用信道发送的报文的 ACK 超时倍数 (备用链路通常时延更长)。
+	AckTimeoutBackupChannelFactor = 1.0
+
+	// AckTimeoutPerKM 定义了发送方与其服务地面站之间每公里距离附加的 ACK 超时，0 表示超时与距离无关。
+	AckTimeoutPerKM = 0 * time.Millisecond
+
 	// MaxRetries 定义了一个报文在因超时或碰撞失败后，允许的最大重传次数。
 	MaxRetries = 16
 
 	// ProcessingDelay 模拟地面站或飞机处理接收到的报文所需的时间。
 	ProcessingDelay = 200 * time.Millisecond
+
+	// GroundStationProcessingSlots 定义了每个地面站可同时处理的报文数，0 表示不限制。
+	// 处理槽位已满时地面站暂停从收件箱取报文，后续报文在收件箱中排队，收件箱满后按 ListenerOverflowPolicy 处理，
+	// 用于模拟地面站在突发流量下无法立即应答所有报文的情况。
+	GroundStationProcessingSlots = 0
+
+	// GroundStationInboxSize 定义了地面站收件箱 (等待处理的报文队列) 的容量。
+	GroundStationInboxSize = 50
+
+	// AckCoalesceWindow 定义了地面站合并 ACK 的等待窗口：窗口内待发送的多个 ACK 合并为一个累积 ACK 帧，
+	// 以减少地面站对信道的占用。累积 ACK 帧同样受 MaxPayloadBytes 限制，放不下的 ACK 拆到下一帧。为 0 时每个 ACK 单独成帧。
+	AckCoalesceWindow = 0 * time.Millisecond
+
+	// AckSendMode 定义了地面站发送 ACK (包括 NACK 与累积 ACK 帧) 的方式:
+	// "contend" (与数据报文一样经过信道接入、占用信道，可能等待与碰撞，默认)、
+	// "immediate" (处理完毕后立即送达，不经信道接入也不占用信道，模拟专用的 ACK 上行链路；仍受信道条件影响)。
+	// 两种方式下飞机的 ACK 超时计算相同。
+	AckSendMode = "contend"
+
+	// DATISBroadcastInterval 定义了每个地面站广播 D-ATIS 的间隔，0 表示不广播。
+	DATISBroadcastInterval = 15 * time.Minute
+
+	// DuplicateCacheSize 定义了每个地面站记录的最近报文ID数量，用于识别重传造成的重复报文。
+	DuplicateCacheSize = 256
+
+	// ListenerOverflowPolicy 定义了接收方收件箱已满时的处理策略:
+	// "drop-new" (丢弃新报文，默认)、"drop-oldest" (淘汰最早的报文)、"block" (阻塞等待，超时后丢弃新报文)。
+	ListenerOverflowPolicy = "drop-new"
+
+	// ListenerBlockTimeout 定义了 "block" 策略下等待收件箱出现空位的最长时间。
+	ListenerBlockTimeout = 50 * time.Millisecond
+
+	// AddressedDelivery 为 true 时信道按地址投递报文：飞机发出的报文只投递给地面站，
+	// 地面站发出的报文只投递给其收件飞机，只有广播报文 (D-ATIS) 投递给所有监听者。
+	// 为 false 时信道上的每个报文投递给所有监听者，由接收方自行过滤。
+	AddressedDelivery = false
+
+	// MaxOutboundQueueLength 定义了每个发送方 (飞机或地面站) 同时处于发送流程 (竞争信道或等待 ACK) 的报文上限，0 表示不限制。
+	// 持续过载时限制积压的报文数，使过载表现为明确的丢弃，而不是积压无限增长。
+	MaxOutboundQueueLength = 0
+
+	// OutboundQueueOverflowPolicy 定义了发送中的报文已达上限时新报文的处理策略:
+	// "reject-new" (拒绝新报文，默认)、"drop-lowest" (放弃发送中优先级最低的报文为新报文腾出位置，新报文优先级不高于它时仍拒绝新报文)。
+	// 被拒绝或被放弃的报文记为死信 (QUEUE_OVERFLOW)。
+	OutboundQueueOverflowPolicy = "reject-new"
+
+	// CollisionWindow 定义了传输开始后的易受碰撞时间窗口 (载波侦听延迟)。
+	// 在此窗口内另一发送方仍会认为信道空闲而开始发送，造成两次传输重叠。为 0 时不模拟重叠碰撞。
+	CollisionWindow = 0 * time.Millisecond
+
+	// CollisionModel 定义了满载信道上的传输尝试何时与正在进行的传输 (长度为 T) 重叠碰撞:
+	// "busy-at-start" (只在传输开始后的 CollisionWindow 内重叠，默认)、
+	// "unslotted-aloha" (传输全程都会被重叠，易受碰撞窗口 2T)、"slotted-aloha" (时隙等于标准帧长 TransmissionTime，传输在时隙起点开始，只与同一时隙内开始的尝试重叠，易受碰撞窗口 1T)。
+	// 窗口外的尝试视为侦听到信道忙而放弃。ALOHA 模型宜与对应的 ALOHA 接入策略搭配使用。
+	CollisionModel = "busy-at-start"
+
+	// InterFrameSpace 定义了帧间间隔 (先听后说的保护时间)：信道必须持续空闲至少该时长，发送方才视其为空闲并允许传输，
+	// 避免多个等待者在一帧刚结束的瞬间同时抢占信道。为 0 时只检查信道此刻是否空闲。
+	InterFrameSpace = 0 * time.Millisecond
+
+	// JamTime 定义了启用碰撞检测时，检测到碰撞后发送阻塞信号的时长。
+	JamTime = 10 * time.Millisecond
+
+	// ChannelCapacity 定义了每个信道可同时承载的独立传输数 (例如宽带链路上的子信道数)。
+	// 为 1 时即传统的单报文信道；只有全部子信道都被占用时，信道才被视为忙碌。
+	ChannelCapacity = 1
+
+	// MaxPayloadBytes 定义了单个 ACARS 报文块中数据部分的最大长度 (字节)。
+	// 超出该长度的报文需要分片发送，由接收方重组。
+	MaxPayloadBytes = 220
+
+	// FrameHeaderBytes 定义了每个 ACARS 帧固定的头部开销 (字节)：模式、地址、标签、块控制、校验等，
+	// 计入报文在空中占用的字节数，使很短的报文 (例如 ACK) 也占用合理的最短传输时间。
+	FrameHeaderBytes = 30
+
+	// LinkBitRate 定义了按报文长度计算传输时间时的链路速率 (bit/s，VHF ACARS 为 2400)。
+	// 大于 0 时，未在 MessageTypeTransmissionTimes 中列出的报文的标称传输时间为 (FrameHeaderBytes + 数据长度) × 8 / LinkBitRate；
+	// 为 0 时不按长度计算，使用 TransmissionTime。
+	LinkBitRate = 0
+
+	// LatencySampleSize 定义了每架飞机为每个优先级保留的端到端时延 (开始发送到收到 ACK) 样本数。
+	// 样本超出该数目后以蓄水池抽样替换，内存有界，报告中的 P50/P95/P99 为近似值。
+	LatencySampleSize = 1024
 )
 
+// AckTimeoutPriorityFactors 定义了各优先级报文的 ACK 超时倍数，未列出的优先级按 1.0 处理。
+// 较小的倍数使紧急报文更快地判定丢失并重传。
+var AckTimeoutPriorityFactors = map[Priority]float64{
+	CriticalPriority: 1.0,
+	HighPriority:     1.0,
+	MediumPriority:   1.0,
+	LowPriority:      1.0,
+}
+
 // ===================================================================
 //                           飞行计划参数
 // ===================================================================
 
 const (
+	// NumAircraft 定义了模拟中的飞机数量。
+	// 0 表示使用 simulation_plan.go 中脚本化的飞行计划，其他值则按 FlightPlanSeed 随机生成相应数量的飞行计划。
+	NumAircraft = 0
+
+	// FlightPlanSeed 是随机生成飞行计划时使用的随机种子，相同的种子会生成相同的计划。
+	FlightPlanSeed int64 = 20250725
+
+	// FlightPlanWindowMinutes 定义了随机生成的飞行计划的开始时间范围 [1, FlightPlanWindowMinutes] (分钟)。
+	FlightPlanWindowMinutes = 30
+
+	// StartTimeJitter 定义了飞行计划开始时间的随机抖动：每个计划的开始时间在 ± StartTimeJitter 内均匀偏移 (不早于模拟开始)，
+	// 在保持整体交通结构的同时改变到达时刻，用于蒙特卡洛研究。抖动由 FlightPlanSeed 决定，相同的种子可复现。0 表示不抖动。
+	StartTimeJitter = 0 * time.Minute
+
 	// FlightDuration 定义了每个飞行计划中，飞机在空域内活动的总时长。
 	FlightDuration = 30 * time.Minute
 
@@ -96,4 +393,190 @@ const (
 
 	// WeatherReportInterval 定义了气象数据报告的发送间隔。
 	WeatherReportInterval = 8 * time.Minute
+
+	// WeatherRequests 为 true 时，飞机的气象报文改为向地面站请求气象信息 (WEATHER_REQUEST)，
+	// 地面站收到后合成 METAR 并按请求报文ID关联、以 WEATHER_REPORT 上行回复请求方，形成有请求的上行流量。
+	WeatherRequests = false
+
+	// MaxConcurrentFlights 定义了空域内同时活动航班数的上限，0 表示不限制。
+	MaxConcurrentFlights = 0
+
+	// AirspaceAdmissionPolicy 定义了空域满容量时进港航班的处理策略: "hold" (盘旋等待) 或 "divert" (备降)。
+	// 离港航班总是在地面等待。
+	AirspaceAdmissionPolicy = "hold"
+
+	// TrafficModel 选择交通模型: "scripted" 使用固定的飞行计划 (见 NumAircraft)，
+	// "poisson" 按泊松过程随机生成飞机到达时刻和飞行时长 (飞机数量由到达过程决定)。
+	TrafficModel = "scripted"
+
+	// PoissonArrivalRatePerMinute 是泊松交通模型中平均每分钟进入空域的飞机数 (会乘以 LoadMultiplier)。
+	PoissonArrivalRatePerMinute = 0.5
+
+	// PoissonArrivalWindow 是泊松交通模型生成到达的时间范围。
+	PoissonArrivalWindow = 30 * time.Minute
+
+	// PoissonFlightDurationMin / Max 定义了泊松交通模型中每架飞机在空域内飞行时长的均匀分布范围。
+	PoissonFlightDurationMin = 15 * time.Minute
+	PoissonFlightDurationMax = 45 * time.Minute
+
+	// TrafficGenerator 选择例行报告 (位置、燃油、气象) 的流量生成器，平均速率均由报告间隔决定:
+	// "periodic" (各类报告按各自的间隔周期发送，默认)、"uniform" (报告间隔在 0 到两倍平均间隔之间均匀分布)、
+	// "bursty" (每次连续发送 TrafficBurstSize 份报告，然后静默)。需要按记录的轨迹发送时使用 TraceMode = "replay"。
+	TrafficGenerator = "periodic"
+
+	// TrafficBurstSize / TrafficBurstSpacing 定义了 "bursty" 生成器每串报告的份数以及串内相邻报告的间隔。
+	TrafficBurstSize    = 5
+	TrafficBurstSpacing = 2 * time.Second
+
+	// LinkTestInterval 定义了飞机在空域内发起 ACARS 链路测试的间隔。
+	LinkTestInterval = 6 * time.Minute
+
+	// LoadMultiplier 是报文生成速率的倍数：所有例行报告间隔都除以该值 (2.0 表示两倍流量)，用于对信道接入层进行压力测试。
+	LoadMultiplier = 1.0
+
+	// ScaleFleetWithLoad 为 true 时，飞机数量也按 LoadMultiplier 等比例缩放 (使用随机生成的飞行计划)。
+	ScaleFleetWithLoad = false
+
+	// ReportIntervalJitter 定义了例行报告 (位置、燃油、气象、链路测试) 间隔的随机抖动范围，
+	// 每次的实际间隔为 interval ± uniform(ReportIntervalJitter)，避免各飞机的报告同步形成争用高峰。为 0 时不抖动。
+	// 抖动与报告间隔一样按 LoadMultiplier 缩放，保证平均报告速率与负载倍数成正比。
+	ReportIntervalJitter = 30 * time.Second
+
+	// FaultProbabilityPerFlight 定义了每个航班在空域内飞行期间发生一次系统故障的概率，
+	// 故障发生时飞机发送一份 CRITICAL 故障报告。0 表示不模拟故障。
+	FaultProbabilityPerFlight = 0.0
+
+	// FaultEscalationLimit 定义了 CRITICAL 故障报告达到最大重传次数成为死信后，升级并重新上报的最大次数。
+	FaultEscalationLimit = 2
+
+	// FaultStormAt 定义了“故障风暴”场景的触发时刻 (从模拟开始计算)，0 表示不启用。
+	// 触发时处于巡航阶段的飞机中约 FaultStormFraction 比例同时各发送 FaultStormFaultsPerAircraft 份 CRITICAL 故障报告，
+	// 用于检验优先级机制在突发高峰下能否保护关键报文；报告中单独统计风暴开始后 FaultStormWindow 内开始发送的报文时延。
+	FaultStormAt                = 0 * time.Minute
+	FaultStormFraction          = 0.5
+	FaultStormFaultsPerAircraft = 3
+	FaultStormWindow            = 2 * time.Minute
+
+	// RunTermination 定义了模拟运行何时结束 (之后进入收尾阶段并保存报告):
+	// "flights" (所有飞行计划完成，默认)、"sim-minutes" (运行 RunTerminationMinutes 分钟模拟时间后，按 TimeScale 缩放)、
+	// "message-count" (所有飞机共成功送达 RunTerminationMessages 个报文后)。提前结束时尚未完成的飞行计划被停止。
+	RunTermination = "flights"
+
+	// RunTerminationMinutes 是 "sim-minutes" 终止条件下的运行时长 (模拟分钟)。
+	RunTerminationMinutes = 30
+
+	// RunTerminationMessages 是 "message-count" 终止条件下需要送达的报文数。
+	RunTerminationMessages = 1000
+)
+
+// ===================================================================
+//                           机型配置
+// ===================================================================
+
+// AircraftProfile 描述一种机型的通信相关特征：发动机数量、例行报告间隔以及典型燃油消耗。
+type AircraftProfile struct {
+	Name                  string        // 配置名称，写入报告
+	AircraftType          string        // 飞机型号
+	Manufacturer          string        // 制造商
+	EngineCount           int           // 发动机数量，引擎报告会逐台发送
+	PosReportInterval     time.Duration // 例行位置报告间隔
+	FuelReportInterval    time.Duration // 燃油状态报告间隔
+	WeatherReportInterval time.Duration // 气象数据报告间隔
+	NominalFuelFlowKGPH   float64       // 巡航时的典型总燃油流量 (公斤/小时)
+	InitialFuelKG         float64       // 进入空域时的燃油量 (公斤)
+	LinkQuality           float64       // 链路质量系数 (0, 1]，0 表示使用 AircraftLinkQuality
+}
+
+// AircraftProfiles 定义了可用的机型配置，飞机按编号依次轮流分配。
+var AircraftProfiles = []AircraftProfile{
+	{
+		Name: "窄体双发", AircraftType: "A320neo", Manufacturer: "Airbus", EngineCount: 2,
+		PosReportInterval: PosReportInterval, FuelReportInterval: FuelReportInterval, WeatherReportInterval: WeatherReportInterval,
+		NominalFuelFlowKGPH: 2400, InitialFuelKG: 12000,
+	},
+	{
+		Name: "宽体双发", AircraftType: "B787-9", Manufacturer: "Boeing", EngineCount: 2,
+		PosReportInterval: 4 * time.Minute, FuelReportInterval: 8 * time.Minute, WeatherReportInterval: 8 * time.Minute,
+		NominalFuelFlowKGPH: 5600, InitialFuelKG: 60000,
+	},
+	{
+		Name: "宽体四发", AircraftType: "A380-800", Manufacturer: "Airbus", EngineCount: 4,
+		PosReportInterval: 4 * time.Minute, FuelReportInterval: 6 * time.Minute, WeatherReportInterval: 6 * time.Minute,
+		NominalFuelFlowKGPH: 11000, InitialFuelKG: 150000,
+	},
+}
+
+// ===================================================================
+//                           信道条件 (天气)
+// ===================================================================
+
+// EnableWeatherDegradation 控制是否模拟天气对信道质量的影响。
+// false: 信道始终处于理想状态 (无误帧、标称速率)。
+const EnableWeatherDegradation = false
+
+const (
+	// BaseFrameErrorRate 晴好天气下的误帧率
+	BaseFrameErrorRate = 0.0
+	// StormPeakFrameErrorRate 风暴峰值时的误帧率
+	StormPeakFrameErrorRate = 0.2
+	// StormPeakDataRateFactor 风暴峰值时的有效数据速率系数 (0.5 表示速率减半)
+	StormPeakDataRateFactor = 0.5
+	// StormCenter 风暴峰值相对模拟开始的时间
+	StormCenter = 35 * time.Minute
+	// StormWidth 风暴影响范围 (高斯曲线的标准差)
+	StormWidth = 8 * time.Minute
+	// ConditionsUpdateInterval 信道条件的更新间隔
+	ConditionsUpdateInterval = 30 * time.Second
+)
+
+// ===================================================================
+//                           报文轨迹 (记录与回放)
+// ===================================================================
+
+const (
+	// TraceMode 控制报文轨迹: "" (关闭，默认)、"record" (记录飞机生成的每一份报告及其时刻)、
+	// "replay" (按记录的时刻回放报告，替代飞行过程中生成的报告，用于在相同流量下比较不同的信道接入策略)。
+	TraceMode = ""
+
+	// TracePath 定义了报文轨迹文件的路径 (JSON Lines 格式)。
+	TracePath = "report/message_trace.jsonl"
+
+	// MessageEventLogPath 定义了报文事件日志的路径 (JSON Lines 格式)，为空时不记录。
+	// 日志按发生顺序记录每个报文的入队、传输、碰撞、抢占、送达、确认与丢弃事件及其时刻，供离线分析。
+	MessageEventLogPath = ""
+)
+
+// ===================================================================
+//                           日志
+// ===================================================================
+
+// LogLevel 控制日志输出级别: "DEBUG" (包含逐时隙的信道接入细节)、"INFO" (默认)、"WARN"、"ERROR"。
+const LogLevel = "INFO"
+
+// ===================================================================
+//                           调试: 状态快照
+// ===================================================================
+
+const (
+	// SnapshotAfter 定义了模拟开始后多久捕获一次完整的模拟状态快照，0 表示不捕获。
+	SnapshotAfter time.Duration = 0
+
+	// RestoreStatePath 指定启动时需要恢复的快照文件路径，空字符串表示从零开始。
+	RestoreStatePath = ""
+)
+
+// ===================================================================
+//                           调试: 终端仪表盘
+// ===================================================================
+
+const (
+	// DashboardRefreshInterval 定义了终端仪表盘 (--tui) 的刷新间隔。
+	DashboardRefreshInterval = 250 * time.Millisecond
+
+	// DashboardEventLogLines 定义了仪表盘底部滚动事件日志保留的行数。
+	DashboardEventLogLines = 12
+
+	// WindowedStatsCapacity 定义了每个飞机、地面站和信道为滑动窗口统计 (GetWindowedStats) 保留的最近事件数。
+	// 窗口内的事件多于此数时，最早的事件被覆盖，统计结果会标记为不完整。
+	WindowedStatsCapacity = 4096
 )
@@ -0,0 +1,183 @@
+// Package dashboard 提供一个基于 ANSI 转义序列的终端仪表盘，用于演示和调试时实时观察模拟状态。
+package dashboard
+
+import (
+	"Air-Simulator/simulation"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	barWidth        = 30  // 信道占用率条的宽度 (字符)
+	maxAircraftRows = 15  // 飞机表格最多显示的行数
+	maxLineRunes    = 140 // 超出此长度的行会被截断，避免终端自动换行打乱画面
+	busySmoothing   = 0.3 // 占用率的指数平滑系数，越大越灵敏
+)
+
+// Dashboard 周期性地读取各实体的 GetRawStats 并重绘整个终端画面。
+// 它只通过各实体公开的只读统计接口取数，不持有任何模拟内部的锁。
+type Dashboard struct {
+	out      io.Writer
+	interval time.Duration
+
+	channels       []*simulation.Channel
+	aircraft       []*simulation.Aircraft
+	groundStations []*simulation.GroundControlCenter
+	airspace       *simulation.Airspace
+	events         *EventLog
+
+	startTime   time.Time
+	lastSample  time.Time
+	lastBusy    map[string]time.Duration
+	utilization map[string]float64
+}
+
+// New 创建一个仪表盘。channels 中的 nil 项 (单信道模式下的备用信道) 会被忽略；
+// airspace 与 events 可以为 nil，此时不显示对应的区块。
+func New(out io.Writer, interval time.Duration, channels []*simulation.Channel, aircraft []*simulation.Aircraft,
+	groundStations []*simulation.GroundControlCenter, airspace *simulation.Airspace, events *EventLog) *Dashboard {
+	var present []*simulation.Channel
+	for _, ch := range channels {
+		if ch != nil {
+			present = append(present, ch)
+		}
+	}
+	return &Dashboard{
+		out:            out,
+		interval:       interval,
+		channels:       present,
+		aircraft:       aircraft,
+		groundStations: groundStations,
+		airspace:       airspace,
+		events:         events,
+		lastBusy:       make(map[string]time.Duration),
+		utilization:    make(map[string]float64),
+	}
+}
+
+// Run 按刷新间隔重绘画面，直到 done 被关闭；退出前绘制最后一帧并恢复光标。
+func (d *Dashboard) Run(done <-chan struct{}) {
+	d.startTime = time.Now()
+	d.lastSample = d.startTime
+	fmt.Fprint(d.out, "\x1b[?25l\x1b[2J") // 隐藏光标并清屏
+	defer fmt.Fprint(d.out, "\x1b[?25h\n")
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		d.render()
+		select {
+		case <-ticker.C:
+		case <-done:
+			d.render()
+			return
+		}
+	}
+}
+
+// render 采样一次统计数据，在内存中拼好整帧后一次性写出，以减少闪烁。
+func (d *Dashboard) render() {
+	now := time.Now()
+	elapsed := simulation.Unscaled(now.Sub(d.lastSample))
+	d.lastSample = now
+
+	var frame bytes.Buffer
+	line := func(format string, args ...any) {
+		text := []rune(fmt.Sprintf(format, args...))
+		if len(text) > maxLineRunes {
+			text = text[:maxLineRunes]
+		}
+		frame.WriteString(string(text))
+		frame.WriteString("\x1b[0m\x1b[K\n") // 复位样式 (截断可能切掉复位序列) 并清除本行残留的旧内容
+	}
+
+	frame.WriteString("\x1b[H")
+	line("\x1b[1m======  Air-Ground Communication Simulation  ======\x1b[0m   已运行 %v", simulation.Unscaled(now.Sub(d.startTime)).Truncate(time.Second))
+	line("")
+
+	line("\x1b[1m信道\x1b[0m")
+	for _, ch := range d.channels {
+		stats := ch.GetRawStats()
+		utilization := d.sampleUtilization(ch.ID, stats.OccupancyTime/time.Duration(stats.Capacity), elapsed)
+		state := "\x1b[32m○ 空闲\x1b[0m"
+		if ch.IsBusy() {
+			state = "\x1b[31m● 忙碌\x1b[0m"
+		}
+		line("  %-8s [%s] %5.1f%%  %s  占用 %d/%d  已传输 %-6d 重叠碰撞 %-5d 误帧 %-5d 速率系数 %.2f",
+			ch.ID, bar(utilization), utilization*100, state, ch.Occupancy(), stats.Capacity,
+			stats.TotalMessagesTransmitted, stats.OverlapCollisions, stats.FramesCorrupted, stats.DataRateFactor)
+	}
+	line("")
+
+	if d.airspace != nil {
+		stats := d.airspace.GetRawStats()
+		capacity := "不限"
+		if stats.Capacity > 0 {
+			capacity = fmt.Sprintf("%d", stats.Capacity)
+		}
+		line("\x1b[1m空域\x1b[0m  活跃 %d / %s   峰值 %d   等待 %d   转场 %d", stats.Active, capacity, stats.PeakActive, stats.Held, stats.Diverted)
+		line("")
+	}
+
+	line("\x1b[1m飞机\x1b[0m")
+	line("  %-10s %-16s %6s %6s %6s %6s %6s %6s %6s", "航班号", "机型", "收件箱", "成功", "尝试", "碰撞", "重传", "失败", "溢出")
+	aircraft := append([]*simulation.Aircraft(nil), d.aircraft...)
+	sort.Slice(aircraft, func(i, j int) bool { return aircraft[i].CurrentFlightID < aircraft[j].CurrentFlightID })
+	for i, ac := range aircraft {
+		if i == maxAircraftRows {
+			line("  ... 另有 %d 架飞机未显示", len(aircraft)-maxAircraftRows)
+			break
+		}
+		stats := ac.GetRawStats()
+		line("  %-10s %-16s %6d %6d %6d %6d %6d %6d %6d", ac.CurrentFlightID, ac.Profile.Name, stats.InboxDepth,
+			stats.SuccessfulTx, stats.TotalTxAttempts, stats.TotalCollisions, stats.TotalRetries, stats.PermanentFailures, stats.ListenerDrops)
+	}
+	line("")
+
+	line("\x1b[1m地面站\x1b[0m")
+	for _, gcc := range d.groundStations {
+		stats := gcc.GetRawStats()
+		state := "\x1b[32m在线\x1b[0m"
+		if !stats.Available {
+			state = fmt.Sprintf("\x1b[31m离线\x1b[0m (缓存 %d 丢弃 %d)", stats.OutageBuffered, stats.OutageDropped)
+		}
+		line("  %-10s %s 收件箱 %-4d 接收 %-6d 发送 %-6d 碰撞 %-5d 重复 %-5d 移交 %-4d 溢出 %d", gcc.ID, state, stats.InboxDepth,
+			stats.TotalReceived, stats.SuccessfulTx, stats.TotalCollisions, stats.DuplicatesReceived, stats.HandoversIn, stats.ListenerDrops)
+	}
+
+	if d.events != nil {
+		line("")
+		line("\x1b[1m事件日志\x1b[0m")
+		for _, event := range d.events.Lines() {
+			line("  %s", event)
+		}
+	}
+	frame.WriteString("\x1b[J") // 清除画面下方残留的旧内容
+
+	d.out.Write(frame.Bytes())
+}
+
+// sampleUtilization 根据两次采样之间信道占用时间 (已按容量归一化) 的增量估算占用率，并做指数平滑。
+// 占用时间在一次传输结束时才会累加，平滑可以避免占用率条随传输边界剧烈跳动。
+func (d *Dashboard) sampleUtilization(channelID string, busy time.Duration, elapsed time.Duration) float64 {
+	previous, seen := d.lastBusy[channelID]
+	d.lastBusy[channelID] = busy
+	if !seen || elapsed <= 0 {
+		return d.utilization[channelID]
+	}
+	instant := float64(busy-previous) / float64(elapsed)
+	instant = min(max(instant, 0), 1)
+	smoothed := d.utilization[channelID]*(1-busySmoothing) + instant*busySmoothing
+	d.utilization[channelID] = smoothed
+	return smoothed
+}
+
+// bar 将 [0, 1] 的占用率绘制为固定宽度的条。
+func bar(fraction float64) string {
+	filled := int(fraction*barWidth + 0.5)
+	return strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+}
@@ -0,0 +1,119 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// EventLog 是一个 slog.Handler，将日志记录格式化为单行文本并保存在固定容量的环形缓冲中，
+// 供仪表盘显示为滚动事件日志。启用仪表盘时它替代写往终端的日志输出，避免日志打乱画面。
+type EventLog struct {
+	level slog.Leveler
+	attrs []slog.Attr
+	group string
+
+	buffer *eventBuffer
+}
+
+// eventBuffer 是多个 EventLog (由 WithAttrs/WithGroup 派生) 共享的环形缓冲。
+type eventBuffer struct {
+	mutex sync.Mutex
+	lines []string
+	next  int
+	full  bool
+}
+
+// NewEventLog 创建一个保留最近 capacity 行、只记录 level 及以上级别的事件日志。
+func NewEventLog(capacity int, level slog.Leveler) *EventLog {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &EventLog{level: level, buffer: &eventBuffer{lines: make([]string, capacity)}}
+}
+
+// Enabled 实现 slog.Handler。
+func (e *EventLog) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= e.level.Level()
+}
+
+// Handle 实现 slog.Handler。
+func (e *EventLog) Handle(_ context.Context, record slog.Record) error {
+	var line strings.Builder
+	fmt.Fprintf(&line, "%s %-5s %s", record.Time.Format("15:04:05"), record.Level, record.Message)
+	for _, attr := range e.attrs {
+		writeAttr(&line, "", attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		writeAttr(&line, e.group, attr)
+		return true
+	})
+	e.buffer.push(line.String())
+	return nil
+}
+
+// WithAttrs 实现 slog.Handler。
+func (e *EventLog) WithAttrs(attrs []slog.Attr) slog.Handler {
+	derived := *e
+	derived.attrs = make([]slog.Attr, 0, len(e.attrs)+len(attrs))
+	derived.attrs = append(derived.attrs, e.attrs...)
+	for _, attr := range attrs {
+		if e.group != "" {
+			attr.Key = e.group + "." + attr.Key
+		}
+		derived.attrs = append(derived.attrs, attr)
+	}
+	return &derived
+}
+
+// WithGroup 实现 slog.Handler。
+func (e *EventLog) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return e
+	}
+	derived := *e
+	if derived.group != "" {
+		derived.group += "."
+	}
+	derived.group += name
+	return &derived
+}
+
+// Lines 按时间顺序返回缓冲中保留的日志行。
+func (e *EventLog) Lines() []string {
+	return e.buffer.snapshot()
+}
+
+func writeAttr(line *strings.Builder, group string, attr slog.Attr) {
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	key := attr.Key
+	if group != "" {
+		key = group + "." + key
+	}
+	fmt.Fprintf(line, " %s=%v", key, attr.Value.Resolve())
+}
+
+func (b *eventBuffer) push(line string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.lines[b.next] = line
+	b.next = (b.next + 1) % len(b.lines)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+func (b *eventBuffer) snapshot() []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if !b.full {
+		return append([]string(nil), b.lines[:b.next]...)
+	}
+	lines := make([]string, 0, len(b.lines))
+	lines = append(lines, b.lines[b.next:]...)
+	return append(lines, b.lines[:b.next]...)
+}
@@ -0,0 +1,86 @@
+package dashboard
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 缓冲写满后继续写入会覆盖最旧的行，快照始终按时间顺序返回最近 capacity 行。
+func TestEventBufferSnapshotWrapsAround(t *testing.T) {
+	buffer := &eventBuffer{lines: make([]string, 3)}
+	if got := buffer.snapshot(); len(got) != 0 {
+		t.Fatalf("空缓冲的快照 = %v, 期望为空", got)
+	}
+
+	tests := []struct {
+		pushes int
+		want   []string
+	}{
+		{2, []string{"0", "1"}},
+		{3, []string{"0", "1", "2"}},
+		{4, []string{"1", "2", "3"}},
+		{6, []string{"3", "4", "5"}},
+		{7, []string{"4", "5", "6"}},
+	}
+	pushed := 0
+	for _, tt := range tests {
+		for ; pushed < tt.pushes; pushed++ {
+			buffer.push(fmt.Sprint(pushed))
+		}
+		if got := buffer.snapshot(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("写入 %d 行后快照 = %v, 期望 %v", tt.pushes, got, tt.want)
+		}
+	}
+}
+
+// 快照是副本，之后的写入不会改变已取得的快照。
+func TestEventBufferSnapshotIsCopy(t *testing.T) {
+	buffer := &eventBuffer{lines: make([]string, 2)}
+	buffer.push("a")
+	buffer.push("b")
+	snapshot := buffer.snapshot()
+	buffer.push("c")
+	if !reflect.DeepEqual(snapshot, []string{"a", "b"}) {
+		t.Errorf("快照在后续写入后变为 %v", snapshot)
+	}
+}
+
+// WithAttrs 添加的属性带上当时所在的分组前缀，WithGroup 之后记录的属性带上完整的分组路径。
+func TestEventLogGroupAndAttrPrefixes(t *testing.T) {
+	events := NewEventLog(10, slog.LevelInfo)
+	logger := slog.New(events).
+		With("flight", "CCA1001").
+		WithGroup("comms").
+		With("channel", "Primary").
+		WithGroup("tx")
+	logger.Info("发送", "attempt", 2)
+	slog.New(events).Info("无分组", "key", "value")
+	slog.New(events).WithGroup("").Info("空分组", "key", "value")
+
+	lines := events.Lines()
+	if len(lines) != 3 {
+		t.Fatalf("记录了 %d 行, 期望 3 行: %v", len(lines), lines)
+	}
+	if want := " flight=CCA1001 comms.channel=Primary comms.tx.attempt=2"; !strings.HasSuffix(lines[0], want) {
+		t.Errorf("分组日志行 = %q, 期望以 %q 结尾", lines[0], want)
+	}
+	for _, line := range lines[1:] {
+		if !strings.HasSuffix(line, " key=value") {
+			t.Errorf("无分组日志行 = %q, 期望以 %q 结尾", line, " key=value")
+		}
+	}
+}
+
+// 派生的 EventLog 与原日志共享同一个缓冲，低于最低级别的记录被丢弃。
+func TestEventLogDerivedHandlersShareBuffer(t *testing.T) {
+	events := NewEventLog(10, slog.LevelInfo)
+	slog.New(events).Debug("被丢弃")
+	slog.New(events).With("a", 1).Info("派生")
+	slog.New(events).WithGroup("g").Warn("分组")
+	if lines := events.Lines(); len(lines) != 2 {
+		t.Errorf("记录了 %d 行, 期望 2 行: %v", len(lines), lines)
+	}
+}
@@ -4,14 +4,35 @@ package main
 import (
 	"Air-Simulator/collector"
 	"Air-Simulator/config" // 导入新的 config 包
+	"Air-Simulator/dashboard"
 	"Air-Simulator/simulation"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 )
 
 func main() {
+	tui := flag.Bool("tui", false, "以终端仪表盘实时显示模拟状态，运行期间日志显示在仪表盘的事件日志中")
+	flag.Parse()
+
+	logger, err := simulation.NewLogger(os.Stderr, config.LogLevel)
+	if err != nil {
+		log.Fatalf("❌ 配置错误: %v", err)
+	}
+	slog.SetDefault(logger) // 此后 log 包的输出也经由该记录器，以 INFO 级别记录
+
+	// 仪表盘模式下日志改写入事件日志，避免直接输出到终端打乱画面
+	var events *dashboard.EventLog
+	if *tui {
+		level, _ := simulation.ParseLogLevel(config.LogLevel) // 已在 NewLogger 中校验
+		events = dashboard.NewEventLog(config.DashboardEventLogLines, level)
+	}
+
 	log.Println("=============================================")
 	log.Println("======  Air-Ground Communication Simulation  ======")
 	log.Println("=============================================")
@@ -27,63 +48,287 @@ func main() {
 	log.Println("=============================================")
 
 	// --- 1. 创建信道和通信系统 (所有参数均从 config 包加载) ---
-	primaryChannel := simulation.NewChannel("Primary", config.PrimaryPMap, config.PrimaryTimeSlot)
-	var backupChannel *simulation.Channel
-	if config.EnableBackupChannel {
-		backupChannel = simulation.NewChannel("Backup", config.BackupPMap, config.BackupTimeSlot)
+	// newChannel 创建一个按全局配置设置容量、碰撞检测与时隙抖动的信道
+	newChannel := func(id string, pMap map[config.Priority]float64, timeSlot time.Duration) *simulation.Channel {
+		ch := simulation.NewChannel(id, pMap, timeSlot)
+		ch.Capacity = config.ChannelCapacity
+		ch.CollisionDetection = config.EnableCollisionDetection
+		ch.CollisionWindow = config.CollisionWindow
+		ch.CollisionModel = config.CollisionModel
+		ch.JamTime = config.JamTime
+		ch.SlotJitter = config.SlotJitter
+		return ch
+	}
+	// newChannelPair 创建一组主/备信道，备用信道未启用时为 nil
+	newChannelPair := func(primaryID, backupID string) (*simulation.Channel, *simulation.Channel) {
+		primary := newChannel(primaryID, config.PrimaryPMap, config.PrimaryTimeSlot)
+		var backup *simulation.Channel
+		if config.EnableBackupChannel {
+			backup = newChannel(backupID, config.BackupPMap, config.BackupTimeSlot)
+		}
+		return primary, backup
+	}
+	primaryChannel, backupChannel := newChannelPair("Primary", "Backup")
+	// 上下行分频时主信道即为下行信道，另建一个上行信道，不使用备用信道
+	var uplinkChannel *simulation.Channel
+	if config.EnableLinkSplit {
+		if config.EnableSectors {
+			log.Fatalf("❌ 配置错误: 上下行分频不能与扇区同时启用")
+		}
+		primaryChannel = newChannel("Downlink", config.PrimaryPMap, config.PrimaryTimeSlot)
+		backupChannel = nil
+		uplinkChannel = newChannel("Uplink", config.PrimaryPMap, config.PrimaryTimeSlot)
+		log.Printf("加载配置: 上下行分频, 下行信道 -> %s, 上行信道 -> %s", primaryChannel.ID, uplinkChannel.ID)
+	}
+	log.Printf("加载配置: 碰撞检测 -> %v (易碰撞窗口: %v)", config.EnableCollisionDetection, config.CollisionWindow)
+
+	mediumAccess, err := simulation.NewMediumAccess(config.MediumAccessScheme)
+	if err != nil {
+		log.Fatalf("❌ 配置错误: %v", err)
+	}
+	log.Printf("加载配置: 信道接入策略 -> %s", mediumAccess.Name())
+	if err := simulation.CheckCollisionModel(config.CollisionModel); err != nil {
+		log.Fatalf("❌ 配置错误: %v", err)
+	}
+	log.Printf("加载配置: 碰撞模型 -> %s", config.CollisionModel)
+	if err := simulation.CheckRunTermination(); err != nil {
+		log.Fatalf("❌ 配置错误: %v", err)
+	}
+	if err := simulation.CheckAckSendMode(config.AckSendMode); err != nil {
+		log.Fatalf("❌ 配置错误: %v", err)
 	}
+	if err := simulation.CheckAircraftReceiver(config.AircraftReceiver); err != nil {
+		log.Fatalf("❌ 配置错误: %v", err)
+	}
+	trafficGenerator, err := simulation.NewTrafficGeneratorFactory(config.TrafficGenerator)
+	if err != nil {
+		log.Fatalf("❌ 配置错误: %v", err)
+	}
+	log.Printf("加载配置: 例行报告流量生成器 -> %s", config.TrafficGenerator)
 
-	commsSystem := simulation.NewCommunicationSystem(primaryChannel, backupChannel, config.SwitchoverProbs)
-	commsSystem.StartDispatching() // 启动所有信道的调度器
+	commsSystem := simulation.NewCommunicationSystem(primaryChannel, backupChannel, config.SwitchoverProbs, mediumAccess)
+	if uplinkChannel != nil {
+		commsSystem.SplitLinks(primaryChannel, uplinkChannel)
+	}
+	if config.EnableGuardChannel {
+		commsSystem.GuardChannel = newChannel("Guard", config.PrimaryPMap, config.PrimaryTimeSlot)
+		log.Printf("加载配置: 已启用只供 CRITICAL 报文使用的保护信道 -> %s", commsSystem.GuardChannel.ID)
+	}
 
 	// --- 2. 创建地面站和飞机 ---
-	groundControl := simulation.NewGroundControlCenter("GND_CTL_MAIN")
-	go groundControl.StartListening(commsSystem)
-
-	aircraftList := make([]*simulation.Aircraft, simulation.AircraftCount)
-	for i := 0; i < simulation.AircraftCount; i++ {
+	flightPlans, err := simulation.SelectFlightPlans()
+	if err != nil {
+		log.Fatalf("❌ 配置错误: %v", err)
+	}
+	aircraftList := make([]*simulation.Aircraft, len(flightPlans))
+	for i := range aircraftList {
 		icao := fmt.Sprintf("A%d", 70000+i)
 		flightID := fmt.Sprintf("CES%d", 1001+i)
-		aircraft := simulation.NewAircraft(icao, fmt.Sprintf("B-%d", 6000+i), "A320neo", "Airbus", "MSN1234"+fmt.Sprintf("%d", i), "CES")
+		profile := config.AircraftProfiles[i%len(config.AircraftProfiles)]
+		aircraft := simulation.NewAircraftWithProfile(icao, fmt.Sprintf("B-%d", 6000+i), "MSN1234"+fmt.Sprintf("%d", i), "CES", profile)
 		aircraft.CurrentFlightID = flightID
 		aircraftList[i] = aircraft
-		go aircraft.StartListening(commsSystem)
 	}
 	log.Printf("✈️  已成功创建 %d 架飞机.", len(aircraftList))
 
+	groundStations := make([]*simulation.GroundControlCenter, 0, len(config.GroundStations))
+	for _, spec := range config.GroundStations {
+		coverage := simulation.CoverageRegion{CenterLatitude: spec.Latitude, CenterLongitude: spec.Longitude, RadiusKM: spec.RadiusKM}
+		station := simulation.NewGroundControlCenter(spec.ID, coverage)
+		station.TrackAircraft(aircraftList)
+		groundStations = append(groundStations, station)
+	}
+	log.Printf("🛰️  已成功部署 %d 个地面站.", len(groundStations))
+
+	// 划分扇区时每个地面站负责一个扇区：第一个扇区使用上面创建的信道，其余扇区使用各自独立的信道
+	if config.EnableSectors {
+		for i, station := range groundStations {
+			primary, backup := primaryChannel, backupChannel
+			if i > 0 {
+				primary, backup = newChannelPair("Primary-"+station.ID, "Backup-"+station.ID)
+			}
+			commsSystem.AddSector(simulation.NewSector(station, primary, backup))
+		}
+		log.Printf("🗺️  已划分 %d 个扇区，各扇区使用独立的信道.", len(commsSystem.Sectors()))
+	}
+
+	// 所有信道均已创建，启动调度器与所有实体的监听
+	commsSystem.StartDispatching()
+	for _, aircraft := range aircraftList {
+		go aircraft.StartListening(commsSystem)
+	}
+	for _, station := range groundStations {
+		go station.StartListening(commsSystem)
+	}
+
+	// --- 2.5 (可选) 从快照恢复状态，并安排中途快照 ---
+	if config.RestoreStatePath != "" {
+		state, err := simulation.LoadSimulationState(config.RestoreStatePath)
+		if err != nil {
+			log.Fatalf("❌ 无法读取模拟状态快照 '%s': %v", config.RestoreStatePath, err)
+		}
+		if err := state.RestoreInto(commsSystem, aircraftList, groundStations); err != nil {
+			log.Fatalf("❌ 无法恢复模拟状态快照 '%s': %v", config.RestoreStatePath, err)
+		}
+		log.Printf("♻️  已从快照 '%s' (捕获于 %v) 恢复模拟状态。", config.RestoreStatePath, state.CapturedAt.Format(time.RFC3339))
+	}
+	if config.SnapshotAfter > 0 {
+		time.AfterFunc(simulation.Scaled(config.SnapshotAfter), func() {
+			path := filepath.Join("report", fmt.Sprintf("simulation_state_%s.json", time.Now().Format("20060102_150405")))
+			state := simulation.CaptureSimulationState(commsSystem, aircraftList, groundStations)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				log.Printf("❌ 错误: 无法创建快照目录: %v", err)
+				return
+			}
+			if err := state.SaveToFile(path); err != nil {
+				log.Printf("❌ 错误: 无法保存模拟状态快照: %v", err)
+				return
+			}
+			log.Printf("📸 模拟状态快照已保存到: %s", path)
+		})
+	}
+
 	// --- 3. 启动独立的数据收集器 ---
 	channelsToMonitor := []*simulation.Channel{primaryChannel, backupChannel}
-	groundStationsToMonitor := []*simulation.GroundControlCenter{groundControl}
+	if uplinkChannel != nil {
+		channelsToMonitor = append(channelsToMonitor, uplinkChannel)
+	}
+	if commsSystem.GuardChannel != nil {
+		channelsToMonitor = append(channelsToMonitor, commsSystem.GuardChannel)
+	}
+	for i, sector := range commsSystem.Sectors() {
+		if i > 0 { // 第一个扇区使用的就是上面的主/备信道
+			channelsToMonitor = append(channelsToMonitor, sector.Channels()...)
+		}
+	}
+	groundStationsToMonitor := groundStations
 
 	var collectorWg sync.WaitGroup
 	collectorWg.Add(1)
 	doneChan := make(chan struct{})
 
+	airspace := simulation.NewAirspace(config.MaxConcurrentFlights, config.AirspaceAdmissionPolicy)
+
 	dataCollector := collector.NewDataCollector(
 		&collectorWg,
 		doneChan,
 		aircraftList,
 		channelsToMonitor,
 		groundStationsToMonitor,
+		airspace,
+		commsSystem.Sectors(),
+		mediumAccess.Name(),
 	)
 	go dataCollector.Run()
 
+	var dashboardWg sync.WaitGroup
+	if *tui {
+		board := dashboard.New(os.Stdout, config.DashboardRefreshInterval, channelsToMonitor, aircraftList, groundStations, airspace, events)
+		slog.SetDefault(slog.New(events))
+		dashboardWg.Add(1)
+		go func() {
+			defer dashboardWg.Done()
+			board.Run(doneChan)
+		}()
+	}
+
+	if config.DATISBroadcastInterval > 0 {
+		for _, station := range groundStations {
+			go station.StartDATISBroadcasts(commsSystem, config.DATISBroadcastInterval, doneChan)
+		}
+	}
+
+	if config.GroundStationOutageStation != "" {
+		for _, station := range groundStations {
+			if station.ID == config.GroundStationOutageStation {
+				go station.ScheduleOutage(config.GroundStationOutageStart, config.GroundStationOutageDuration, doneChan)
+				log.Printf("加载配置: 地面站 %s 将在 %v 后离线 %v (策略: %s)", station.ID,
+					config.GroundStationOutageStart, config.GroundStationOutageDuration, config.GroundStationOutagePolicy)
+			}
+		}
+	}
+
+	if config.EnableWeatherDegradation {
+		conditions := &simulation.ChannelConditions{
+			BaseFrameErrorRate: config.BaseFrameErrorRate,
+			PeakFrameErrorRate: config.StormPeakFrameErrorRate,
+			PeakDataRateFactor: config.StormPeakDataRateFactor,
+			StormCenter:        config.StormCenter,
+			StormWidth:         config.StormWidth,
+			UpdateInterval:     config.ConditionsUpdateInterval,
+		}
+		go conditions.Run(commsSystem.Channels(), doneChan)
+	}
+
 	// --- 4. 运行飞行计划模拟 ---
-	log.Println("🛫 开始执行所有飞行计划...")
 	var simWg sync.WaitGroup
-	simulation.RunSimulationSession(&simWg, commsSystem, aircraftList)
+	runStop := make(chan struct{}) // 运行终止条件提前满足时关闭，停止尚未完成的飞行计划
+	var traceRecorder *simulation.TraceRecorder
+	switch config.TraceMode {
+	case simulation.TraceModeRecord:
+		traceRecorder, err = simulation.NewTraceRecorder(config.TracePath)
+		if err != nil {
+			log.Fatalf("❌ 无法创建报文轨迹文件 '%s': %v", config.TracePath, err)
+		}
+		simulation.SetTraceRecorder(traceRecorder)
+		log.Printf("📼 将记录报文轨迹到: %s", config.TracePath)
+	case simulation.TraceModeReplay:
+		trace, err := simulation.LoadTrace(config.TracePath)
+		if err != nil {
+			log.Fatalf("❌ 无法读取报文轨迹 '%s': %v", config.TracePath, err)
+		}
+		log.Printf("📼 将回放报文轨迹 '%s' (%d 条记录)", config.TracePath, len(trace.Records))
+		trace.Run(&simWg, commsSystem, aircraftList, runStop)
+	case simulation.TraceModeOff:
+	default:
+		log.Fatalf("❌ 配置错误: 未知的报文轨迹模式 %q", config.TraceMode)
+	}
+	var eventLog *simulation.MessageEventLog
+	if config.MessageEventLogPath != "" {
+		eventLog, err = simulation.NewMessageEventLog(config.MessageEventLogPath)
+		if err != nil {
+			log.Fatalf("❌ 无法创建报文事件日志 '%s': %v", config.MessageEventLogPath, err)
+		}
+		simulation.SetMessageEventLog(eventLog)
+		log.Printf("🧾 将记录报文事件日志到: %s", config.MessageEventLogPath)
+	}
 
-	// 等待所有飞行计划完成
-	simWg.Wait()
-	log.Println("✅ 所有飞行计划已执行完毕.")
+	log.Println("🛫 开始执行所有飞行计划...")
+	if _, err := simulation.RunSimulationSession(&simWg, commsSystem, flightPlans, aircraftList, airspace, trafficGenerator, runStop); err != nil {
+		log.Fatalf("❌ 无法启动飞行计划: %v", err)
+	}
+
+	// 等待所有飞行计划完成，或其他运行终止条件满足
+	log.Printf("✅ 模拟运行结束: %s.", simulation.WaitForTermination(&simWg, aircraftList, runStop))
+	if traceRecorder != nil {
+		count, err := traceRecorder.Close()
+		if err != nil {
+			log.Printf("❌ 错误: 无法保存报文轨迹: %v", err)
+		} else {
+			log.Printf("📼 报文轨迹已保存到: %s (%d 条记录)", config.TracePath, count)
+		}
+	}
 
 	// --- 5. 结束并保存 ---
 	log.Println("... 等待 1 分钟以确保所有最终的通信完成 ...")
-	time.Sleep(1 * time.Minute)
+	time.Sleep(simulation.Scaled(1 * time.Minute))
 
 	log.Println("... 正在停止数据收集器并保存结果 ...")
 	close(doneChan)    // 发送停止信号
+	dashboardWg.Wait() // 等待仪表盘绘制最后一帧
+	slog.SetDefault(logger)
 	collectorWg.Wait() // 等待收集器完成文件保存
+	if err := dataCollector.Err(); err != nil {
+		log.Printf("❌ 错误: %v", err)
+	}
+	if eventLog != nil {
+		count, err := eventLog.Close()
+		if err != nil {
+			log.Printf("❌ 错误: 无法保存报文事件日志: %v", err)
+		} else {
+			log.Printf("🧾 报文事件日志已保存到: %s (%d 条事件)", config.MessageEventLogPath, count)
+		}
+	}
 
 	log.Println("=============================================")
 	log.Println("===========  SIMULATION FINISHED  ===========")
@@ -0,0 +1,99 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// 地面站发送 ACK 的方式
+const (
+	AckSendContend   = "contend"   // 与数据报文一样竞争信道
+	AckSendImmediate = "immediate" // 不经信道接入立即送达 (专用 ACK 上行链路)
+)
+
+// CheckAckSendMode 检查 mode 是否为已知的 ACK 发送方式。
+func CheckAckSendMode(mode string) error {
+	switch mode {
+	case AckSendContend, AckSendImmediate:
+		return nil
+	default:
+		return fmt.Errorf("未知的 ACK 发送方式 %q", mode)
+	}
+}
+
+// ackCoalescer 收集地面站待发送的 ACK，在 config.AckCoalesceWindow 结束时合并为累积 ACK 帧发送。
+type ackCoalescer struct {
+	mutex      sync.Mutex
+	pending    []AckEntry
+	recipients []string // 与 pending 一一对应，被确认的报文的发送方 ICAO 地址
+	batches    uint64   // 已生成的累积 ACK 帧数，用于生成报文ID
+}
+
+// queueAck 将一个发给 recipient 的 ACK 加入待合并队列；队列中的第一个 ACK 会启动合并窗口。
+func (gcc *GroundControlCenter) queueAck(ack AcknowledgementData, recipient string, commsSystem *CommunicationSystem) {
+	entry := AckEntry{OriginalMessageID: ack.OriginalMessageID, Status: ack.Status, Reason: ack.Reason}
+	if !ack.OriginalTimestamp.IsZero() {
+		entry.SentAtUnixMilli = ack.OriginalTimestamp.UnixMilli()
+	}
+
+	gcc.acks.mutex.Lock()
+	defer gcc.acks.mutex.Unlock()
+	gcc.acks.pending = append(gcc.acks.pending, entry)
+	gcc.acks.recipients = append(gcc.acks.recipients, recipient)
+	if len(gcc.acks.pending) == 1 {
+		time.AfterFunc(Scaled(config.AckCoalesceWindow), func() { gcc.flushAcks(commsSystem) })
+	}
+}
+
+// flushAcks 将待合并队列中的所有 ACK 按 config.MaxPayloadBytes 装入尽可能少的帧并发送。
+func (gcc *GroundControlCenter) flushAcks(commsSystem *CommunicationSystem) {
+	gcc.acks.mutex.Lock()
+	pending, recipients := gcc.acks.pending, gcc.acks.recipients
+	gcc.acks.pending, gcc.acks.recipients = nil, nil
+	gcc.acks.mutex.Unlock()
+
+	for len(pending) > 0 {
+		n := ackBatchSize(pending)
+		batch := pending[:n]
+		pending = pending[n:]
+		batchRecipients := slices.Compact(slices.Sorted(slices.Values(recipients[:n])))
+		recipients = recipients[n:]
+
+		var data AcknowledgementData
+		var messageID string
+		if len(batch) == 1 {
+			// 只有一个 ACK 时仍按普通 ACK 发送
+			data = AcknowledgementData{OriginalMessageID: batch[0].OriginalMessageID, Status: batch[0].Status, Reason: batch[0].Reason}
+			if batch[0].SentAtUnixMilli != 0 {
+				data.OriginalTimestamp = time.UnixMilli(batch[0].SentAtUnixMilli)
+			}
+			messageID = fmt.Sprintf("ACK-%s", batch[0].OriginalMessageID)
+		} else {
+			gcc.acks.mutex.Lock()
+			gcc.acks.batches++
+			messageID = fmt.Sprintf("ACK-%s-BATCH%d", gcc.ID, gcc.acks.batches)
+			gcc.acks.mutex.Unlock()
+			data = AcknowledgementData{Acks: batch}
+			slog.Debug("📦 合并发送累积 ACK", "station", gcc.ID, "msgID", messageID, "acks", len(batch))
+		}
+		gcc.sendAck(messageID, data, len(batch), batchRecipients, commsSystem)
+	}
+}
+
+// ackBatchSize 返回从 pending 开头起能装入一个累积 ACK 帧的 ACK 数，至少为 1。
+func ackBatchSize(pending []AckEntry) int {
+	n := 1
+	for n < len(pending) {
+		raw, err := json.Marshal(AcknowledgementData{Acks: pending[:n+1]})
+		if err != nil || len(raw) > config.MaxPayloadBytes {
+			break
+		}
+		n++
+	}
+	return n
+}
@@ -0,0 +1,26 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"time"
+)
+
+// ackTimeoutFor 计算等待 ACK 的超时时间：以 config.AckTimeout 为基础，
+// 乘以优先级与信道 (主/备用) 的倍数，再加上与服务地面站距离成正比的余量。
+func ackTimeoutFor(priority config.Priority, backupChannel bool, distanceKM float64) time.Duration {
+	factor, ok := config.AckTimeoutPriorityFactors[priority]
+	if !ok {
+		factor = 1
+	}
+	if backupChannel {
+		factor *= config.AckTimeoutBackupChannelFactor
+	}
+	timeout := time.Duration(float64(config.AckTimeout)*factor) + time.Duration(distanceKM*float64(config.AckTimeoutPerKM))
+	return max(timeout, 0)
+}
+
+// ackTimeoutFor 返回飞机此刻发出的报文等待 ACK 的超时时间。尚未分配服务地面站时不计距离余量。
+func (a *Aircraft) ackTimeoutFor(priority config.Priority, backupChannel bool) time.Duration {
+	distanceKM, _ := a.servingStationRangeKM()
+	return ackTimeoutFor(priority, backupChannel, distanceKM)
+}
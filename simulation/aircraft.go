@@ -3,8 +3,8 @@ package simulation
 import (
 	"Air-Simulator/config"
 	"encoding/json"
-	"log"
-	"math/rand/v2"
+	"log/slog"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -34,28 +34,83 @@ type Aircraft struct {
 	SquawkCode              string                    `json:"squawkCode"`                // 应答机代码 (Transponder Code)
 
 	// --- 通信与系统能力 ---
-	ACARSEnabled          bool   `json:"acarsEnabled"`          // 是否启用 ACARS 功能
-	CPDLCEnabled          bool   `json:"cpdlcEnabled"`          // 是否启用 CPDLC 功能
-	SatelliteCommsEnabled bool   `json:"satelliteCommsEnabled"` // 是否启用卫星通信
-	SoftwareVersion       string `json:"softwareVersion"`
+	ACARSEnabled          bool    `json:"acarsEnabled"`          // 是否启用 ACARS 功能
+	CPDLCEnabled          bool    `json:"cpdlcEnabled"`          // 是否启用 CPDLC 功能
+	SatelliteCommsEnabled bool    `json:"satelliteCommsEnabled"` // 是否启用卫星通信
+	SoftwareVersion       string  `json:"softwareVersion"`
+	TxPowerDBm            float64 `json:"txPowerDBm"`  // 发射功率 (dBm)，决定下行帧在地面站处的接收功率
+	LinkQuality           float64 `json:"linkQuality"` // 链路质量系数 (0, 1]，下行帧额外以 1 - LinkQuality 的概率损坏
+
+	// --- 机型配置 ---
+	Profile config.AircraftProfile `json:"-"` // 发动机数量、报告间隔与典型燃油消耗
 
 	// --- 通信与状态管理 ---
 	inboundQueue chan ACARSMessageInterface // 自己的消息收件箱
+	listener     *Listener                  // 注册到信道上的收件箱，记录溢出丢弃
 	ackWaiters   sync.Map
 
+	// --- 航迹与地面站移交 ---
+	track            flightTrack               // 当前航迹，位置由其按时间推算
+	servingStationID string                    // 当前为本机提供服务的地面站
+	stationCoverage  map[string]CoverageRegion // 已知地面站的覆盖区域，用于接收广播
+	trackSince       time.Time                 // 当前航迹的启用时刻，用于计算盲区停留时间
+	deadZoneTime     time.Duration             // 之前各段航迹在通信盲区内的停留时间
+	positionMutex    sync.RWMutex              // 保护 track / CurrentPosition / servingStationID / stationCoverage / 盲区停留时间
+
 	// --- 通信统计 ---
-	totalTxAttempts   uint64       // 总传输尝试次数
-	totalCollisions   uint64       // 碰撞
-	successfulTx      uint64       // 成功发送并收到ACK的报文总数
-	totalRetries      uint64       // 总重传次数
-	totalRqTunnel     uint64       // 总尝试请求隧道次数
-	totalFailRqTunnel uint64       // 总失败请求隧道次数
-	totalWaitTimeNs   atomic.Int64 // 总等待时间 (纳秒)
+	Transmitter              // 信道接入逻辑与统计
+	successfulTx      uint64 // 成功发送并收到ACK的报文总数
+	totalRetries      uint64 // 总重传次数
+	permanentFailures uint64 // 达到最大重传次数后被放弃的报文数
+	nacksReceived     uint64 // 被地面站以 NACK 拒绝的报文数
+	checksumFailures  uint64 // 收到的校验和错误的 ACK / D-ATIS 帧数 (包括发给其他飞机的)
+	sectorCrossings   uint64 // 跨越扇区 (切换信道) 的次数
+	ackMissedDueToTx  uint64 // 半双工模式下因正在发送而错过的 ACK 数
+	ackMissedTuning   uint64 // 可调谐接收机因正在切换信道而错过的 ACK 数
+	ackMissedOffTuned uint64 // 可调谐接收机因调谐在其他信道而错过的 ACK 数
+	linkQualityLosses uint64 // 因链路质量较差而在传输中损坏的下行帧数
+	deadZoneLosses    uint64 // 在通信盲区内发出、到不了地面站的下行帧数
+
+	// --- 死信 ---
+	deadLetters deadLetterBook // 正在发送中以及最终未能送达的报文
+
+	// --- 故障报告 ---
+	faultSeq atomic.Uint64 // 已发送的故障报告数，用于生成唯一的报文ID (故障风暴中同一时刻会发出多份故障报告)
+
+	// --- 链路测试 ---
+	linkTestRTTs  []time.Duration // 每次链路测试的往返时间 (从报文发出到收到地面站回复)
+	linkTestMutex sync.Mutex
+
+	// --- ACK 往返时间 ---
+	ackRttNs    atomic.Int64    // 所有 ACK 往返时间之和 (纳秒)
+	ackRTTs     []time.Duration // 每个 ACK 的往返时间样本，用于计算分位数
+	ackRTTMutex sync.Mutex
+
+	// --- 各优先级的最大等待时间 (从开始发送到获得信道) ---
+	maxWaitByPriority map[config.Priority]time.Duration
+	maxWaitMutex      sync.Mutex
+
+	// --- 端到端时延 (从开始发送到收到 ACK，按优先级抽样) ---
+	latencies      latencyStats
+	stormLatencies latencyStats // 其中在故障风暴期间开始发送的报文
+
+	// --- D-ATIS 广播 ---
+	datisReceived uint64     // 收到的 D-ATIS 广播次数
+	latestDATIS   *DATISData // 最近收到的 D-ATIS
+	datisMutex    sync.Mutex
+
+	// --- 气象请求 (config.WeatherRequests) ---
+	weatherRequests        sync.Map     // 等待地面站回复的气象请求报文ID
+	weatherRequestsSent    uint64       // 发出的气象请求数
+	weatherRepliesReceived uint64       // 收到的对应回复数
+	latestWeather          *WeatherData // 最近收到的气象信息
+	weatherMutex           sync.Mutex
 }
 
 // NewAircraft 创建一个航空器实例的构造函数
 func NewAircraft(icaoAddr, reg, aircraftType, manufacturer, serialNum, airlineCode string) *Aircraft {
-	return &Aircraft{
+	inboundQueue := make(chan ACARSMessageInterface, 20) // 初始化收件箱
+	a := &Aircraft{
 		ICAOAddress:             icaoAddr,
 		Registration:            reg,
 		AircraftType:            aircraftType,
@@ -64,137 +119,532 @@ func NewAircraft(icaoAddr, reg, aircraftType, manufacturer, serialNum, airlineCo
 		AirlineICAOCode:         airlineCode,
 		EngineStatus:            make(map[int]*EngineReportData), // 初始化 Map
 		LastDataReportTimestamp: time.Now(),
-		inboundQueue:            make(chan ACARSMessageInterface, 20), // 初始化收件箱
-		ackWaiters:              sync.Map{},                           // 初始时间
+		inboundQueue:            inboundQueue,
+		listener:                NewListener(icaoAddr, inboundQueue),
+		ackWaiters:              sync.Map{}, // 初始时间
+		track:                   stationaryTrack(config.AirportLatitude, config.AirportLongitude),
+		TxPowerDBm:              config.AircraftTxPowerDBm,
+		LinkQuality:             config.AircraftLinkQuality,
+		Transmitter:             Transmitter{MaxQueueLength: config.MaxOutboundQueueLength},
+	}
+	a.listener.onMissed = a.missWhileTransmitting
+	if config.AircraftReceiver == ReceiverTuned {
+		a.listener.useTuner(a.missOffChannel)
+	}
+	return a
+}
+
+// NewAircraftWithProfile 按机型配置创建航空器，机型、制造商、燃油状态均取自配置。
+func NewAircraftWithProfile(icaoAddr, reg, serialNum, airlineCode string, profile config.AircraftProfile) *Aircraft {
+	a := NewAircraft(icaoAddr, reg, profile.AircraftType, profile.Manufacturer, serialNum, airlineCode)
+	a.Profile = profile
+	a.FuelRemainingKG = profile.InitialFuelKG
+	a.FuelConsumptionRateKGPH = profile.NominalFuelFlowKGPH
+	if profile.LinkQuality > 0 {
+		a.LinkQuality = profile.LinkQuality
 	}
+	return a
+}
+
+// engineCount 返回飞机的发动机数量，未配置机型时视为单发。
+func (a *Aircraft) engineCount() int {
+	return max(a.Profile.EngineCount, 1)
+}
+
+// reportIntervals 返回位置、燃油、气象报告的发送间隔，机型配置未指定时使用全局默认值。
+func (a *Aircraft) reportIntervals() (pos, fuel, weather time.Duration) {
+	pos, fuel, weather = config.PosReportInterval, config.FuelReportInterval, config.WeatherReportInterval
+	if a.Profile.PosReportInterval > 0 {
+		pos = a.Profile.PosReportInterval
+	}
+	if a.Profile.FuelReportInterval > 0 {
+		fuel = a.Profile.FuelReportInterval
+	}
+	if a.Profile.WeatherReportInterval > 0 {
+		weather = a.Profile.WeatherReportInterval
+	}
+	return pos, fuel, weather
 }
 
 func (a *Aircraft) StartListening(comms *CommunicationSystem) {
-	comms.RegisterListener(a.inboundQueue) // 通过管理器注册
-	log.Printf("✈️  [飞机 %s] 的通信系统已启动，开始监听主/备信道...", a.CurrentFlightID)
+	comms.RegisterReceiver(a.listener, true) // 通过管理器注册，上下行分频时只接收上行报文
+	slog.Info("✈️  飞机通信系统已启动，开始监听主/备信道", "flight", a.CurrentFlightID)
 
 	for msg := range a.inboundQueue {
-		// 只关心 ACK 报文
+		// 只处理 D-ATIS、ACK 与地面站的气象回复，校验和不一致的报文已在传输中损坏，视同丢失
+		base := msg.GetBaseMessage()
+		weatherReply := base.Type == MsgTypeWeather && base.FromGround()
+		if (base.Type == MsgTypeDATIS || base.Type == MsgTypeAck || weatherReply) && !verifyChecksum(msg) {
+			atomic.AddUint64(&a.checksumFailures, 1)
+			continue
+		}
+		if weatherReply {
+			a.receiveWeather(msg)
+			continue
+		}
+		// D-ATIS 是地面站的广播，只需记录，无需应答
+		if msg.GetBaseMessage().Type == MsgTypeDATIS {
+			a.receiveDATIS(msg)
+			continue
+		}
+		// 其余只关心 ACK 报文
 		if msg.GetBaseMessage().Type != MsgTypeAck {
 			continue
 		}
-		// 尝试解析 ACK 数据
-		var ackData AcknowledgementData
-		// GetData() 返回的是 json.RawMessage，需要先转换
-		if rawData, ok := msg.GetData().(json.RawMessage); ok {
-			if err := json.Unmarshal(rawData, &ackData); err != nil {
-				continue // 解析失败，忽略
+		ackData, ok := decodeAck(msg)
+		if !ok {
+			continue // 解析失败，忽略
+		}
+
+		// 累积 ACK 一帧确认多个报文 (可能属于不同飞机)，逐条检查是否是我们正在等待的
+		for _, ack := range ackData.Entries() {
+			waiterChan, ok := a.ackWaiters.Load(ack.OriginalMessageID)
+			if !ok {
+				continue
+			}
+			if ack.IsNack() {
+				slog.Warn("🚫 报文被地面站拒绝", "flight", a.CurrentFlightID, "msgID", ack.OriginalMessageID, "reason", ack.Reason)
+			} else {
+				slog.Debug("🎉 成功收到 ACK", "flight", a.CurrentFlightID, "msgID", ack.OriginalMessageID)
+				// ACK 中携带了原始报文的发送时间，据此计算从报文发出到收到 ACK 的真实往返时间
+				if !ack.OriginalTimestamp.IsZero() {
+					a.recordAckRTT(simSince(ack.OriginalTimestamp))
+				}
+			}
+			// 发送信号 (NACK 为 false)，通知等待的 goroutine；重复的 ACK 不应阻塞监听循环
+			select {
+			case waiterChan.(chan bool) <- !ack.IsNack():
+			default:
 			}
-		} else {
-			continue
 		}
+	}
+}
+
+// decodeAck 解析 ACK 报文的数据。
+func decodeAck(msg ACARSMessageInterface) (AcknowledgementData, bool) {
+	var ackData AcknowledgementData
+	// GetData() 返回的是 json.RawMessage，需要先转换
+	rawData, ok := msg.GetData().(json.RawMessage)
+	if !ok || json.Unmarshal(rawData, &ackData) != nil {
+		return AcknowledgementData{}, false
+	}
+	return ackData, true
+}
 
-		// 检查这个 ACK 是否是我们正在等待的
-		if waiterChan, ok := a.ackWaiters.Load(ackData.OriginalMessageID); ok {
-			log.Printf("🎉 [飞机 %s] 成功收到对报文 %s 的 ACK!", a.CurrentFlightID, ackData.OriginalMessageID)
-			// 发送信号，通知等待的 goroutine
-			waiterChan.(chan bool) <- true
+// awaitedAcks 返回错过的报文 msg 中本机正在等待的 ACK 所确认的报文ID，msg 不是有效的 ACK 时返回 nil。
+func (a *Aircraft) awaitedAcks(msg ACARSMessageInterface) []string {
+	if msg.GetBaseMessage().Type != MsgTypeAck || !verifyChecksum(msg) {
+		return nil
+	}
+	ackData, ok := decodeAck(msg)
+	if !ok {
+		return nil
+	}
+	var awaited []string
+	for _, ack := range ackData.Entries() {
+		if _, waiting := a.ackWaiters.Load(ack.OriginalMessageID); waiting {
+			awaited = append(awaited, ack.OriginalMessageID)
 		}
 	}
+	return awaited
+}
+
+// missWhileTransmitting 记录半双工模式下因本机正在发送而错过的 ACK (只统计本机正在等待的)。
+func (a *Aircraft) missWhileTransmitting(msg ACARSMessageInterface) {
+	for _, msgID := range a.awaitedAcks(msg) {
+		atomic.AddUint64(&a.ackMissedDueToTx, 1)
+		slog.Debug("📵 正在发送，错过 ACK", "flight", a.CurrentFlightID, "msgID", msgID)
+	}
 }
 
+// missOffChannel 记录可调谐接收机因正在切换信道或调谐在其他信道而错过的 ACK (只统计本机正在等待的)。
+func (a *Aircraft) missOffChannel(msg ACARSMessageInterface, settling bool) {
+	for _, msgID := range a.awaitedAcks(msg) {
+		if settling {
+			atomic.AddUint64(&a.ackMissedTuning, 1)
+			slog.Debug("📻 接收机正在切换信道，错过 ACK", "flight", a.CurrentFlightID, "msgID", msgID)
+		} else {
+			atomic.AddUint64(&a.ackMissedOffTuned, 1)
+			slog.Debug("📻 接收机调谐在其他信道，错过 ACK", "flight", a.CurrentFlightID, "msgID", msgID)
+		}
+	}
+}
+
+// SendMessage 发送一个报文：竞争信道、等待 ACK，超时后重传，直到送达或成为死信。
 func (a *Aircraft) SendMessage(msg ACARSMessageInterface, comms *CommunicationSystem) {
+	a.SendMessageNotify(msg, comms, nil)
+}
+
+// SendMessageNotify 与 SendMessage 相同，并在报文的最终结果确定后调用 onDone：
+// reason 为空表示报文已送达，否则为其成为死信的原因。onDone 为 nil 时不通知。
+func (a *Aircraft) SendMessageNotify(msg ACARSMessageInterface, comms *CommunicationSystem, onDone func(reason DeadLetterReason)) {
+	reason := a.send(msg, comms)
+	if reason == "" {
+		logMessageEvent(MessageEventAcked, a.CurrentFlightID, msg.GetBaseMessage().MessageID, "", "")
+	} else {
+		logMessageEvent(MessageEventDropped, a.CurrentFlightID, msg.GetBaseMessage().MessageID, "", string(reason))
+	}
+	if onDone != nil {
+		onDone(reason)
+	}
+}
+
+// send 执行报文的发送、等待 ACK 与重传流程，返回报文的最终结果 (空表示已送达)。
+func (a *Aircraft) send(msg ACARSMessageInterface, comms *CommunicationSystem) DeadLetterReason {
 	// 1. 函数签名已更新，移除了 timeSlot time.Duration 参数
 	baseMsg := msg.GetBaseMessage()
 	sendStartTime := time.Now()
+	var txTime time.Time // 最近一次成功发出报文的时间
+	from := sender{logKey: "flight", id: a.CurrentFlightID, prepare: a.prepareFrame, receiver: a.listener, outbound: &a.deadLetters}
+	if config.EnableHalfDuplex {
+		from.halfDuplex = a.listener
+	}
+	if !a.admitOutbound(&a.deadLetters, msg, from, sendStartTime) {
+		return DeadLetterQueueOverflow
+	}
 
 	for retries := 0; retries < config.MaxRetries; retries++ {
-		log.Printf("🚀 [飞机 %s] 准备发送报文 (ID: %s, Prio: %s), 尝试次数: %d/%d", a.CurrentFlightID, baseMsg.MessageID, msg.GetPriority(), retries+1, config.MaxRetries)
+		slog.Debug("🚀 准备发送报文", "flight", a.CurrentFlightID, "msgID", baseMsg.MessageID, "priority", msg.GetPriority(), "attempt", retries+1, "maxRetries", config.MaxRetries)
 		if retries > 0 {
 			atomic.AddUint64(&a.totalRetries, 1)
+			a.recentEvents.record(eventRetry, 0)
 		}
 
-		// --- 核心逻辑: 在每次重试前，都动态选择信道 ---
-		targetChannel := comms.SelectChannelForMessage(msg, a.CurrentFlightID)
-		p := targetChannel.GetPForMessage(msg.GetPriority())
-		// 2. 从选定的目标信道获取其专属的时隙
-		timeSlotForChannel := targetChannel.GetCurrentTimeSlot()
-
-		// 在选定的目标信道上执行 p-坚持 CSMA 算法
-
-		for {
-			atomic.AddUint64(&a.totalRqTunnel, 1)
-			if !targetChannel.IsBusy() {
-				if rand.Float64() < p {
-					// 只有在概率允许时才真正尝试传输，这构成一次“传输尝试”
-					atomic.AddUint64(&a.totalTxAttempts, 1)
-					if targetChannel.AttemptTransmit(msg, a.CurrentFlightID, config.TransmissionTime) {
-						// 传输成功，记录等待时间
-						waitTime := time.Since(sendStartTime)
-						a.totalWaitTimeNs.Add(waitTime.Nanoseconds())
-						// 跳出CSMA循环，去等待ACK
-						goto waitForAck
-					} else {
-						// 传输失败，即发生碰撞
-						atomic.AddUint64(&a.totalCollisions, 1)
-						// 4. 日志增强: 明确指出在哪个信道上发生了碰撞
-						log.Printf("💥 [飞机 %s] 在信道 [%s] 上发生碰撞！", a.CurrentFlightID, targetChannel.ID)
-					}
-				} else {
-					// 4. 日志增强: 明确指出在哪个信道上延迟
-					log.Printf("🤔 [飞机 %s] 在信道 [%s] 上空闲，但决定延迟 (p=%.2f)。", a.CurrentFlightID, targetChannel.ID, p)
-				}
-			} else {
-				atomic.AddUint64(&a.totalFailRqTunnel, 1)
-				// 4. 日志增强: 明确指出哪个信道忙
-				log.Printf("⏳ [飞机 %s] 发现信道 [%s] 忙，持续监听...", a.CurrentFlightID, targetChannel.ID)
-			}
-			// 3. 使用从信道获取的专属时隙进行等待
-			time.Sleep(timeSlotForChannel)
+		// 每次 (重新) 发送前按当前位置确定所在扇区，跨越扇区后改用新扇区的信道
+		a.updateSector(comms)
+		// 在动态选择的目标信道上执行通信系统注入的信道接入策略 (默认 p-坚持 CSMA)，直到获得信道
+		txChannel, sentAt, waitTime := a.acquireChannel(msg, comms, from, sendStartTime)
+		if txChannel == nil {
+			// 等待信道期间被更高优先级的报文挤出发送队列
+			a.deadLetters.resolve(baseMsg.MessageID, DeadLetterQueueOverflow)
+			return DeadLetterQueueOverflow
 		}
+		txTime = sentAt
+		a.recordWaitByPriority(msg.GetPriority(), waitTime)
 
-	waitForAck:
 		// 等待 ACK 或超时的逻辑保持不变
 		ackChan := make(chan bool, 1)
 		a.ackWaiters.Store(baseMsg.MessageID, ackChan)
 
 		select {
-		case <-ackChan:
+		case acked := <-ackChan:
+			if !acked {
+				// NACK: 报文本身有误，重传无济于事，直接记为死信
+				a.ackWaiters.Delete(baseMsg.MessageID)
+				atomic.AddUint64(&a.nacksReceived, 1)
+				a.deadLetters.resolve(baseMsg.MessageID, DeadLetterRejected)
+				return DeadLetterRejected
+			}
 			atomic.AddUint64(&a.successfulTx, 1)
+			a.recentEvents.record(eventSuccess, 0)
+			a.latencies.record(msg.GetPriority(), simSince(sendStartTime))
+			if inFaultStorm(sendStartTime) {
+				a.stormLatencies.record(msg.GetPriority(), simSince(sendStartTime))
+			}
 			a.ackWaiters.Delete(baseMsg.MessageID)
-			log.Printf("✅ [飞机 %s] 报文 (ID: %s) 发送流程完成！", a.CurrentFlightID, baseMsg.MessageID)
-			return
-		case <-time.After(config.AckTimeout):
+			a.deadLetters.resolve(baseMsg.MessageID, "")
+			if baseMsg.Type == MsgTypeLinkTest {
+				a.recordLinkTestRTT(simSince(txTime))
+			}
+			slog.Debug("✅ 报文发送流程完成", "flight", a.CurrentFlightID, "msgID", baseMsg.MessageID)
+			return ""
+		case <-time.After(Scaled(a.ackTimeoutFor(msg.GetPriority(), comms.isBackup(txChannel)))):
 			a.ackWaiters.Delete(baseMsg.MessageID)
-			log.Printf("⏰ [飞机 %s] 等待报文 (ID: %s) 的 ACK 超时！准备重发...", a.CurrentFlightID, baseMsg.MessageID)
+			slog.Info("⏰ 等待 ACK 超时，准备重发", "flight", a.CurrentFlightID, "msgID", baseMsg.MessageID)
 		}
 	}
 
-	log.Printf("❌ [飞机 %s] 报文 (ID: %s) 发送失败，已达到最大重试次数。", a.CurrentFlightID, baseMsg.MessageID)
+	atomic.AddUint64(&a.permanentFailures, 1)
+	a.deadLetters.resolve(baseMsg.MessageID, DeadLetterMaxRetriesExceeded)
+	slog.Warn("❌ 报文发送失败，已达到最大重试次数", "flight", a.CurrentFlightID, "msgID", baseMsg.MessageID)
+	return DeadLetterMaxRetriesExceeded
+}
+
+// registerStation 登记一个地面站的覆盖区域，用于判断是否能收到该站的广播。
+func (a *Aircraft) registerStation(stationID string, coverage CoverageRegion) {
+	a.positionMutex.Lock()
+	defer a.positionMutex.Unlock()
+	if a.stationCoverage == nil {
+		a.stationCoverage = make(map[string]CoverageRegion)
+	}
+	a.stationCoverage[stationID] = coverage
+}
+
+// receiveDATIS 处理一条 D-ATIS 广播：飞机位于发送站覆盖范围内时记录最新版本，不生成 ACK。
+func (a *Aircraft) receiveDATIS(msg ACARSMessageInterface) {
+	stationID := msg.GetBaseMessage().AircraftICAOAddress
+	a.positionMutex.RLock()
+	coverage, known := a.stationCoverage[stationID]
+	a.positionMutex.RUnlock()
+	pos := a.GetPosition()
+	if !known || !coverage.Contains(pos.Latitude, pos.Longitude) {
+		return
+	}
+
+	var data DATISData
+	rawData, ok := msg.GetData().(json.RawMessage)
+	if !ok || json.Unmarshal(rawData, &data) != nil {
+		return
+	}
+	atomic.AddUint64(&a.datisReceived, 1)
+	a.datisMutex.Lock()
+	a.latestDATIS = &data
+	a.datisMutex.Unlock()
+	slog.Debug("📻 收到 D-ATIS 广播", "flight", a.CurrentFlightID, "station", stationID, "edition", data.Edition)
+}
+
+// LatestDATIS 返回最近收到的 D-ATIS，尚未收到时返回 nil。
+func (a *Aircraft) LatestDATIS() *DATISData {
+	a.datisMutex.Lock()
+	defer a.datisMutex.Unlock()
+	return a.latestDATIS
+}
+
+// DeadLetters 返回所有未能送达的报文，包括当前仍在发送中的报文 (原因记为 PENDING_AT_END)。
+func (a *Aircraft) DeadLetters() []DeadLetter {
+	return a.deadLetters.list()
+}
+
+// setTrack 更新飞机当前的航迹。
+func (a *Aircraft) setTrack(track flightTrack) {
+	a.positionMutex.Lock()
+	defer a.positionMutex.Unlock()
+	now := time.Now()
+	a.deadZoneTime += timeInDeadZone(a.track, a.trackSince, now)
+	a.track, a.trackSince = track, now
+}
+
+// GetPosition 根据当前航迹推算飞机此刻的位置。
+func (a *Aircraft) GetPosition() PositionReportData {
+	a.positionMutex.RLock()
+	defer a.positionMutex.RUnlock()
+	return a.track.positionAt(time.Now())
+}
+
+// recordPositionReport 记录最近一次上报的位置。
+func (a *Aircraft) recordPositionReport(pos PositionReportData) {
+	a.positionMutex.Lock()
+	defer a.positionMutex.Unlock()
+	a.CurrentPosition = &pos
+}
+
+// swapServingStation 将服务地面站切换为 stationID，并返回之前的服务地面站。
+func (a *Aircraft) swapServingStation(stationID string) string {
+	a.positionMutex.Lock()
+	defer a.positionMutex.Unlock()
+	prev := a.servingStationID
+	a.servingStationID = stationID
+	return prev
+}
+
+// recordLinkTestRTT 记录一次链路测试的往返时间。
+func (a *Aircraft) recordLinkTestRTT(rtt time.Duration) {
+	a.linkTestMutex.Lock()
+	defer a.linkTestMutex.Unlock()
+	a.linkTestRTTs = append(a.linkTestRTTs, rtt)
+	slog.Info("🔗 链路测试完成", "flight", a.CurrentFlightID, "rtt", rtt)
+}
+
+// linkTestRTTSummary 返回链路测试的次数，以及往返时间的最小值、平均值和最大值。
+func (a *Aircraft) linkTestRTTSummary() (count int, minRTT, avgRTT, maxRTT time.Duration) {
+	a.linkTestMutex.Lock()
+	defer a.linkTestMutex.Unlock()
+	count = len(a.linkTestRTTs)
+	if count == 0 {
+		return 0, 0, 0, 0
+	}
+	var total time.Duration
+	minRTT = a.linkTestRTTs[0]
+	for _, rtt := range a.linkTestRTTs {
+		total += rtt
+		minRTT = min(minRTT, rtt)
+		maxRTT = max(maxRTT, rtt)
+	}
+	return count, minRTT, total / time.Duration(count), maxRTT
+}
+
+// recordAckRTT 记录一次从报文发出到收到 ACK 的往返时间。
+func (a *Aircraft) recordAckRTT(rtt time.Duration) {
+	a.ackRttNs.Add(rtt.Nanoseconds())
+	a.ackRTTMutex.Lock()
+	defer a.ackRTTMutex.Unlock()
+	a.ackRTTs = append(a.ackRTTs, rtt)
+}
+
+// recordWaitByPriority 记录一个报文从开始发送到获得信道的等待时间，按报文自身的优先级统计最大值。
+func (a *Aircraft) recordWaitByPriority(priority config.Priority, wait time.Duration) {
+	a.maxWaitMutex.Lock()
+	defer a.maxWaitMutex.Unlock()
+	if a.maxWaitByPriority == nil {
+		a.maxWaitByPriority = make(map[config.Priority]time.Duration)
+	}
+	a.maxWaitByPriority[priority] = max(a.maxWaitByPriority[priority], wait)
+}
+
+// ackRTTSummary 返回 ACK 往返时间的样本数，以及最小值、平均值和 95 分位数。
+func (a *Aircraft) ackRTTSummary() (count int, minRTT, avgRTT, p95RTT time.Duration) {
+	a.ackRTTMutex.Lock()
+	samples := append([]time.Duration(nil), a.ackRTTs...)
+	a.ackRTTMutex.Unlock()
+
+	count = len(samples)
+	if count == 0 {
+		return 0, 0, 0, 0
+	}
+	slices.Sort(samples)
+	p95Index := (count*95+99)/100 - 1
+	return count, samples[0], time.Duration(a.ackRttNs.Load()) / time.Duration(count), samples[p95Index]
 }
 
 func (a *Aircraft) ResetStats() {
-	atomic.StoreUint64(&a.totalTxAttempts, 0)
-	atomic.StoreUint64(&a.totalCollisions, 0)
+	a.resetTransmitStats()
 	atomic.StoreUint64(&a.successfulTx, 0)
 	atomic.StoreUint64(&a.totalRetries, 0)
-	a.totalWaitTimeNs.Store(0)
+	atomic.StoreUint64(&a.permanentFailures, 0)
+	atomic.StoreUint64(&a.nacksReceived, 0)
+	atomic.StoreUint64(&a.checksumFailures, 0)
+	atomic.StoreUint64(&a.sectorCrossings, 0)
+	atomic.StoreUint64(&a.ackMissedDueToTx, 0)
+	atomic.StoreUint64(&a.ackMissedTuning, 0)
+	atomic.StoreUint64(&a.ackMissedOffTuned, 0)
+	atomic.StoreUint64(&a.linkQualityLosses, 0)
+	atomic.StoreUint64(&a.deadZoneLosses, 0)
+	a.positionMutex.Lock()
+	a.deadZoneTime = 0
+	if !a.trackSince.IsZero() {
+		a.trackSince = time.Now()
+	}
+	a.positionMutex.Unlock()
+
+	a.linkTestMutex.Lock()
+	a.linkTestRTTs = nil
+	a.linkTestMutex.Unlock()
+	a.deadLetters.reset()
+
+	a.ackRttNs.Store(0)
+	a.ackRTTMutex.Lock()
+	a.ackRTTs = nil
+	a.ackRTTMutex.Unlock()
+
+	a.maxWaitMutex.Lock()
+	a.maxWaitByPriority = nil
+	a.maxWaitMutex.Unlock()
+	a.latencies.reset()
+	a.stormLatencies.reset()
+
+	a.listener.resetDrops()
+	a.listener.resetRetunes()
+	atomic.StoreUint64(&a.datisReceived, 0)
+	atomic.StoreUint64(&a.weatherRequestsSent, 0)
+	atomic.StoreUint64(&a.weatherRepliesReceived, 0)
 }
 
 // AircraftRawStats Excel自动统计需要以下两个函数
 type AircraftRawStats struct {
-	SuccessfulTx      uint64
-	TotalTxAttempts   uint64
-	TotalCollisions   uint64
-	TotalRetries      uint64
-	TotalRqTunnel     uint64
-	TotalFailRqTunnel uint64
-	TotalWaitTime     time.Duration
+	SuccessfulTx       uint64
+	TotalTxAttempts    uint64
+	TotalCollisions    uint64
+	TotalRetries       uint64
+	TotalRqTunnel      uint64
+	TotalFailRqTunnel  uint64
+	TotalWaitTime      time.Duration
+	ForcedSwitchovers  uint64
+	PermanentFailures  uint64
+	NacksReceived      uint64
+	ChecksumFailures   uint64
+	QueueOverflowDrops uint64
+	SectorCrossings    uint64
+	AckMissedDueToTx   uint64
+	AckMissedTuning    uint64 // 可调谐接收机切换信道期间错过的 ACK 数
+	AckMissedOffTuned  uint64 // 可调谐接收机调谐在其他信道时错过的 ACK 数
+	ReceiverRetunes    uint64 // 可调谐接收机切换信道的次数
+	LinkQualityLosses  uint64
+	DeadZoneLosses     uint64
+	DeadZoneTime       time.Duration
+
+	LinkTestCount  int
+	LinkTestRTTMin time.Duration
+	LinkTestRTTAvg time.Duration
+	LinkTestRTTMax time.Duration
+
+	AckRTTCount int
+	AckRTTMin   time.Duration
+	AckRTTAvg   time.Duration
+	AckRTTP95   time.Duration
+
+	MaxWaitByPriority map[config.Priority]time.Duration
+
+	Latency LatencyPercentiles // 所有优先级报文的端到端时延
+
+	ListenerDrops uint64
+	InboxDepth    int
+
+	DATISReceived uint64
+	DATISEdition  string
+
+	WeatherRequests uint64
+	WeatherReplies  uint64
 }
 
 func (a *Aircraft) GetRawStats() AircraftRawStats {
+	linkTestCount, rttMin, rttAvg, rttMax := a.linkTestRTTSummary()
+	ackRTTCount, ackRTTMin, ackRTTAvg, ackRTTP95 := a.ackRTTSummary()
+
+	a.maxWaitMutex.Lock()
+	maxWait := make(map[config.Priority]time.Duration, len(a.maxWaitByPriority))
+	for priority, wait := range a.maxWaitByPriority {
+		maxWait[priority] = wait
+	}
+	a.maxWaitMutex.Unlock()
+
+	var datisEdition string
+	if datis := a.LatestDATIS(); datis != nil {
+		datisEdition = datis.Edition
+	}
+
 	return AircraftRawStats{
-		SuccessfulTx:      atomic.LoadUint64(&a.successfulTx),
-		TotalTxAttempts:   atomic.LoadUint64(&a.totalTxAttempts),
-		TotalCollisions:   atomic.LoadUint64(&a.totalCollisions),
-		TotalRetries:      atomic.LoadUint64(&a.totalRetries),
-		TotalRqTunnel:     atomic.LoadUint64(&a.totalRqTunnel),
-		TotalFailRqTunnel: atomic.LoadUint64(&a.totalFailRqTunnel),
-		TotalWaitTime:     time.Duration(a.totalWaitTimeNs.Load()),
+		SuccessfulTx:       atomic.LoadUint64(&a.successfulTx),
+		TotalTxAttempts:    atomic.LoadUint64(&a.totalTxAttempts),
+		TotalCollisions:    atomic.LoadUint64(&a.totalCollisions),
+		TotalRetries:       atomic.LoadUint64(&a.totalRetries),
+		TotalRqTunnel:      atomic.LoadUint64(&a.totalRqTunnel),
+		TotalFailRqTunnel:  atomic.LoadUint64(&a.totalFailRqTunnel),
+		TotalWaitTime:      time.Duration(a.totalWaitTimeNs.Load()),
+		ForcedSwitchovers:  atomic.LoadUint64(&a.forcedSwitchovers),
+		PermanentFailures:  atomic.LoadUint64(&a.permanentFailures),
+		NacksReceived:      atomic.LoadUint64(&a.nacksReceived),
+		ChecksumFailures:   atomic.LoadUint64(&a.checksumFailures),
+		QueueOverflowDrops: atomic.LoadUint64(&a.queueOverflowDrops),
+		SectorCrossings:    atomic.LoadUint64(&a.sectorCrossings),
+		AckMissedDueToTx:   atomic.LoadUint64(&a.ackMissedDueToTx),
+		AckMissedTuning:    atomic.LoadUint64(&a.ackMissedTuning),
+		AckMissedOffTuned:  atomic.LoadUint64(&a.ackMissedOffTuned),
+		ReceiverRetunes:    a.listener.Retunes(),
+		LinkQualityLosses:  atomic.LoadUint64(&a.linkQualityLosses),
+		DeadZoneLosses:     atomic.LoadUint64(&a.deadZoneLosses),
+		DeadZoneTime:       a.DeadZoneTime(),
+
+		LinkTestCount:  linkTestCount,
+		LinkTestRTTMin: rttMin,
+		LinkTestRTTAvg: rttAvg,
+		LinkTestRTTMax: rttMax,
+
+		AckRTTCount: ackRTTCount,
+		AckRTTMin:   ackRTTMin,
+		AckRTTAvg:   ackRTTAvg,
+		AckRTTP95:   ackRTTP95,
+
+		MaxWaitByPriority: maxWait,
+
+		Latency: a.LatencyPercentiles(""),
+
+		ListenerDrops: a.listener.Drops(),
+		InboxDepth:    a.listener.Depth(),
+
+		DATISReceived: atomic.LoadUint64(&a.datisReceived),
+		DATISEdition:  datisEdition,
+
+		WeatherRequests: atomic.LoadUint64(&a.weatherRequestsSent),
+		WeatherReplies:  atomic.LoadUint64(&a.weatherRepliesReceived),
 	}
 }
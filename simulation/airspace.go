@@ -0,0 +1,96 @@
+package simulation
+
+import (
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 空域满容量时进港航班可选的处理策略
+const (
+	AdmissionHold   = "hold"   // 在空域外等待 (盘旋) 直到有空位
+	AdmissionDivert = "divert" // 备降其他机场，不再进入本空域
+)
+
+// Airspace 模拟终端管制区 (TMA) 的容量限制，对同时在空域内活动的航班数进行准入控制。
+// 离港航班在满容量时只能地面等待；进港航班按 Policy 等待或备降。
+type Airspace struct {
+	Capacity int    // 同时活动航班数上限，0 表示不限制
+	Policy   string // 进港航班在满容量时的处理策略
+
+	mutex sync.Mutex
+	freed *sync.Cond // 有航班离开空域时通知等待者
+
+	active        int           // 当前活动航班数，受 mutex 保护
+	peakActive    int           // 最大同时活动航班数，受 mutex 保护
+	held          atomic.Uint64 // 因满容量而等待过的航班数
+	diverted      atomic.Uint64 // 因满容量而备降的进港航班数
+	totalHoldTime atomic.Int64  // 累计等待时间 (纳秒)
+}
+
+// NewAirspace 创建一个容量为 capacity 的空域。
+func NewAirspace(capacity int, policy string) *Airspace {
+	a := &Airspace{Capacity: capacity, Policy: policy}
+	a.freed = sync.NewCond(&a.mutex)
+	return a
+}
+
+// admit 为航班申请进入空域。返回 false 表示航班已备降，不应继续执行飞行计划。
+func (a *Airspace) admit(flightID string, arriving bool) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.Capacity > 0 && a.active >= a.Capacity {
+		if arriving && a.Policy == AdmissionDivert {
+			a.diverted.Add(1)
+			slog.Warn("🔀 空域已满，进港航班备降", "flight", flightID, "active", a.active, "capacity", a.Capacity)
+			return false
+		}
+		a.held.Add(1)
+		slog.Info("🌀 空域已满，航班等待进入", "flight", flightID, "active", a.active, "capacity", a.Capacity)
+		holdStart := time.Now()
+		for a.active >= a.Capacity {
+			a.freed.Wait()
+		}
+		holdTime := simSince(holdStart)
+		a.totalHoldTime.Add(holdTime.Nanoseconds())
+		slog.Info("🌀 航班结束等待，进入空域", "flight", flightID, "holdTime", holdTime)
+	}
+
+	a.active++
+	a.peakActive = max(a.peakActive, a.active)
+	return true
+}
+
+// release 在航班离开空域时释放其占用的容量。
+func (a *Airspace) release() {
+	a.mutex.Lock()
+	a.active--
+	a.mutex.Unlock()
+	a.freed.Signal()
+}
+
+// AirspaceRawStats 定义了用于数据收集的空域原始统计数据。
+type AirspaceRawStats struct {
+	Capacity      int
+	Active        int
+	PeakActive    int
+	Held          uint64
+	Diverted      uint64
+	TotalHoldTime time.Duration
+}
+
+// GetRawStats 返回原始统计数据，用于写入报告。
+func (a *Airspace) GetRawStats() AirspaceRawStats {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return AirspaceRawStats{
+		Capacity:      a.Capacity,
+		Active:        a.active,
+		PeakActive:    a.peakActive,
+		Held:          a.held.Load(),
+		Diverted:      a.diverted.Load(),
+		TotalHoldTime: time.Duration(a.totalHoldTime.Load()),
+	}
+}
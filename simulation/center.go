@@ -4,8 +4,8 @@ package simulation
 import (
 	"Air-Simulator/config"
 	"fmt"
-	"log"
-	"math/rand"
+	"log/slog"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -13,135 +13,373 @@ import (
 // GroundControlCenter 代表一个地面控制站。
 type GroundControlCenter struct {
 	ID           string
+	Coverage     CoverageRegion             // 地面站的覆盖区域
+	TxPowerDBm   float64                    // 发射功率 (dBm)，决定上行帧在飞机处的接收功率
 	inboundQueue chan ACARSMessageInterface // 自己的内部消息队列
+	listener     *Listener                  // 注册到信道上的收件箱，记录溢出丢弃
+	aircraft     map[string]*Aircraft       // 已知飞机 (按 ICAO 地址索引)，用于判断发送方位置
+
+	recentMessages *recentMessageCache  // 最近收到的报文ID，用于识别重复报文
+	fragments      *fragmentReassembler // 尚未收齐的分片报文
 
 	// --- 通信统计 ---
-	totalTxAttempts   uint64       // 总传输尝试次数 (每次尝试获得信道)
-	totalCollisions   uint64       // 碰撞/信道访问失败次数
-	successfulTx      uint64       // 成功发送并收到ACK的报文总数
-	totalRqTunnel     uint64       // 总请求隧道次数
-	totalFailRqTunnel uint64       // 失败请求隧道次数
-	totalWaitTimeNs   atomic.Int64 // 总等待时间 (纳秒)
+	Transmitter         // 信道接入逻辑与统计
+	successfulTx uint64 // 成功发出的报文总数 (地面站不等待 ACK)
+
+	// --- 死信 ---
+	deadLetters deadLetterBook // 正在发送中以及最终未能送达的报文
+
+	// --- 覆盖与移交统计 ---
+	totalReceived        uint64 // 覆盖范围内收到并处理的报文数，不含重复报文
+	outOfCoverageIgnored uint64 // 因发送方不在覆盖范围内而忽略的报文数
+	handoversIn          uint64 // 从其他地面站移交至本站的次数
+	duplicatesReceived   uint64 // 收到的重复报文数
+	checksumFailures     uint64 // 覆盖范围内收到的校验和错误 (传输中损坏) 的报文数
+
+	datisBroadcasts uint64 // 发布的 D-ATIS 广播次数
+	weatherReplies  uint64 // 对飞机气象请求的上行回复数
+
+	// --- 处理能力 ---
+	processingSlots chan struct{} // 同时处理报文的并发上限 (信号量)，nil 表示不限制
+	processing      atomic.Int64  // 正在处理的报文数
+	awaitingSlot    atomic.Int64  // 已从收件箱取出、正在等待处理槽位的报文数
+
+	// --- ACK 合并 ---
+	acks          ackCoalescer
+	ackFramesSent uint64 // 发出的 ACK 帧数 (累积 ACK 计为一帧)
+	acksSent      uint64 // ACK 帧中确认的报文总数
+	nacksSent     uint64 // 因报文格式错误而发出的 NACK 数
+
+	// --- 离线 (受 outageMutex 保护) ---
+	outageMutex    sync.Mutex
+	available      bool
+	comms          *CommunicationSystem    // 恢复在线时用于补处理缓存的报文
+	outageBuffer   []ACARSMessageInterface // 离线期间缓存的报文
+	outages        []OutageWindow
+	outageBuffered uint64 // 离线期间缓存的报文数
+	outageDropped  uint64 // 离线期间丢弃的报文数
 }
 
 // NewGroundControlCenter 是 GroundControlCenter 的构造函数。
-func NewGroundControlCenter(id string) *GroundControlCenter {
+func NewGroundControlCenter(id string, coverage CoverageRegion) *GroundControlCenter {
+	inboundQueue := make(chan ACARSMessageInterface, config.GroundStationInboxSize) // 为其分配一个带缓冲的队列
+	listener := NewListener(id, inboundQueue)
+	listener.ground = true
+	var processingSlots chan struct{}
+	if config.GroundStationProcessingSlots > 0 {
+		processingSlots = make(chan struct{}, config.GroundStationProcessingSlots)
+	}
 	return &GroundControlCenter{
 		ID:           id,
-		inboundQueue: make(chan ACARSMessageInterface, 50), // 为其分配一个带缓冲的队列
+		Coverage:     coverage,
+		inboundQueue: inboundQueue,
+		listener:     listener,
+		aircraft:     make(map[string]*Aircraft),
+
+		recentMessages: newRecentMessageCache(config.DuplicateCacheSize),
+		fragments:      newFragmentReassembler(),
+
+		available:       true,
+		processingSlots: processingSlots,
+
+		TxPowerDBm:  config.GroundStationTxPowerDBm,
+		Transmitter: Transmitter{MaxQueueLength: config.MaxOutboundQueueLength},
 	}
 }
 
+// TrackAircraft 登记地面站需要服务的飞机，必须在 StartListening 之前调用。
+func (gcc *GroundControlCenter) TrackAircraft(aircraftList []*Aircraft) {
+	for _, a := range aircraftList {
+		gcc.aircraft[a.ICAOAddress] = a
+		a.registerStation(gcc.ID, gcc.Coverage)
+	}
+}
+
+// coversSender 判断报文发送方当前是否位于本站覆盖范围内。
+// 未知的发送方 (例如其他地面站) 一律视为不在覆盖范围内。
+func (gcc *GroundControlCenter) coversSender(icao string) (*Aircraft, bool) {
+	a, ok := gcc.aircraft[icao]
+	if !ok {
+		return nil, false
+	}
+	pos := a.GetPosition()
+	return a, gcc.Coverage.Contains(pos.Latitude, pos.Longitude)
+}
+
 // StartListening 启动地面站的监听服务。
 // 它现在向整个通信系统注册自己。
 func (gcc *GroundControlCenter) StartListening(commsSystem *CommunicationSystem) {
 	// 向通信系统注册自己的接收队列
-	commsSystem.RegisterListener(gcc.inboundQueue)
-	log.Printf("🛰️  地面站 [%s] 已启动，开始监听通信系统...", gcc.ID)
+	commsSystem.RegisterReceiver(gcc.listener, false) // 上下行分频时只接收下行报文
+	gcc.outageMutex.Lock()
+	gcc.comms = commsSystem
+	gcc.outageMutex.Unlock()
+	slog.Info("🛰️  地面站已启动，开始监听通信系统", "station", gcc.ID)
 
 	// 开启一个循环，专门处理自己队列中的消息
 	for msg := range gcc.inboundQueue {
-		// 为每个消息启动一个 goroutine 进行处理，以实现并发
-		go gcc.processMessage(msg, commsSystem)
+		// 为每个消息启动一个 goroutine 进行处理，以实现并发；处理槽位已满时在此等待
+		gcc.startProcessing(msg, commsSystem)
 	}
 }
 
+// startProcessing 在获得处理槽位后异步处理一个报文。槽位已满时阻塞直到有报文处理完毕，
+// 期间后续报文在收件箱中排队。
+func (gcc *GroundControlCenter) startProcessing(msg ACARSMessageInterface, commsSystem *CommunicationSystem) {
+	if gcc.processingSlots != nil {
+		gcc.awaitingSlot.Add(1)
+		gcc.processingSlots <- struct{}{}
+		gcc.awaitingSlot.Add(-1)
+	}
+	gcc.processing.Add(1)
+	go func() {
+		defer func() {
+			gcc.processing.Add(-1)
+			if gcc.processingSlots != nil {
+				<-gcc.processingSlots
+			}
+		}()
+		gcc.processMessage(msg, commsSystem)
+	}()
+}
+
 // processMessage 是内部处理方法，处理单个报文并发送 ACK。
 func (gcc *GroundControlCenter) processMessage(msg ACARSMessageInterface, commsSystem *CommunicationSystem) {
 	baseMsg := msg.GetBaseMessage()
 
-	// 如果是自己发出的消息，应当不进行任何操作。
-	if baseMsg.AircraftICAOAddress == gcc.ID {
+	// 地面站发出的报文 (自己或其他地面站的 ACK、D-ATIS 广播) 不需要确认，应当不进行任何操作。
+	// 按报文头部的发送方角色判断，而不是比较 ICAO 地址与地面站ID，避免多个地面站之间互相确认。
+	if !baseMsg.Ackable() {
+		return
+	}
+
+	// 只应答覆盖范围内飞机的报文，覆盖区外的报文由其他地面站负责
+	sender, inCoverage := gcc.coversSender(baseMsg.AircraftICAOAddress)
+	if !inCoverage {
+		atomic.AddUint64(&gcc.outOfCoverageIgnored, 1)
+		return
+	}
+	// 离线期间不处理报文，也不回复 ACK；覆盖区外的报文本就不归本站处理，不占用离线缓存
+	if gcc.holdDuringOutage(msg) {
+		return
+	}
+	// 校验和不一致的报文已在传输中损坏，视同丢失，不回复 ACK
+	if !verifyChecksum(msg) {
+		atomic.AddUint64(&gcc.checksumFailures, 1)
+		slog.Debug("🧮 报文校验和错误，丢弃", "station", gcc.ID, "msgID", baseMsg.MessageID)
+		return
+	}
+	if prev := sender.swapServingStation(gcc.ID); prev != gcc.ID {
+		atomic.AddUint64(&gcc.handoversIn, 1)
+		if prev == "" {
+			slog.Info("📶 飞机进入覆盖范围，开始提供服务", "station", gcc.ID, "flight", sender.CurrentFlightID)
+		} else {
+			slog.Info("🔀 飞机已移交至本站", "station", gcc.ID, "flight", sender.CurrentFlightID, "from", prev)
+		}
+	}
+
+	// 重传可能导致同一报文被收到多次：重复报文不再处理，但仍需重发 ACK (原 ACK 很可能已丢失)
+	duplicate := gcc.recentMessages.seen(baseMsg.MessageID)
+	if duplicate {
+		atomic.AddUint64(&gcc.duplicatesReceived, 1)
+		slog.Debug("🔁 收到重复报文，跳过处理，仅重发 ACK", "station", gcc.ID, "msgID", baseMsg.MessageID)
+	} else {
+		gcc.countReceived()
+	}
+
+	// 格式错误的报文重传也无济于事，回复 NACK 让发送方立即放弃；重复收到时同样重发 NACK
+	if err := validateMessage(msg); err != nil {
+		atomic.AddUint64(&gcc.nacksSent, 1)
+		slog.Warn("🚫 报文格式错误，回复 NACK", "station", gcc.ID, "msgID", baseMsg.MessageID, "reason", err)
+		nackData := AcknowledgementData{
+			OriginalMessageID: baseMsg.MessageID,
+			Status:            AckStatusFailed,
+			OriginalTimestamp: baseMsg.Timestamp,
+			Reason:            err.Error(),
+		}
+		if config.AckCoalesceWindow > 0 {
+			gcc.queueAck(nackData, baseMsg.AircraftICAOAddress, commsSystem)
+			return
+		}
+		gcc.sendAck(fmt.Sprintf("NACK-%s", baseMsg.MessageID), nackData, 1, []string{baseMsg.AircraftICAOAddress}, commsSystem)
 		return
 	}
+	if !duplicate {
+		// 模拟处理延迟
+		time.Sleep(Scaled(config.ProcessingDelay))
+		slog.Debug("✅ 报文处理完毕，准备发送高优先级 ACK", "station", gcc.ID, "msgID", baseMsg.MessageID)
+	}
 
-	// 模拟处理延迟
-	time.Sleep(config.ProcessingDelay)
+	// 气象请求在 ACK 之后上行回复；重复的请求不再回复
+	if baseMsg.Type == MsgTypeWeatherRequest && !duplicate {
+		defer gcc.replyWeather(msg, commsSystem)
+	}
 
-	log.Printf("✅ [%s] 报文 %s 处理完毕，准备发送高优先级 ACK...", gcc.ID, baseMsg.MessageID)
+	// 分片报文先缓存，全部到齐后重组为原始报文。
+	// 每个分片都单独确认，丢失的分片由发送方单独重传，无需重发整组报文。
+	status := "RECEIVED"
+	if baseMsg.IsFragment() && !duplicate {
+		status = "FRAGMENT_RECEIVED"
+		if original, complete := gcc.fragments.add(msg); complete {
+			status = "REASSEMBLED"
+			slog.Debug("🧩 分片已全部收到并完成重组", "station", gcc.ID, "msgID", original.GetBaseMessage().MessageID, "fragments", baseMsg.FragmentCount)
+		}
+	}
 
-	// 创建 ACK 报文
+	// 创建 ACK 报文。链路测试报文的 ACK 即为地面站的测试回复
 	ackData := AcknowledgementData{
 		OriginalMessageID: baseMsg.MessageID,
-		Status:            "RECEIVED",
+		Status:            status,
+		OriginalTimestamp: baseMsg.Timestamp,
 	}
+	if baseMsg.Type == MsgTypeLinkTest {
+		ackData.Status = "LINK_TEST_OK"
+	}
+	if config.AckCoalesceWindow > 0 {
+		gcc.queueAck(ackData, baseMsg.AircraftICAOAddress, commsSystem)
+		return
+	}
+	gcc.sendAck(fmt.Sprintf("ACK-%s", baseMsg.MessageID), ackData, 1, []string{baseMsg.AircraftICAOAddress}, commsSystem)
+}
+
+// countReceived 记录一个收到并处理的报文。
+func (gcc *GroundControlCenter) countReceived() {
+	atomic.AddUint64(&gcc.totalReceived, 1)
+	gcc.recentEvents.record(eventReceived, 0)
+}
+
+// sendAck 创建一个确认 ackCount 个报文、发给 recipients (飞机 ICAO 地址) 的 ACK 帧，并异步发送回通信系统。
+func (gcc *GroundControlCenter) sendAck(messageID string, ackData AcknowledgementData, ackCount int, recipients []string, commsSystem *CommunicationSystem) {
 	ackBaseMsg := ACARSBaseMessage{
 		AircraftICAOAddress: gcc.ID,
 		FlightID:            "GND_CTL",
-		MessageID:           fmt.Sprintf("ACK-%s", baseMsg.MessageID),
+		MessageID:           messageID,
 		Timestamp:           time.Now(),
 		Type:                MsgTypeAck,
+		Origin:              OriginGround,
+		Recipients:          recipients,
 	}
 
 	// 使用我们为 ACK 创建的专用高优先级构造函数
 	ackMessage, err := NewCriticalPriorityMessage(ackBaseMsg, ackData)
 	if err != nil {
-		log.Printf("错误: [%s] 创建 ACK 报文失败: %v", gcc.ID, err)
+		slog.Error("创建 ACK 报文失败", "station", gcc.ID, "msgID", messageID, "err", err)
 		return
 	}
+	atomic.AddUint64(&gcc.ackFramesSent, 1)
+	atomic.AddUint64(&gcc.acksSent, uint64(ackCount))
 
 	// 调用 SendMessage 将 ACK 发送回通信系统
 	go gcc.SendMessage(ackMessage, commsSystem)
 }
 
-// SendMessage 使用 p-坚持 CSMA 算法在选定的信道上发送报文。
+// BroadcastDATIS 在信道上广播一次 D-ATIS。广播经由信道投递给所有监听者，
+// 覆盖范围内的飞机记录最新版本但不回复 ACK，因此只需竞争到信道、发送一次即可。
+// 地面站离线时跳过本次广播。
+func (gcc *GroundControlCenter) BroadcastDATIS(data DATISData, commsSystem *CommunicationSystem) {
+	if !gcc.IsAvailable() {
+		slog.Debug("📻 地面站离线，跳过 D-ATIS 广播", "station", gcc.ID, "edition", data.Edition)
+		return
+	}
+	baseMsg := ACARSBaseMessage{
+		AircraftICAOAddress: gcc.ID,
+		FlightID:            "GND_CTL",
+		MessageID:           fmt.Sprintf("DATIS-%s-%s-%d", gcc.ID, data.Edition, time.Now().Unix()),
+		Timestamp:           time.Now(),
+		Type:                MsgTypeDATIS,
+		Origin:              OriginGround,
+		Broadcast:           true,
+	}
+	msg, err := NewMediumLowPriorityMessage(baseMsg, data)
+	if err != nil {
+		slog.Error("创建 D-ATIS 广播失败", "station", gcc.ID, "edition", data.Edition, "err", err)
+		return
+	}
+	slog.Info("📻 发布 D-ATIS 广播", "station", gcc.ID, "edition", data.Edition)
+	atomic.AddUint64(&gcc.datisBroadcasts, 1)
+	gcc.SendMessage(msg, commsSystem)
+}
+
+// StartDATISBroadcasts 每隔 interval 广播一次新版本的 D-ATIS，直到 done 被关闭。它应该在一个单独的goroutine中运行。
+func (gcc *GroundControlCenter) StartDATISBroadcasts(commsSystem *CommunicationSystem, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(Scaled(interval))
+	defer ticker.Stop()
+	for edition := 0; ; edition++ {
+		data := DATISData{
+			AirportICAO: "ZBAA",
+			Edition:     datisEditions[edition%len(datisEditions)],
+			Content:     "RWY 36R IN USE. WIND 270/10. VIS 10KM. QNH 1012.",
+		}
+		go gcc.BroadcastDATIS(data, commsSystem)
+
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		}
+	}
+}
+
+// datisEditions 是 D-ATIS 版本的轮换代号
+var datisEditions = []string{"ALPHA", "BRAVO", "CHARLIE", "DELTA", "ECHO", "FOXTROT", "GOLF", "HOTEL"}
+
+// SendMessage 使用通信系统的信道接入策略 (默认 p-坚持 CSMA) 在选定的信道上发送报文。
 // 它会持续尝试直到发送成功。
 func (gcc *GroundControlCenter) SendMessage(msg ACARSMessageInterface, commsSystem *CommunicationSystem) {
 	baseMsg := msg.GetBaseMessage()
 	sendStartTime := time.Now()
 
-	log.Printf("🚀 [%s] 准备发送 ACK (ID: %s, Prio: %s)", gcc.ID, baseMsg.MessageID, msg.GetPriority())
-
-	// 地面站将持续尝试发送 ACK 直到成功
-	for {
-		// 1. 在每次循环时都动态选择最佳信道，以适应信道状态变化
-		targetChannel := commsSystem.SelectChannelForMessage(msg, gcc.ID)
-		p := targetChannel.GetPForMessage(msg.GetPriority())
-		timeSlotForChannel := targetChannel.GetCurrentTimeSlot()
-
-		atomic.AddUint64(&gcc.totalRqTunnel, 1)
-
-		if !targetChannel.IsBusy() {
-			if rand.Float64() < p {
-				// 只有在概率允许时才真正尝试传输，这构成一次“传输尝试”
-				atomic.AddUint64(&gcc.totalTxAttempts, 1)
-
-				// 尝试传输。ACK的传输时间也使用全局常量
-				if targetChannel.AttemptTransmit(msg, gcc.ID, config.TransmissionTime) {
-					// 发送成功！
-					waitTime := time.Since(sendStartTime)
-					gcc.totalWaitTimeNs.Add(waitTime.Nanoseconds())
-					atomic.AddUint64(&gcc.successfulTx, 1)
-					log.Printf("✅ [%s] 在信道 [%s] 上成功发送 ACK (ID: %s)", gcc.ID, targetChannel.ID, baseMsg.MessageID)
-					return // 成功发送后退出函数
-				} else {
-					// 发生碰撞
-					atomic.AddUint64(&gcc.totalCollisions, 1)
-					log.Printf("💥 [%s] 在信道 [%s] 上发送 ACK 时发生碰撞！", gcc.ID, targetChannel.ID)
-				}
-			} else {
-				// p-坚持算法决定延迟
-				log.Printf("🤔 [%s] 信道 [%s] 空闲，但决定延迟发送 ACK (p=%.2f)...", gcc.ID, targetChannel.ID, p)
-			}
-		} else {
-			// 信道忙
-			atomic.AddUint64(&gcc.totalFailRqTunnel, 1)
-			log.Printf("⏳ [%s] 发现信道 [%s] 忙，等待发送 ACK...", gcc.ID, targetChannel.ID)
-		}
-
-		// 2. 等待从目标信道获取的专属时隙，然后重试
-		time.Sleep(timeSlotForChannel)
+	slog.Debug("🚀 准备发送 ACK", "station", gcc.ID, "msgID", baseMsg.MessageID, "priority", msg.GetPriority())
+	from := sender{logKey: "station", id: gcc.ID, uplink: true, outbound: &gcc.deadLetters}
+	if !gcc.admitOutbound(&gcc.deadLetters, msg, from, sendStartTime) {
+		logMessageEvent(MessageEventDropped, gcc.ID, baseMsg.MessageID, "", string(DeadLetterQueueOverflow))
+		return
 	}
+	var targetChannel *Channel
+	if baseMsg.Type == MsgTypeAck && config.AckSendMode == AckSendImmediate {
+		// 专用 ACK 上行链路：不竞争信道，立即送达
+		targetChannel, _ = commsSystem.selectChannel(msg, msg.GetPriority(), from, newSwitchover())
+		targetChannel.deliverDirect(withTimestamp(msg, time.Now()))
+	} else {
+		// 地面站将持续尝试发送 ACK 直到成功获得信道，发出后不等待确认
+		targetChannel, _, _ = gcc.acquireChannel(msg, commsSystem, from, sendStartTime)
+	}
+	if targetChannel == nil {
+		gcc.deadLetters.resolve(baseMsg.MessageID, DeadLetterQueueOverflow)
+		logMessageEvent(MessageEventDropped, gcc.ID, baseMsg.MessageID, "", string(DeadLetterQueueOverflow))
+		return
+	}
+	atomic.AddUint64(&gcc.successfulTx, 1)
+	gcc.recentEvents.record(eventSuccess, 0)
+	gcc.deadLetters.resolve(baseMsg.MessageID, "")
+	slog.Debug("✅ 成功发送 ACK", "station", gcc.ID, "channel", targetChannel.ID, "msgID", baseMsg.MessageID)
+}
+
+// DeadLetters 返回所有未能送达的报文，包括当前仍在发送中的报文 (原因记为 PENDING_AT_END)。
+func (gcc *GroundControlCenter) DeadLetters() []DeadLetter {
+	return gcc.deadLetters.list()
 }
 
 // ResetStats 重置所有统计计数器。
 func (gcc *GroundControlCenter) ResetStats() {
-	atomic.StoreUint64(&gcc.totalTxAttempts, 0)
-	atomic.StoreUint64(&gcc.totalCollisions, 0)
+	gcc.resetTransmitStats()
 	atomic.StoreUint64(&gcc.successfulTx, 0)
-	atomic.StoreUint64(&gcc.totalRqTunnel, 0)
-	atomic.StoreUint64(&gcc.totalFailRqTunnel, 0)
-	gcc.totalWaitTimeNs.Store(0)
+	atomic.StoreUint64(&gcc.totalReceived, 0)
+	atomic.StoreUint64(&gcc.outOfCoverageIgnored, 0)
+	atomic.StoreUint64(&gcc.handoversIn, 0)
+	atomic.StoreUint64(&gcc.duplicatesReceived, 0)
+	atomic.StoreUint64(&gcc.checksumFailures, 0)
+	gcc.deadLetters.reset()
+	gcc.fragments.reset()
+	gcc.listener.resetDrops()
+	atomic.StoreUint64(&gcc.datisBroadcasts, 0)
+	atomic.StoreUint64(&gcc.weatherReplies, 0)
+	atomic.StoreUint64(&gcc.ackFramesSent, 0)
+	atomic.StoreUint64(&gcc.acksSent, 0)
+	atomic.StoreUint64(&gcc.nacksSent, 0)
+	gcc.outageMutex.Lock()
+	gcc.outageBuffered, gcc.outageDropped = 0, 0
+	gcc.outageMutex.Unlock()
 }
 
 // GroundControlRawStats 定义了用于数据收集的原始统计数据结构。
@@ -152,10 +390,40 @@ type GroundControlRawStats struct {
 	TotalRqTunnel     uint64
 	TotalFailRqTunnel uint64
 	TotalWaitTimeNs   time.Duration
+	ForcedSwitchovers uint64
+
+	TotalReceived        uint64
+	OutOfCoverageIgnored uint64
+	HandoversIn          uint64
+	DuplicatesReceived   uint64
+	ChecksumFailures     uint64
+	QueueOverflowDrops   uint64
+
+	FragmentGroupsStarted   uint64
+	FragmentGroupsCompleted uint64
+
+	ListenerDrops     uint64
+	InboxDepth        int
+	Processing        int64 // 正在处理的报文数
+	ProcessingBacklog int   // 等待处理的报文数 (收件箱中的报文与等待处理槽位的报文)
+	DATISBroadcasts   uint64
+	WeatherReplies    uint64
+
+	Available      bool
+	OutageBuffered uint64
+	OutageDropped  uint64
+
+	AckFramesSent uint64
+	AcksSent      uint64
+	NacksSent     uint64
 }
 
 // GetRawStats 返回原始统计数据，用于写入报告。
 func (gcc *GroundControlCenter) GetRawStats() GroundControlRawStats {
+	started, completed := gcc.fragments.stats()
+	gcc.outageMutex.Lock()
+	available, outageBuffered, outageDropped := gcc.available, gcc.outageBuffered, gcc.outageDropped
+	gcc.outageMutex.Unlock()
 	return GroundControlRawStats{
 		SuccessfulTx:      atomic.LoadUint64(&gcc.successfulTx),
 		TotalTxAttempts:   atomic.LoadUint64(&gcc.totalTxAttempts),
@@ -163,5 +431,31 @@ func (gcc *GroundControlCenter) GetRawStats() GroundControlRawStats {
 		TotalRqTunnel:     atomic.LoadUint64(&gcc.totalRqTunnel),
 		TotalFailRqTunnel: atomic.LoadUint64(&gcc.totalFailRqTunnel),
 		TotalWaitTimeNs:   time.Duration(gcc.totalWaitTimeNs.Load()),
+		ForcedSwitchovers: atomic.LoadUint64(&gcc.forcedSwitchovers),
+
+		TotalReceived:        atomic.LoadUint64(&gcc.totalReceived),
+		OutOfCoverageIgnored: atomic.LoadUint64(&gcc.outOfCoverageIgnored),
+		HandoversIn:          atomic.LoadUint64(&gcc.handoversIn),
+		DuplicatesReceived:   atomic.LoadUint64(&gcc.duplicatesReceived),
+		ChecksumFailures:     atomic.LoadUint64(&gcc.checksumFailures),
+		QueueOverflowDrops:   atomic.LoadUint64(&gcc.queueOverflowDrops),
+
+		FragmentGroupsStarted:   started,
+		FragmentGroupsCompleted: completed,
+
+		ListenerDrops:     gcc.listener.Drops(),
+		InboxDepth:        gcc.listener.Depth(),
+		Processing:        gcc.processing.Load(),
+		ProcessingBacklog: gcc.listener.Depth() + int(gcc.awaitingSlot.Load()),
+		DATISBroadcasts:   atomic.LoadUint64(&gcc.datisBroadcasts),
+		WeatherReplies:    atomic.LoadUint64(&gcc.weatherReplies),
+
+		Available:      available,
+		OutageBuffered: outageBuffered,
+		OutageDropped:  outageDropped,
+
+		AckFramesSent: atomic.LoadUint64(&gcc.ackFramesSent),
+		AcksSent:      atomic.LoadUint64(&gcc.acksSent),
+		NacksSent:     atomic.LoadUint64(&gcc.nacksSent),
 	}
 }
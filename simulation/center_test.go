@@ -0,0 +1,165 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 同一报文ID收到两次时只处理一次，但两次都回复 ACK (第一次的 ACK 可能已丢失)。
+func TestDuplicateMessageProcessedOnceAckedTwice(t *testing.T) {
+	comms := newTestComms(newTestChannel("Primary"), nil)
+	gcc := newTestStation("GND")
+	a := newTestAircraft("A00001", "CCA101")
+	gcc.TrackAircraft([]*Aircraft{a})
+
+	msg := newTestMessage(t, a, "CCA101-POS-1", MsgTypePosition, config.HighPriority, a.GetPosition())
+	gcc.processMessage(msg, comms)
+	gcc.processMessage(msg, comms)
+
+	stats := gcc.GetRawStats()
+	if stats.TotalReceived != 1 {
+		t.Errorf("接收数 = %d，重复报文不应计入", stats.TotalReceived)
+	}
+	if stats.DuplicatesReceived != 1 {
+		t.Errorf("重复报文数 = %d，期望 1", stats.DuplicatesReceived)
+	}
+	if got := atomic.LoadUint64(&gcc.acksSent); got != 2 {
+		t.Errorf("ACK 数 = %d，期望 2", got)
+	}
+	waitFor(t, 5*time.Second, "两个 ACK 帧都已发出", func() bool {
+		return gcc.GetRawStats().SuccessfulTx == 2
+	})
+}
+
+// 同一个格式错误的报文收到两次：只计一次接收，两次都回复 NACK，且地面站照常接管对该飞机的服务。
+func TestDuplicateMalformedMessageCountedOnce(t *testing.T) {
+	comms := newTestComms(newTestChannel("Primary"), nil)
+	gcc := newTestStation("GND")
+	a := newTestAircraft("A00001", "CCA101")
+	gcc.TrackAircraft([]*Aircraft{a})
+
+	msg := newTestMessage(t, a, "CCA101-FLT-1", MsgTypeAircraftFault, config.CriticalPriority, AircraftFaultData{FaultCode: "bad code", Severity: "MAJOR", System: "ENGINE_1"})
+	if validateMessage(msg) == nil {
+		t.Fatal("测试报文应当格式错误")
+	}
+	gcc.processMessage(msg, comms)
+	gcc.processMessage(msg, comms)
+
+	stats := gcc.GetRawStats()
+	if stats.TotalReceived != 1 {
+		t.Errorf("接收数 = %d，重复的格式错误报文不应计入", stats.TotalReceived)
+	}
+	if stats.DuplicatesReceived != 1 {
+		t.Errorf("重复报文数 = %d，期望 1", stats.DuplicatesReceived)
+	}
+	if got := atomic.LoadUint64(&gcc.nacksSent); got != 2 {
+		t.Errorf("NACK 数 = %d，期望 2", got)
+	}
+	if prev := a.swapServingStation(gcc.ID); prev != gcc.ID {
+		t.Errorf("飞机的服务地面站 = %q，期望 %q", prev, gcc.ID)
+	}
+}
+
+// 离线的地面站只缓存覆盖范围内飞机的报文，覆盖区外的报文直接忽略，不占用离线缓存。
+func TestOutageHoldsOnlyCoveredMessages(t *testing.T) {
+	comms := newTestComms(newTestChannel("Primary"), nil)
+	gcc := NewGroundControlCenter("GND", CoverageRegion{
+		CenterLatitude: config.AirportLatitude, CenterLongitude: config.AirportLongitude, RadiusKM: 50,
+	})
+	near, far := newTestAircraft("A00001", "CCA101"), newTestAircraft("A00002", "CCA102")
+	far.setTrack(stationaryTrack(config.AirportLatitude+5, config.AirportLongitude))
+	gcc.TrackAircraft([]*Aircraft{near, far})
+	gcc.SetAvailable(false)
+
+	gcc.processMessage(newTestMessage(t, near, "CCA101-POS-1", MsgTypePosition, config.HighPriority, near.GetPosition()), comms)
+	gcc.processMessage(newTestMessage(t, far, "CCA102-POS-1", MsgTypePosition, config.HighPriority, far.GetPosition()), comms)
+
+	stats := gcc.GetRawStats()
+	if stats.OutageBuffered+stats.OutageDropped != 1 {
+		t.Errorf("离线期间截留了 %d 个报文，期望只截留覆盖范围内的 1 个", stats.OutageBuffered+stats.OutageDropped)
+	}
+	if stats.OutOfCoverageIgnored != 1 {
+		t.Errorf("覆盖区外忽略数 = %d，期望 1", stats.OutOfCoverageIgnored)
+	}
+}
+
+// ResetStats 清零离线期间的缓存与丢弃计数。
+func TestResetStatsClearsOutageCounters(t *testing.T) {
+	comms := newTestComms(newTestChannel("Primary"), nil)
+	gcc := newTestStation("GND")
+	a := newTestAircraft("A00001", "CCA101")
+	gcc.TrackAircraft([]*Aircraft{a})
+	gcc.SetAvailable(false)
+	for i := range 3 {
+		gcc.processMessage(newTestMessage(t, a, fmt.Sprintf("CCA101-POS-%d", i), MsgTypePosition, config.HighPriority, a.GetPosition()), comms)
+	}
+	if stats := gcc.GetRawStats(); stats.OutageBuffered+stats.OutageDropped != 3 {
+		t.Fatalf("离线期间截留了 %d 个报文，期望 3", stats.OutageBuffered+stats.OutageDropped)
+	}
+
+	gcc.ResetStats()
+	if stats := gcc.GetRawStats(); stats.OutageBuffered != 0 || stats.OutageDropped != 0 {
+		t.Errorf("重置后离线缓存数 = %d，丢弃数 = %d，期望都为 0", stats.OutageBuffered, stats.OutageDropped)
+	}
+}
+
+// 两个地面站共用一个信道且都覆盖同一架飞机：每个地面站对飞机的每个报文最多回复一次 ACK/NACK，
+// 且不会确认另一个地面站发出的 ACK、NACK、气象回复或 D-ATIS 广播。
+func TestTwoStationsNeverAckGroundFrames(t *testing.T) {
+	comms := newTestComms(newTestChannel("Primary"), nil)
+	a := newTestAircraft("A00001", "CCA101")
+	stations := []*GroundControlCenter{newTestStation("GND-A"), newTestStation("GND-B")}
+	for _, gcc := range stations {
+		gcc.TrackAircraft([]*Aircraft{a})
+	}
+	startTestEntities(comms, []*Aircraft{a}, stations)
+
+	msgs := []ACARSMessageInterface{
+		newTestMessage(t, a, "CCA101-POS-1", MsgTypePosition, config.HighPriority, a.GetPosition()),
+		newTestMessage(t, a, "CCA101-WXRQ-1", MsgTypeWeatherRequest, config.MediumPriority, WeatherData{RequestType: "METAR", Location: weatherRequestLocation}),
+		newTestMessage(t, a, "CCA101-FLT-1", MsgTypeAircraftFault, config.CriticalPriority, AircraftFaultData{FaultCode: "bad code", Severity: "MAJOR", System: "ENGINE_1"}),
+	}
+	a.weatherRequests.Store("CCA101-WXRQ-1", struct{}{})
+	var sent atomic.Int32
+	for _, msg := range msgs {
+		go a.SendMessageNotify(msg, comms, func(DeadLetterReason) { sent.Add(1) })
+	}
+	stations[0].BroadcastDATIS(DATISData{AirportICAO: "ZBAA", Edition: "ALPHA", Content: "TEST"}, comms)
+
+	waitFor(t, 15*time.Second, "所有报文都有了最终结果，地面站发出的帧都已上信道", func() bool {
+		if sent.Load() != int32(len(msgs)) {
+			return false
+		}
+		for _, gcc := range stations {
+			stats := gcc.GetRawStats()
+			if stats.WeatherReplies != 1 || stats.SuccessfulTx != stats.AckFramesSent+stats.WeatherReplies+stats.DATISBroadcasts {
+				return false
+			}
+		}
+		return true
+	})
+	// 给地面站处理对方帧留出时间：若错误地确认了地面帧，ACK 数会在此期间增加
+	time.Sleep(Scaled(config.ProcessingDelay) + 200*time.Millisecond)
+
+	for _, gcc := range stations {
+		stats := gcc.GetRawStats()
+		if stats.TotalReceived != uint64(len(msgs)) {
+			t.Errorf("%s 处理了 %d 个报文，期望只处理飞机发出的 %d 个", gcc.ID, stats.TotalReceived, len(msgs))
+		}
+		if stats.OutOfCoverageIgnored != 0 {
+			t.Errorf("%s 将 %d 个地面帧当作覆盖区外的飞机报文，地面帧应在此之前被过滤", gcc.ID, stats.OutOfCoverageIgnored)
+		}
+		if stats.NacksSent != 1 {
+			t.Errorf("%s 回复了 %d 个 NACK，期望 1", gcc.ID, stats.NacksSent)
+		}
+		if want := stats.TotalReceived + stats.DuplicatesReceived; stats.AckFramesSent != want {
+			t.Errorf("%s 发出了 %d 个 ACK/NACK 帧，期望每个收到的飞机报文一个，共 %d 个", gcc.ID, stats.AckFramesSent, want)
+		}
+	}
+	if a.LatestDATIS() == nil {
+		t.Error("飞机没有收到 D-ATIS 广播")
+	}
+}
@@ -3,7 +3,11 @@ package simulation
 
 import (
 	"Air-Simulator/config"
-	"log"
+	"hash/fnv"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,15 +17,24 @@ import (
 type Channel struct {
 	ID            string
 	mutex         sync.Mutex
-	isBusy        bool
+	occupancy     int       // 正在进行的传输数，达到容量时信道忙碌
+	idleSince     time.Time // 信道最近一次从满载变为有空闲容量的时刻
 	messageQueue  chan ACARSMessageInterface
-	listeners     []chan<- ACARSMessageInterface
+	listeners     []*Listener
 	listenerMutex sync.Mutex
 
 	// --- 统计字段 ---
 	totalMessagesTransmitted atomic.Uint64
-	totalBusyTime            time.Duration
-	lastBusyTimestamp        time.Time
+	criticalTransmitted      atomic.Uint64 // 成功传输的 CRITICAL 优先级报文数
+	totalBusyTime            time.Duration // 信道满载 (全部子信道被占用) 的累计时间
+	lastBusyTimestamp        time.Time     // 最近一次变为满载的时间
+	occupancyTime            time.Duration // 所有传输占用时间之和，除以时长即为平均占用路数
+	transmitAttempts         atomic.Uint64 // AttemptTransmit 被调用的次数 (包括因信道满载被拒绝的)
+	offeredAirTime           atomic.Int64  // 所有传输尝试的标称传输时间之和 (纳秒)
+	successfulAirTime        time.Duration // 未损坏的传输占用信道的时间之和
+	statsSince               time.Time     // 统计开始的时间
+	recentEvents             eventWindow   // 最近结束的传输，用于滑动窗口统计
+	idle                     idleTracker   // 信道完全空闲的时间与空闲间隔分布，受 mutex 保护
 
 	// --- 可动态更新的 p-value 策略 ---
 	pValues      map[config.Priority]float64
@@ -30,24 +43,97 @@ type Channel struct {
 	// --- 时隙 (TimeSlot) ---
 	currentTimeSlot time.Duration // 新增: 时隙现在是信道的属性
 	timeSlotMutex   sync.RWMutex
+
+	// --- 信道条件 (由 ChannelConditions 按天气变化动态调整) ---
+	frameErrorRate  float64 // 误帧率：报文在分发时被损坏而丢失的概率
+	dataRateFactor  float64 // 有效数据速率系数，1.0 为标称速率，越小传输耗时越长
+	conditionFactor float64 // 当前天气影响程度，0 为晴好，1 为最恶劣
+	conditionsMutex sync.RWMutex
+	framesCorrupted atomic.Uint64 // 因信道条件而损坏丢失的报文数
+
+	// --- 链路预算 (仅对记录了接收功率的帧) ---
+	lastReceivedPowerDBm atomic.Uint64 // 最近分发的帧的接收功率 (math.Float64bits)，0 表示尚无记录
+	weakSignalLosses     atomic.Uint64 // 因接收功率不足而丢失的帧数
+
+	// --- 容量 (需在信道开始使用前设置) ---
+	Capacity int // 可同时进行的传输数，小于 1 时按 1 处理
+
+	// --- 碰撞检测 (需在信道开始使用前设置) ---
+	CollisionDetection bool          // 是否启用碰撞检测 (CSMA/CD)，false 时为仅碰撞避免
+	CollisionWindow    time.Duration // 传输开始后的易受碰撞时间窗口，为 0 时不会发生重叠碰撞 (仅 busy-at-start 模型)
+	CollisionModel     string        // 碰撞模型 (见 CollisionBusyAtStart 等)，为空时按 busy-at-start 处理
+	JamTime            time.Duration // 检测到碰撞后发送阻塞信号的时长
+	slotEpoch          time.Time     // 时隙 ALOHA 模型下第 0 个时隙的起点 (信道创建时刻)
+
+	// SlotJitter 是发送方在本信道上按时隙等待时附加的随机抖动，以时隙长度为单位 (见 config.SlotJitter)，0 时严格按时隙边界同步。
+	// 需在信道开始使用前设置。
+	SlotJitter float64
+
+	// Scheduler 非 nil 时，并发到达的传输尝试按其决定的顺序逐个进入信道 (用于可复现的测试)；为 nil 时按真实并发执行。
+	// 需在信道开始使用前设置。
+	Scheduler Scheduler
+
+	active            []*activeTransmission // 正在进行的传输 (按开始时间排序)，受 mutex 保护
+	overlapCollisions atomic.Uint64         // 发生重叠碰撞的次数
+	recoveredTime     time.Duration         // 因碰撞检测提前释放而节省的信道时间，受 mutex 保护
+	preemptions       atomic.Uint64         // 被抢占中止的传输数
+	preemptedTime     time.Duration         // 被抢占的传输已占用 (浪费) 的信道时间，受 mutex 保护
+
+	// --- 优先级反转检测 ---
+	contenders         map[config.Priority]int // 正在本信道上等待发送的发送方数 (按报文优先级)，受 mutex 保护
+	priorityInversions atomic.Uint64           // 低优先级帧获得信道而更高优先级帧仍在等待的次数
+
+	// --- 传输时间抽样 ---
+	rng      *rand.Rand // 每个信道独立的随机数生成器
+	rngMutex sync.Mutex
 }
 
 // NewChannel 是 Channel 的构造函数。
 func NewChannel(id string, initialPMap map[config.Priority]float64, initialTimeSlot time.Duration) *Channel {
 	return &Channel{
 		ID:              id,
+		Capacity:        1,
 		messageQueue:    make(chan ACARSMessageInterface, 100),
-		listeners:       make([]chan<- ACARSMessageInterface, 0),
+		listeners:       make([]*Listener, 0),
 		pValues:         initialPMap,
 		currentTimeSlot: initialTimeSlot,
+		dataRateFactor:  1.0,
+		statsSince:      time.Now(),
+		idleSince:       time.Now(),
+		slotEpoch:       time.Now(),
+		idle:            idleTracker{emptySince: time.Now()},
+		rng:             rand.New(rand.NewPCG(uint64(config.FlightPlanSeed), channelSeed(id))),
 	}
 }
 
+// channelSeed 由信道ID派生随机数种子，使不同信道的随机序列互不相同。
+func channelSeed(id string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	return h.Sum64()
+}
+
+// SetConditions 更新信道当前的误帧率、有效数据速率系数以及天气影响程度。
+func (c *Channel) SetConditions(frameErrorRate, dataRateFactor, conditionFactor float64) {
+	c.conditionsMutex.Lock()
+	defer c.conditionsMutex.Unlock()
+	c.frameErrorRate = frameErrorRate
+	c.dataRateFactor = dataRateFactor
+	c.conditionFactor = conditionFactor
+}
+
+// GetConditions 返回信道当前的误帧率、有效数据速率系数以及天气影响程度。
+func (c *Channel) GetConditions() (frameErrorRate, dataRateFactor, conditionFactor float64) {
+	c.conditionsMutex.RLock()
+	defer c.conditionsMutex.RUnlock()
+	return c.frameErrorRate, c.dataRateFactor, c.conditionFactor
+}
+
 func (c *Channel) UpdatePValues(newPMap map[config.Priority]float64) {
 	c.pValuesMutex.Lock()
 	defer c.pValuesMutex.Unlock()
 	c.pValues = newPMap
-	log.Printf("🔄 信道 [%s] 的 p-map 已更新。", c.ID)
+	slog.Info("🔄 信道 p-map 已更新", "channel", c.ID)
 }
 
 // GetPForMessage 为给定的优先级获取当前的 p-value。
@@ -65,7 +151,7 @@ func (c *Channel) UpdateCurrentTimeSlot(newTimeSlot time.Duration) {
 	c.timeSlotMutex.Lock()
 	defer c.timeSlotMutex.Unlock()
 	c.currentTimeSlot = newTimeSlot
-	log.Printf("🔄 信道 [%s] 的时隙已更新为 %v。", c.ID, newTimeSlot)
+	slog.Info("🔄 信道时隙已更新", "channel", c.ID, "timeSlot", newTimeSlot)
 }
 
 // GetCurrentTimeSlot 安全地获取当前的时隙值。
@@ -75,60 +161,393 @@ func (c *Channel) GetCurrentTimeSlot() time.Duration {
 	return c.currentTimeSlot
 }
 
-// IsBusy 检查信道当前是否被占用。
+// IsBusy 检查信道当前是否满载，即全部子信道都被占用。
 func (c *Channel) IsBusy() bool {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	return c.isBusy
+	return c.occupancy >= c.capacity()
 }
 
-// AttemptTransmit 尝试在信道上传输一个报文。
-func (c *Channel) AttemptTransmit(msg ACARSMessageInterface, senderID string, transmissionTime time.Duration) bool {
+// IdleSince 返回信道最近一次从满载变为有空闲容量的时刻，信道当前满载时返回零值。
+func (c *Channel) IdleSince() time.Time {
 	c.mutex.Lock()
-	if c.isBusy {
+	defer c.mutex.Unlock()
+	if c.occupancy >= c.capacity() {
+		return time.Time{}
+	}
+	return c.idleSince
+}
+
+// idleFor 判断信道是否已持续至少 d 有空闲容量 (帧间间隔)。d 为 0 时等价于 !IsBusy()。
+func (c *Channel) idleFor(d time.Duration) bool {
+	since := c.IdleSince()
+	return !since.IsZero() && time.Since(since) >= Scaled(d)
+}
+
+// Occupancy 返回信道上正在进行的传输数。
+func (c *Channel) Occupancy() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.occupancy
+}
+
+// capacity 返回信道的有效容量。
+func (c *Channel) capacity() int {
+	return max(c.Capacity, 1)
+}
+
+// nominalTransmissionTime 返回报文的标称传输时间：config.MessageTypeTransmissionTimes 中该类报文的设置；
+// 未设置时，若配置了 config.LinkBitRate 则按报文在空中占用的字节数 (SizeBytes) 计算，否则为 config.TransmissionTime。
+func nominalTransmissionTime(msg ACARSMessageInterface) time.Duration {
+	if d, ok := config.MessageTypeTransmissionTimes[string(msg.GetBaseMessage().Type)]; ok && d > 0 {
+		return d
+	}
+	if bitRate := config.LinkBitRate; bitRate > 0 {
+		return time.Duration(SizeBytes(msg)) * 8 * time.Second / time.Duration(bitRate)
+	}
+	return config.TransmissionTime
+}
+
+// transmissionTimeFor 为一个报文抽取其在信道上的传输时间，以模拟真实报文长度的差异。
+// 分布由 config.TransmissionTimeDistribution 决定，以报文类型的标称传输时间为基准，
+// 随机数由每个信道独立的随机数生成器产生，相同配置下可复现。
+func (c *Channel) transmissionTimeFor(msg ACARSMessageInterface) time.Duration {
+	base, jitter := nominalTransmissionTime(msg), config.TransmissionTimeJitter
+	if jitter <= 0 {
+		return base
+	}
+
+	c.rngMutex.Lock()
+	defer c.rngMutex.Unlock()
+	var d time.Duration
+	switch config.TransmissionTimeDistribution {
+	case "uniform":
+		d = base + time.Duration(c.rng.Int64N(int64(2*jitter)+1)) - jitter
+	case "normal":
+		d = base + time.Duration(c.rng.NormFloat64()*float64(jitter))
+	default:
+		d = base
+	}
+	return max(d, base/10) // 传输时间不能为负，也不应小到失去意义
+}
+
+// activeTransmission 描述信道上正在进行的一次传输。
+type activeTransmission struct {
+	start     time.Time     // 开始传输的时间
+	end       time.Time     // 预计释放信道的时间，发生重叠碰撞时可能被延长或提前
+	corrupted bool          // 是否因重叠碰撞而损坏
+	wake      chan struct{} // end 被修改时通知传输 goroutine
+
+	priority config.Priority // 报文的优先级，用于判断能否被抢占
+	released bool            // 是否已开始释放 (传输结束或被抢占)，此后不能再被抢占
+}
+
+// AttemptTransmit 尝试在信道上传输一个报文，信道上最多可同时进行 Capacity 次传输。
+// 信道满载时返回 false。若此时处于最近开始的传输的易受碰撞时间窗口内 (由 CollisionModel 决定，
+// 默认为传输开始后的 CollisionWindow，此时发送方尚未能侦听到载波)，两次传输在信道上发生重叠碰撞：
+//   - 未启用碰撞检测时，两次传输都会完整地占用信道，正在进行的报文损坏；
+//   - 启用碰撞检测 (CollisionDetection) 时，双方在发送 JamTime 长度的阻塞信号后立即中止，提前释放信道。
+//
+// 报文占用信道的时长由 transmissionTimeFor 决定。
+func (c *Channel) AttemptTransmit(msg ACARSMessageInterface, senderID string) bool {
+	return c.attemptTransmit(msg, senderID, nil)
+}
+
+// attemptTransmit 与 AttemptTransmit 相同；halfDuplex 非 nil 时，它在报文占用信道期间收不到任何报文 (半双工电台)。
+func (c *Channel) attemptTransmit(msg ACARSMessageInterface, senderID string, halfDuplex *Listener) bool {
+	return c.transmit(msg, senderID, halfDuplex, false)
+}
+
+// PreemptTransmit 在信道满载时中止一个优先级低于 msg 的进行中传输，并在同一临界区内让 msg 占用腾出的容量，
+// 其他发送方无法在两者之间抢走这份容量。有多个候选时选择优先级最低、其中开始最晚 (浪费的信道时间最少) 的传输。
+// 被中止的报文不会送达 (发送方将因等不到 ACK 而重传)，其已占用的信道时间计为浪费。
+// 信道未满载或没有可抢占的传输时返回 false，此时 msg 没有被发送，也不计为一次传输尝试。
+func (c *Channel) PreemptTransmit(msg ACARSMessageInterface, senderID string) bool {
+	return c.preemptTransmit(msg, senderID, nil)
+}
+
+// preemptTransmit 与 PreemptTransmit 相同，halfDuplex 的含义与 attemptTransmit 相同。
+func (c *Channel) preemptTransmit(msg ACARSMessageInterface, senderID string, halfDuplex *Listener) bool {
+	return c.transmit(msg, senderID, halfDuplex, true)
+}
+
+// airTimeFor 返回报文此次占用信道的实际时长。
+func (c *Channel) airTimeFor(msg ACARSMessageInterface) time.Duration {
+	transmissionTime := Scaled(c.transmissionTimeFor(msg))
+
+	// 信道条件恶化时有效数据速率下降，同一报文需要占用信道更长时间
+	_, dataRateFactor, _ := c.GetConditions()
+	if dataRateFactor > 0 && dataRateFactor < 1 {
+		transmissionTime = time.Duration(float64(transmissionTime) / dataRateFactor)
+	}
+	return transmissionTime
+}
+
+// transmit 实现 attemptTransmit 与 preemptTransmit：preempt 为 true 时先抢占一个低优先级传输，
+// 抢占失败则不发送。
+func (c *Channel) transmit(msg ACARSMessageInterface, senderID string, halfDuplex *Listener, preempt bool) bool {
+	c.awaitSlotBoundary()
+	if c.Scheduler != nil {
+		done := c.Scheduler.Admit(TransmitAttempt{SenderID: senderID, Message: msg})
+		defer done()
+	}
+	transmissionTime := c.airTimeFor(msg)
+
+	c.mutex.Lock()
+	now := time.Now()
+	if preempt && !c.preemptLocked(msg.GetPriority(), senderID, now) {
+		c.mutex.Unlock()
+		return false
+	}
+	c.transmitAttempts.Add(1)
+	c.offeredAirTime.Add(int64(Scaled(nominalTransmissionTime(msg))))
+	if c.occupancy >= c.capacity() {
+		if tx := c.active[len(c.active)-1]; c.overlapsLocked(tx, now) {
+			c.handleOverlap(tx, now, transmissionTime, senderID)
+		}
 		c.mutex.Unlock()
 		return false
 	}
-	c.isBusy = true
-	c.lastBusyTimestamp = time.Now()
+	if c.occupancy == 0 {
+		c.idle.end(now)
+	}
+	c.occupancy++
+	if c.occupancy == c.capacity() {
+		c.lastBusyTimestamp = now
+	}
+	tx := &activeTransmission{
+		start:    now,
+		end:      now.Add(transmissionTime),
+		wake:     make(chan struct{}, 1),
+		priority: msg.GetPriority(),
+	}
+	c.active = append(c.active, tx)
+	c.checkPriorityInversionLocked(tx.priority, msg.GetBaseMessage().MessageID, senderID)
 	c.mutex.Unlock()
+	if halfDuplex != nil {
+		halfDuplex.transmitting.Add(1)
+	}
 
-	log.Printf("➡️  [%s] 成功获得信道，开始传输报文 (ID: %s)", senderID, msg.GetBaseMessage().MessageID)
+	slog.Debug("➡️  成功获得信道，开始传输报文", "sender", senderID, "channel", c.ID, "msgID", msg.GetBaseMessage().MessageID,
+		"bytes", SizeBytes(msg), "airTime", transmissionTime)
 
 	go func() {
-		time.Sleep(transmissionTime)
-		c.messageQueue <- msg
-		c.totalMessagesTransmitted.Add(1)
-		log.Printf("✅ [%s] 报文 (ID: %s) 已成功发送至信道。", senderID, msg.GetBaseMessage().MessageID)
+		c.awaitEnd(tx)
+		if halfDuplex != nil {
+			halfDuplex.transmitting.Add(-1)
+		}
+
+		c.mutex.Lock()
+		if tx.released {
+			// 已被更高优先级的报文抢占，preemptLocked 已完成释放
+			c.mutex.Unlock()
+			slog.Debug("✂️  报文传输被抢占，未能送达", "sender", senderID, "channel", c.ID, "msgID", msg.GetBaseMessage().MessageID)
+			logMessageEvent(MessageEventPreempted, senderID, msg.GetBaseMessage().MessageID, c.ID, "")
+			return
+		}
+		tx.released = true
+		corrupted := tx.corrupted
+		c.mutex.Unlock()
+		if corrupted {
+			slog.Debug("💥 报文与其他传输重叠，已损坏", "sender", senderID, "channel", c.ID, "msgID", msg.GetBaseMessage().MessageID)
+			logMessageEvent(MessageEventCollided, senderID, msg.GetBaseMessage().MessageID, c.ID, "")
+		} else {
+			logMessageEvent(MessageEventDelivered, senderID, msg.GetBaseMessage().MessageID, c.ID, "")
+			c.messageQueue <- msg
+			c.totalMessagesTransmitted.Add(1)
+			if msg.GetPriority() == config.CriticalPriority {
+				c.criticalTransmitted.Add(1)
+			}
+			slog.Debug("✅ 报文已成功发送至信道", "sender", senderID, "channel", c.ID, "msgID", msg.GetBaseMessage().MessageID)
+		}
 
 		c.mutex.Lock()
-		c.isBusy = false
-		busyDuration := time.Since(c.lastBusyTimestamp)
-		c.totalBusyTime += busyDuration
+		outcome := txDelivered
+		if corrupted {
+			outcome = txCorrupted
+		}
+		c.releaseLocked(tx, time.Now(), outcome)
 		c.mutex.Unlock()
-		log.Printf("⬅️  [%s] 传输完成，释放信道。", senderID)
+		slog.Debug("⬅️  传输完成，释放信道", "sender", senderID, "channel", c.ID)
 	}()
 
 	return true
 }
 
-// RegisterListener 和 StartDispatching 保持不变
-func (c *Channel) RegisterListener(listener chan<- ACARSMessageInterface) {
+// awaitEnd 等待传输 tx 结束；期间 end 可能因重叠碰撞或抢占而改变。
+func (c *Channel) awaitEnd(tx *activeTransmission) {
+	for {
+		c.mutex.Lock()
+		remaining := time.Until(tx.end)
+		c.mutex.Unlock()
+		if remaining <= 0 {
+			return
+		}
+		select {
+		case <-time.After(remaining):
+		case <-tx.wake:
+		}
+	}
+}
+
+// txOutcome 是一次传输释放时的结果，决定它计入哪些统计。
+type txOutcome int
+
+const (
+	txDelivered txOutcome = iota // 未损坏地发送完毕
+	txCorrupted                  // 因重叠碰撞或被抢占而未能送达
+	txRestored                   // 从快照恢复的传输：只占用信道时间，既不算送达也不算损坏
+)
+
+// releaseLocked 结束一次传输并释放其占用的容量，调用方必须持有 c.mutex。
+func (c *Channel) releaseLocked(tx *activeTransmission, now time.Time, outcome txOutcome) {
+	if c.occupancy == c.capacity() {
+		c.totalBusyTime += now.Sub(c.lastBusyTimestamp)
+		c.idleSince = now
+	}
+	c.occupancy--
+	if c.occupancy == 0 {
+		c.idle.begin(now)
+	}
+	c.occupancyTime += now.Sub(tx.start)
+	switch outcome {
+	case txDelivered:
+		c.successfulAirTime += now.Sub(tx.start)
+		c.recentEvents.record(eventDelivered, Unscaled(now.Sub(tx.start)))
+	case txCorrupted:
+		c.recentEvents.record(eventCorrupted, Unscaled(now.Sub(tx.start)))
+	}
+	for i, other := range c.active {
+		if other == tx {
+			c.active = append(c.active[:i], c.active[i+1:]...)
+			break
+		}
+	}
+}
+
+// preemptLocked 在信道满载时中止一个优先级低于 priority 的进行中传输，返回是否腾出了容量。
+// 调用方必须持有 c.mutex，并在释放锁之前占用腾出的容量。
+func (c *Channel) preemptLocked(priority config.Priority, senderID string, now time.Time) bool {
+	if c.occupancy < c.capacity() {
+		return false
+	}
+
+	var victim *activeTransmission
+	for _, tx := range c.active {
+		if tx.released || priorityValue(tx.priority) >= priorityValue(priority) {
+			continue
+		}
+		if victim == nil || priorityValue(tx.priority) < priorityValue(victim.priority) ||
+			(tx.priority == victim.priority && tx.start.After(victim.start)) {
+			victim = tx
+		}
+	}
+	if victim == nil {
+		return false
+	}
+
+	victim.released = true
+	victim.end = now
+	c.preemptions.Add(1)
+	c.preemptedTime += now.Sub(victim.start)
+	c.releaseLocked(victim, now, txCorrupted)
+	select {
+	case victim.wake <- struct{}{}:
+	default:
+	}
+	slog.Debug("✂️  抢占低优先级传输，为高优先级报文腾出信道", "sender", senderID, "channel", c.ID,
+		"priority", priority, "preempted", victim.priority, "wasted", now.Sub(victim.start))
+	return true
+}
+
+// handleOverlap 处理一次重叠碰撞，调用方必须持有 c.mutex。
+func (c *Channel) handleOverlap(tx *activeTransmission, now time.Time, transmissionTime time.Duration, senderID string) {
+	c.overlapCollisions.Add(1)
+	tx.corrupted = true
+
+	// 不进行碰撞检测时，信道要一直被占用到两次传输都结束
+	fullEnd := tx.end
+	if newcomerEnd := now.Add(transmissionTime); newcomerEnd.After(fullEnd) {
+		fullEnd = newcomerEnd
+	}
+
+	if !c.CollisionDetection {
+		tx.end = fullEnd
+		slog.Debug("💥 与正在进行的传输发生重叠碰撞", "sender", senderID, "channel", c.ID)
+	} else {
+		jamEnd := now.Add(Scaled(c.JamTime))
+		if jamEnd.Before(fullEnd) {
+			c.recoveredTime += fullEnd.Sub(jamEnd)
+			tx.end = jamEnd
+		} else {
+			tx.end = fullEnd
+		}
+		slog.Debug("📣 检测到碰撞，双方发送阻塞信号后中止传输", "sender", senderID, "channel", c.ID)
+	}
+
+	select {
+	case tx.wake <- struct{}{}:
+	default:
+	}
+}
+
+// deliverDirect 不经信道接入、不占用信道，直接将报文交给调度器投递给所有监听者，
+// 用于模拟不与数据争用的专用链路。报文仍受信道条件 (误帧、弱信号) 影响，但不计入信道的传输统计。
+func (c *Channel) deliverDirect(msg ACARSMessageInterface) {
+	c.messageQueue <- msg
+}
+
+// RegisterListener 将一个监听者注册到信道，信道上成功传输的每个报文都会投递给所有监听者
+// (启用 config.AddressedDelivery 时只投递给报文的收件方)。同一监听者重复注册时只保留一次。
+func (c *Channel) RegisterListener(listener *Listener) {
 	c.listenerMutex.Lock()
 	defer c.listenerMutex.Unlock()
+	if slices.Contains(c.listeners, listener) {
+		return // 重复注册 (例如恢复快照时) 不会重复投递
+	}
 	c.listeners = append(c.listeners, listener)
+	if listener.tuner != nil {
+		listener.tuner.register(c)
+	}
 }
 
 func (c *Channel) StartDispatching() {
-	log.Println("📡 信道调度服务已启动...")
+	slog.Info("📡 信道调度服务已启动", "channel", c.ID)
 	go func() {
 		for msg := range c.messageQueue {
+			// 按当前误帧率模拟报文在传播过程中出现比特错误：损坏的报文照常送达，由接收方通过校验和发现并丢弃
+			if frameErrorRate, _, _ := c.GetConditions(); frameErrorRate > 0 && rand.Float64() < frameErrorRate {
+				c.framesCorrupted.Add(1)
+				slog.Debug("🌩️  报文因信道条件恶劣而出现比特错误", "channel", c.ID, "msgID", msg.GetBaseMessage().MessageID)
+				msg = corruptFrame(msg)
+			}
+			// 按接收功率模拟远距离、低功率发送方的弱信号丢帧
+			if rxPower := msg.GetBaseMessage().rxPowerDBm; rxPower != 0 {
+				c.lastReceivedPowerDBm.Store(math.Float64bits(rxPower))
+				if loss := weakSignalLossProbability(rxPower); loss > 0 && rand.Float64() < loss {
+					c.weakSignalLosses.Add(1)
+					slog.Debug("📶 报文因接收信号过弱而丢失", "channel", c.ID, "msgID", msg.GetBaseMessage().MessageID, "rxPowerDBm", rxPower)
+					continue
+				}
+			}
 			c.listenerMutex.Lock()
 			for _, listener := range c.listeners {
-				select {
-				case listener <- msg:
-				default:
-					log.Printf("警告: 监听者队列已满，消息 %s 被丢弃。", msg.GetBaseMessage().MessageID)
+				if config.AddressedDelivery && !listener.addressedTo(msg) {
+					continue
+				}
+				if listener.ground && msg.GetBaseMessage().fromDeadZone {
+					continue // 盲区内发出的帧到不了地面站
+				}
+				if !listener.receiving() {
+					listener.miss(msg)
+					continue
+				}
+				if !listener.tunedTo(c, msg) {
+					continue
+				}
+				if !listener.deliver(msg) {
+					slog.Warn("监听者队列已满，消息被丢弃", "channel", c.ID, "listener", listener.OwnerID,
+						"msgID", msg.GetBaseMessage().MessageID, "policy", config.ListenerOverflowPolicy)
 				}
 			}
 			c.listenerMutex.Unlock()
@@ -136,30 +555,158 @@ func (c *Channel) StartDispatching() {
 	}()
 }
 
-// GetTotalBusyTime 安全地返回总占用时间
+// LastReceivedPowerDBm 返回信道上最近分发的帧的接收功率 (dBm)，尚无记录时返回 0。
+func (c *Channel) LastReceivedPowerDBm() float64 {
+	return math.Float64frombits(c.lastReceivedPowerDBm.Load())
+}
+
+// GetTotalBusyTime 安全地返回信道满载的总时间 (容量为 1 时即总占用时间)
 func (c *Channel) GetTotalBusyTime() time.Duration {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	return c.totalBusyTime
+	return Unscaled(c.totalBusyTime)
+}
+
+// GetOccupancyTime 安全地返回所有传输占用时间之和
+func (c *Channel) GetOccupancyTime() time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return Unscaled(c.occupancyTime)
+}
+
+// GetPreemptedTime 安全地返回被抢占的传输浪费的信道时间
+func (c *Channel) GetPreemptedTime() time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return Unscaled(c.preemptedTime)
+}
+
+// GetRecoveredTime 安全地返回因碰撞检测而节省的信道时间
+func (c *Channel) GetRecoveredTime() time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return Unscaled(c.recoveredTime)
 }
 
 func (c *Channel) ResetStats() {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	c.totalBusyTime = 0
+	c.occupancyTime = 0
+	c.successfulAirTime = 0
+	c.recoveredTime = 0
+	c.preemptedTime = 0
+	c.preemptions.Store(0)
+	c.weakSignalLosses.Store(0)
+	c.priorityInversions.Store(0)
+	c.statsSince = time.Now()
+	c.idle.reset(c.statsSince, c.occupancy == 0)
 
 	c.totalMessagesTransmitted.Store(0)
+	c.criticalTransmitted.Store(0)
+	c.transmitAttempts.Store(0)
+	c.offeredAirTime.Store(0)
+	c.recentEvents.reset()
+	c.overlapCollisions.Store(0)
+	c.framesCorrupted.Store(0)
 }
 
 // ChannelRawStats Excel自动统计需要以下两个函数
 type ChannelRawStats struct {
 	TotalMessagesTransmitted uint64
-	TotalBusyTime            time.Duration
+	CriticalTransmitted      uint64        // 其中 CRITICAL 优先级的报文数
+	TotalBusyTime            time.Duration // 满载时间
+	Capacity                 int
+	OccupancyTime            time.Duration // 除以统计时长即为平均占用路数
+
+	FramesCorrupted uint64
+	FrameErrorRate  float64
+	DataRateFactor  float64
+	ConditionFactor float64
+
+	OverlapCollisions uint64
+	RecoveredTime     time.Duration
+
+	Preemptions   uint64
+	PreemptedTime time.Duration
+
+	WeakSignalLosses     uint64
+	LastReceivedPowerDBm float64
+
+	PriorityInversions uint64
+
+	TimeSlot time.Duration // 统计时信道的当前时隙
+
+	IdleTime time.Duration // 信道上没有任何传输的总时间
+	IdleGaps []uint64      // 空闲间隔按 IdleGapBounds 分区间的计数
+
+	Efficiency ChannelEfficiencyStats
+}
+
+// ChannelEfficiencyStats 描述信道的提供负载与承载负载，用于绘制信道接入策略的吞吐量-负载曲线。
+// 两者均以信道容量为单位 (1.0 表示全部子信道持续被占用)。
+type ChannelEfficiencyStats struct {
+	Elapsed           time.Duration // 统计时长
+	TransmitAttempts  uint64        // 尝试传输次数
+	SuccessfulAirTime time.Duration // 未损坏的传输占用信道的时间之和
+
+	OfferedLoad float64 // 提供负载 G = 所有传输尝试的标称传输时间之和 / 统计时长
+	CarriedLoad float64 // 承载负载 S = 成功传输占用时间 / 统计时长
+}
+
+// efficiencyStats 根据累计计数器计算信道的提供负载与承载负载。
+func (c *Channel) efficiencyStats() ChannelEfficiencyStats {
+	c.mutex.Lock()
+	elapsed := time.Since(c.statsSince)
+	successfulAirTime := c.successfulAirTime
+	c.mutex.Unlock()
+
+	stats := ChannelEfficiencyStats{
+		Elapsed:           Unscaled(elapsed),
+		TransmitAttempts:  c.transmitAttempts.Load(),
+		SuccessfulAirTime: Unscaled(successfulAirTime),
+	}
+	if elapsed > 0 {
+		capacityTime := float64(elapsed) * float64(c.capacity())
+		stats.OfferedLoad = float64(c.offeredAirTime.Load()) / capacityTime
+		stats.CarriedLoad = float64(successfulAirTime) / capacityTime
+	}
+	return stats
 }
 
 func (c *Channel) GetRawStats() ChannelRawStats {
+	frameErrorRate, dataRateFactor, conditionFactor := c.GetConditions()
+	c.mutex.Lock()
+	idleTime, idleGaps := c.idle.snapshot(time.Now())
+	c.mutex.Unlock()
 	return ChannelRawStats{
 		TotalMessagesTransmitted: c.totalMessagesTransmitted.Load(),
+		CriticalTransmitted:      c.criticalTransmitted.Load(),
 		TotalBusyTime:            c.GetTotalBusyTime(),
+		Capacity:                 c.capacity(),
+		OccupancyTime:            c.GetOccupancyTime(),
+
+		FramesCorrupted: c.framesCorrupted.Load(),
+		FrameErrorRate:  frameErrorRate,
+		DataRateFactor:  dataRateFactor,
+		ConditionFactor: conditionFactor,
+
+		OverlapCollisions: c.overlapCollisions.Load(),
+		RecoveredTime:     c.GetRecoveredTime(),
+
+		Preemptions:   c.preemptions.Load(),
+		PreemptedTime: c.GetPreemptedTime(),
+
+		WeakSignalLosses:     c.weakSignalLosses.Load(),
+		LastReceivedPowerDBm: c.LastReceivedPowerDBm(),
+
+		PriorityInversions: c.priorityInversions.Load(),
+
+		TimeSlot: c.GetCurrentTimeSlot(),
+
+		IdleTime: idleTime,
+		IdleGaps: idleGaps,
+
+		Efficiency: c.efficiencyStats(),
 	}
 }
@@ -0,0 +1,67 @@
+package simulation
+
+import (
+	"log/slog"
+	"math"
+	"time"
+)
+
+// ChannelConditions 按时间表模拟天气对信道质量的影响。
+// 天气影响程度是以 StormCenter 为中心、StormWidth 为标准差的高斯曲线：
+// 在风暴峰值时误帧率升至 PeakFrameErrorRate、数据速率降至 PeakDataRateFactor，之后逐渐恢复。
+type ChannelConditions struct {
+	BaseFrameErrorRate float64       // 晴好天气下的误帧率
+	PeakFrameErrorRate float64       // 风暴峰值时的误帧率
+	PeakDataRateFactor float64       // 风暴峰值时的有效数据速率系数
+	StormCenter        time.Duration // 风暴峰值相对模拟开始的时间
+	StormWidth         time.Duration // 风暴持续范围 (高斯曲线的标准差)
+	UpdateInterval     time.Duration // 信道条件的更新间隔
+
+	startTime time.Time
+}
+
+// ConditionFactorAt 返回模拟开始 elapsed 时长后的天气影响程度，范围 [0, 1]。
+func (cc *ChannelConditions) ConditionFactorAt(elapsed time.Duration) float64 {
+	if cc.StormWidth <= 0 {
+		return 0
+	}
+	z := float64(elapsed-cc.StormCenter) / float64(cc.StormWidth)
+	return math.Exp(-z * z / 2)
+}
+
+// apply 根据天气影响程度计算并设置每个信道的误帧率与数据速率。
+func (cc *ChannelConditions) apply(channels []*Channel, factor float64) {
+	frameErrorRate := cc.BaseFrameErrorRate + (cc.PeakFrameErrorRate-cc.BaseFrameErrorRate)*factor
+	dataRateFactor := 1 - (1-cc.PeakDataRateFactor)*factor
+	for _, ch := range channels {
+		if ch != nil {
+			ch.SetConditions(frameErrorRate, dataRateFactor, factor)
+		}
+	}
+}
+
+// Run 按 UpdateInterval 周期性地更新所有信道的条件，直到 done 被关闭。它应该在一个单独的goroutine中运行。
+func (cc *ChannelConditions) Run(channels []*Channel, done <-chan struct{}) {
+	cc.startTime = time.Now()
+	cc.apply(channels, cc.ConditionFactorAt(0))
+	slog.Info("🌦️  信道条件驱动已启动", "stormCenter", cc.StormCenter)
+
+	ticker := time.NewTicker(Scaled(cc.UpdateInterval))
+	defer ticker.Stop()
+
+	lastLogged := -1.0
+	for {
+		select {
+		case <-ticker.C:
+			factor := cc.ConditionFactorAt(simSince(cc.startTime))
+			cc.apply(channels, factor)
+			// 影响程度变化超过 10% 时记录一次，避免刷屏
+			if math.Abs(factor-lastLogged) >= 0.1 {
+				slog.Info("🌦️  信道条件更新", "factor", factor)
+				lastLogged = factor
+			}
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// withTransmissionTimes 在测试期间为各报文类型设置标称传输时间。
+func withTransmissionTimes(t *testing.T, times map[MessageType]time.Duration) {
+	t.Helper()
+	saved := config.MessageTypeTransmissionTimes
+	config.MessageTypeTransmissionTimes = make(map[string]time.Duration, len(times))
+	for msgType, d := range times {
+		config.MessageTypeTransmissionTimes[string(msgType)] = d
+	}
+	t.Cleanup(func() { config.MessageTypeTransmissionTimes = saved })
+}
+
+// 传输时长各不相同时，满载时间与占用时间应等于各次传输时长之和。
+func TestBusyTimeSumsVariableDurations(t *testing.T) {
+	withTransmissionTimes(t, map[MessageType]time.Duration{
+		MsgTypePosition: 30 * time.Millisecond,
+		MsgTypeFuel:     60 * time.Millisecond,
+		MsgTypeWeather:  120 * time.Millisecond,
+	})
+	ch := newTestChannel("Primary")
+	ch.StartDispatching()
+	a := newTestAircraft("A00001", "CCA101")
+
+	var want time.Duration
+	for i, msgType := range []MessageType{MsgTypePosition, MsgTypeFuel, MsgTypeWeather} {
+		msg := newTestMessage(t, a, "CCA101-"+string(msgType), msgType, config.HighPriority, map[string]int{"seq": i})
+		if !ch.AttemptTransmit(msg, a.CurrentFlightID) {
+			t.Fatalf("空闲信道拒绝了报文 %s", msgType)
+		}
+		want += config.MessageTypeTransmissionTimes[string(msgType)]
+		waitFor(t, time.Second, "传输结束", func() bool { return ch.Occupancy() == 0 })
+	}
+
+	stats := ch.GetRawStats()
+	const slack = 30 * time.Millisecond // 每次传输结束时定时器可能略有延迟
+	for name, got := range map[string]time.Duration{"满载时间": stats.TotalBusyTime, "占用时间": stats.OccupancyTime} {
+		if got < want || got > want+slack {
+			t.Errorf("%s = %v，期望 %v (允许 %v 误差)", name, got, want, slack)
+		}
+	}
+	if stats.TotalMessagesTransmitted != 3 {
+		t.Errorf("传输数 = %d，期望 3", stats.TotalMessagesTransmitted)
+	}
+}
+
+// 发动机报告与 OOOI 报告按各自的传输时间占用信道：提供负载对应的传输时间与占用时间都等于按类型加权的传输时间之和。
+func TestLoadReflectsWeightedMessageMix(t *testing.T) {
+	times := map[MessageType]time.Duration{
+		MsgTypeEngineReport: 120 * time.Millisecond,
+		MsgTypeOOOI:         40 * time.Millisecond,
+	}
+	withTransmissionTimes(t, times)
+	ch := newTestChannel("Primary")
+	ch.StartDispatching()
+	a := newTestAircraft("A00001", "CCA101")
+
+	mix := map[MessageType]int{MsgTypeEngineReport: 2, MsgTypeOOOI: 3}
+	var want time.Duration
+	for msgType, count := range mix {
+		want += time.Duration(count) * times[msgType]
+		for i := range count {
+			msg := newTestMessage(t, a, fmt.Sprintf("CCA101-%s-%d", msgType, i), msgType, config.HighPriority, map[string]int{"seq": i})
+			if !ch.AttemptTransmit(msg, a.CurrentFlightID) {
+				t.Fatalf("空闲信道拒绝了报文 %s", msgType)
+			}
+			waitFor(t, time.Second, "传输结束", func() bool { return ch.Occupancy() == 0 })
+		}
+	}
+
+	stats := ch.GetRawStats()
+	offered := time.Duration(stats.Efficiency.OfferedLoad * float64(stats.Efficiency.Elapsed))
+	if diff := offered - want; diff < -time.Millisecond || diff > time.Millisecond {
+		t.Errorf("提供负载 × 统计时长 = %v，期望加权和 %v", offered, want)
+	}
+	const slack = 30 * time.Millisecond // 每次传输结束时定时器可能略有延迟
+	if stats.OccupancyTime < want || stats.OccupancyTime > want+slack {
+		t.Errorf("占用时间 = %v，期望加权和 %v (允许 %v 误差)", stats.OccupancyTime, want, slack)
+	}
+}
+
+// 抢占在同一临界区内让 CRITICAL 报文占用腾出的容量，其他发送方无法抢走；被抢占的报文不会送达。
+func TestPreemptTransmitReservesFreedCapacity(t *testing.T) {
+	withTransmissionTimes(t, map[MessageType]time.Duration{
+		MsgTypeEngineReport:  time.Second,
+		MsgTypeAircraftFault: 50 * time.Millisecond,
+		MsgTypePosition:      50 * time.Millisecond,
+	})
+	ch := newTestChannel("Primary")
+	ch.StartDispatching()
+	inbox := make(chan ACARSMessageInterface, 4)
+	capture := NewListener("CAPTURE", inbox)
+	capture.ground = true
+	ch.RegisterListener(capture)
+	a := newTestAircraft("A00001", "CCA101")
+
+	low := newTestMessage(t, a, "CCA101-ENG-1", MsgTypeEngineReport, config.LowPriority, map[string]int{"n1": 85})
+	critical := newTestMessage(t, a, "CCA101-FAULT-1", MsgTypeAircraftFault, config.CriticalPriority, map[string]string{"code": "ENG1"})
+	other := newTestMessage(t, a, "CCA101-POS-1", MsgTypePosition, config.HighPriority, a.GetPosition())
+
+	if ch.PreemptTransmit(critical, a.CurrentFlightID) {
+		t.Fatal("空闲信道上不应发生抢占")
+	}
+	if !ch.AttemptTransmit(low, a.CurrentFlightID) {
+		t.Fatal("空闲信道拒绝了低优先级报文")
+	}
+	if !ch.PreemptTransmit(critical, a.CurrentFlightID) {
+		t.Fatal("CRITICAL 报文未能抢占低优先级传输")
+	}
+	if ch.AttemptTransmit(other, "CCA102") {
+		t.Error("抢占腾出的容量被其他发送方占用")
+	}
+
+	select {
+	case msg := <-inbox:
+		if id := msg.GetBaseMessage().MessageID; id != critical.GetBaseMessage().MessageID {
+			t.Errorf("送达的报文为 %s，期望抢占者 %s", id, critical.GetBaseMessage().MessageID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("抢占者的报文未送达")
+	}
+	waitFor(t, time.Second, "信道空闲", func() bool { return ch.Occupancy() == 0 })
+	select {
+	case msg := <-inbox:
+		t.Errorf("被抢占的报文不应送达，收到 %s", msg.GetBaseMessage().MessageID)
+	default:
+	}
+	if got := ch.GetRawStats().Preemptions; got != 1 {
+		t.Errorf("抢占次数 = %d，期望 1", got)
+	}
+}
+
+func TestCanPreempt(t *testing.T) {
+	a := newTestAircraft("A00001", "CCA101")
+	fault := newTestMessage(t, a, "CCA101-FAULT-1", MsgTypeAircraftFault, config.CriticalPriority, map[string]string{"code": "ENG1"})
+	ack := newTestMessage(t, a, "ACK-CCA101-POS-1", MsgTypeAck, config.CriticalPriority, AcknowledgementData{OriginalMessageID: "CCA101-POS-1"})
+	pos := newTestMessage(t, a, "CCA101-POS-1", MsgTypePosition, config.HighPriority, a.GetPosition())
+	for _, tc := range []struct {
+		msg  ACARSMessageInterface
+		want bool
+	}{{fault, true}, {ack, false}, {pos, false}} {
+		if got := canPreempt(tc.msg); got != tc.want {
+			t.Errorf("canPreempt(%s) = %v，期望 %v", tc.msg.GetBaseMessage().Type, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package simulation
+
+import (
+	"encoding/json"
+	"hash/crc32"
+	"math/rand/v2"
+)
+
+// payloadChecksum 计算报文头部 (不含校验和本身) 与数据的 CRC-32 校验和。
+func payloadChecksum(base ACARSBaseMessage, rawData json.RawMessage) uint32 {
+	base.Checksum = 0
+	header, err := json.Marshal(base)
+	if err != nil {
+		return 0
+	}
+	return crc32.Update(crc32.ChecksumIEEE(header), crc32.IEEETable, rawData)
+}
+
+// verifyChecksum 检查收到的报文是否与其校验和一致，不一致说明报文在传输中被损坏。
+func verifyChecksum(msg ACARSMessageInterface) bool {
+	rawData, _ := msg.GetData().(json.RawMessage)
+	base := msg.GetBaseMessage()
+	return base.Checksum == payloadChecksum(base, rawData)
+}
+
+// corruptFrame 返回一个数据部分被翻转了一个随机比特的报文副本，模拟传输中的比特错误。
+// 头部保持不变，使接收方仍能识别发送方，只能通过校验和发现损坏。
+func corruptFrame(msg ACARSMessageInterface) ACARSMessageInterface {
+	rawData, ok := msg.GetData().(json.RawMessage)
+	if !ok || len(rawData) == 0 {
+		return msg
+	}
+	corrupted := append(json.RawMessage(nil), rawData...)
+	corrupted[rand.IntN(len(corrupted))] ^= 1 << rand.IntN(8)
+
+	switch m := msg.(type) {
+	case CriticalPriorityMessage:
+		m.Data = corrupted
+		return m
+	case HighMediumPriorityMessage:
+		m.Data = corrupted
+		return m
+	case MediumLowPriorityMessage:
+		m.Data = corrupted
+		return m
+	case LowAuxiliaryPriorityMessage:
+		m.Data = corrupted
+		return m
+	default:
+		return msg
+	}
+}
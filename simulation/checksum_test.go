@@ -0,0 +1,43 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestChecksumDetectsCorruption(t *testing.T) {
+	a := newTestAircraft("A00001", "CCA101")
+	for _, priority := range []config.Priority{config.CriticalPriority, config.HighPriority, config.MediumPriority, config.LowPriority} {
+		msg := newTestMessage(t, a, "CCA101-POS-"+string(priority), MsgTypePosition, priority, a.GetPosition())
+		if !verifyChecksum(msg) {
+			t.Fatalf("%s: 新建报文的校验和不一致", priority)
+		}
+		// 发送时更新时间戳会重新计算校验和；接收功率不属于报文内容
+		if !verifyChecksum(withTimestamp(msg, time.Now().Add(time.Minute))) {
+			t.Errorf("%s: 更新时间戳后校验和不一致", priority)
+		}
+		if !verifyChecksum(withReceivedPower(msg, -90)) {
+			t.Errorf("%s: 记录接收功率后校验和不一致", priority)
+		}
+		// 未重新计算校验和的头部改动应能被发现
+		if verifyChecksum(withBase(msg, func(base *ACARSBaseMessage) { base.FlightID = "CCA999" })) {
+			t.Errorf("%s: 头部改动未被校验和发现", priority)
+		}
+
+		original := append(json.RawMessage(nil), msg.GetData().(json.RawMessage)...)
+		corrupted := corruptFrame(msg)
+		if verifyChecksum(corrupted) {
+			t.Errorf("%s: 比特错误未被校验和发现", priority)
+		}
+		if !reflect.DeepEqual(corrupted.GetBaseMessage(), msg.GetBaseMessage()) {
+			t.Errorf("%s: corruptFrame 不应修改报文头部", priority)
+		}
+		if !bytes.Equal(msg.GetData().(json.RawMessage), original) {
+			t.Errorf("%s: corruptFrame 修改了原报文的数据", priority)
+		}
+	}
+}
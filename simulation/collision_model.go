@@ -0,0 +1,67 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"fmt"
+	"time"
+)
+
+// 可选的碰撞模型，决定满载信道上的一次传输尝试何时与正在进行的传输 (长度为 T) 发生重叠碰撞。
+// 落在易受碰撞时间窗口之外的尝试视为侦听到信道忙而放弃，不影响正在进行的传输。
+const (
+	// CollisionBusyAtStart: 只在正在进行的传输开始后的 CollisionWindow (载波侦听延迟) 内发生重叠，
+	// 之后发送方能侦听到载波。CollisionWindow 为 0 时信道忙即失败，不会重叠。
+	CollisionBusyAtStart = "busy-at-start"
+
+	// CollisionUnslottedALOHA: 非时隙 ALOHA，传输在整个占用期间都会与新的尝试重叠。
+	// 一帧开始前 T 内与开始后 T 内开始的传输都会与它碰撞，易受碰撞时间窗口为 2T。
+	CollisionUnslottedALOHA = "unslotted-aloha"
+
+	// CollisionSlottedALOHA: 时隙 ALOHA，时隙长度等于标准帧长 T (config.TransmissionTime)，传输总在时隙起点开始
+	// (尝试先等到下一个时隙起点)，只与同一时隙内开始的尝试重叠，易受碰撞时间窗口为 1T。
+	// 相邻时隙开始的尝试不与之重叠；长于 T 的帧跨越多个时隙，其后续时隙内的尝试视为侦听到信道忙。
+	CollisionSlottedALOHA = "slotted-aloha"
+)
+
+// CheckCollisionModel 检查 model 是否为已知的碰撞模型。
+func CheckCollisionModel(model string) error {
+	switch model {
+	case CollisionBusyAtStart, CollisionUnslottedALOHA, CollisionSlottedALOHA:
+		return nil
+	default:
+		return fmt.Errorf("未知的碰撞模型 %q", model)
+	}
+}
+
+// overlapsLocked 判断在 now 开始的传输尝试是否与正在进行的传输 tx 重叠，调用方必须持有 c.mutex。
+func (c *Channel) overlapsLocked(tx *activeTransmission, now time.Time) bool {
+	switch c.CollisionModel {
+	case CollisionUnslottedALOHA:
+		return now.Before(tx.end)
+	case CollisionSlottedALOHA:
+		return c.alohaSlot(now) == c.alohaSlot(tx.start)
+	default:
+		return now.Sub(tx.start) < Scaled(c.CollisionWindow)
+	}
+}
+
+// alohaSlotLength 返回时隙 ALOHA 模型的 (缩放后的) 时隙长度，即标准帧长。
+func alohaSlotLength() time.Duration {
+	return max(Scaled(config.TransmissionTime), 1)
+}
+
+// awaitSlotBoundary 在时隙 ALOHA 模型下阻塞到下一个时隙起点，使传输总在时隙边界开始；其他模型立即返回。
+func (c *Channel) awaitSlotBoundary() {
+	if c.CollisionModel != CollisionSlottedALOHA {
+		return
+	}
+	slot := alohaSlotLength()
+	next := c.slotEpoch.Add((time.Since(c.slotEpoch)/slot + 1) * slot)
+	time.Sleep(time.Until(next))
+}
+
+// alohaSlot 返回 t 所在的时隙序号。传输开始时刻已对齐到时隙起点 (醒来时略有延迟)，因此按最近的时隙起点取整。
+func (c *Channel) alohaSlot(t time.Time) int64 {
+	slot := alohaSlotLength()
+	return int64((t.Sub(c.slotEpoch) + slot/2) / slot)
+}
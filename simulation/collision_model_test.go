@@ -0,0 +1,141 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCheckCollisionModel(t *testing.T) {
+	for _, model := range []string{CollisionBusyAtStart, CollisionUnslottedALOHA, CollisionSlottedALOHA} {
+		if err := CheckCollisionModel(model); err != nil {
+			t.Errorf("CheckCollisionModel(%q) = %v", model, err)
+		}
+	}
+	if err := CheckCollisionModel("csma-cd"); err == nil {
+		t.Error("未知的碰撞模型应返回错误")
+	}
+}
+
+// busy-at-start 与非时隙 ALOHA 的易受碰撞时间窗口边界：窗口内的最后一刻重叠，窗口结束时不再重叠。
+func TestOverlapWindowEdges(t *testing.T) {
+	const frame = 100 * time.Millisecond
+	start := time.Now()
+	// 重叠碰撞已将 end 延长到 1.5T
+	tx := &activeTransmission{start: start, end: start.Add(frame * 3 / 2)}
+
+	for _, tc := range []struct {
+		model  string
+		window time.Duration // 传输开始后的易受碰撞时间
+	}{
+		{CollisionBusyAtStart, 20 * time.Millisecond},
+		{CollisionUnslottedALOHA, frame * 3 / 2}, // 正在进行的传输全程
+	} {
+		ch := newTestChannel("Primary")
+		ch.CollisionModel = tc.model
+		ch.CollisionWindow = 20 * time.Millisecond
+
+		edge := start.Add(Scaled(tc.window))
+		if !ch.overlapsLocked(tx, start) {
+			t.Errorf("%s: 与传输同时开始的尝试应重叠", tc.model)
+		}
+		if !ch.overlapsLocked(tx, edge.Add(-time.Nanosecond)) {
+			t.Errorf("%s: 窗口结束前的尝试应重叠", tc.model)
+		}
+		if ch.overlapsLocked(tx, edge) {
+			t.Errorf("%s: 窗口结束时的尝试不应重叠", tc.model)
+		}
+	}
+}
+
+// 时隙 ALOHA 下只有同一时隙内开始的尝试重叠：相邻时隙起点开始的尝试即使正在进行的帧尚未结束 (例如 end 已被延长) 也不重叠。
+// 传输开始时刻在时隙起点之后略有延迟 (goroutine 醒来的延迟) 不影响所属时隙。
+func TestSlottedALOHAOverlapsOnlySameSlot(t *testing.T) {
+	ch := newTestChannel("Primary")
+	ch.CollisionModel = CollisionSlottedALOHA
+	// 信道的接入时隙与帧长无关，不影响时隙 ALOHA 的时隙
+	ch.UpdateCurrentTimeSlot(7 * time.Millisecond)
+	slot := alohaSlotLength()
+	boundary := func(k int) time.Time { return ch.slotEpoch.Add(time.Duration(k) * slot) }
+	delay := slot / 10
+
+	tx := &activeTransmission{start: boundary(3).Add(delay), end: boundary(5)}
+	for _, tc := range []struct {
+		name    string
+		now     time.Time
+		overlap bool
+	}{
+		{"同一时隙起点", boundary(3), true},
+		{"同一时隙起点之后略有延迟", boundary(3).Add(2 * delay), true},
+		{"前一时隙起点", boundary(2).Add(delay), false},
+		{"下一时隙起点", boundary(4), false},
+		{"下一时隙起点之后略有延迟", boundary(4).Add(delay), false},
+	} {
+		if got := ch.overlapsLocked(tx, tc.now); got != tc.overlap {
+			t.Errorf("%s: 重叠 = %v，期望 %v", tc.name, got, tc.overlap)
+		}
+	}
+}
+
+// 时隙 ALOHA 下传输在时隙起点开始：同一时隙内先后到达的两个尝试都推迟到下一个时隙起点，相互碰撞，双方都不送达。
+func TestSlottedALOHACollidesWithinSlot(t *testing.T) {
+	ch := newTestChannel("Primary")
+	ch.CollisionModel = CollisionSlottedALOHA
+	ch.StartDispatching()
+	a, b := newTestAircraft("A00001", "CCA101"), newTestAircraft("A00002", "CCA102")
+	msgA := newTestMessage(t, a, "CCA101-POS-1", MsgTypePosition, config.HighPriority, a.GetPosition())
+	msgB := newTestMessage(t, b, "CCA102-POS-1", MsgTypePosition, config.HighPriority, b.GetPosition())
+
+	// 从一个时隙起点开始，确保两个尝试在同一时隙内到达
+	ch.awaitSlotBoundary()
+	var wg sync.WaitGroup
+	accepted := make([]bool, 2)
+	for i, attempt := range []func() bool{
+		func() bool { return ch.AttemptTransmit(msgA, a.CurrentFlightID) },
+		func() bool { return ch.AttemptTransmit(msgB, b.CurrentFlightID) },
+	} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			accepted[i] = attempt()
+		}()
+	}
+	wg.Wait()
+	waitFor(t, time.Second, "信道空闲", func() bool { return ch.Occupancy() == 0 })
+
+	if accepted[0] == accepted[1] {
+		t.Errorf("两个尝试的结果为 %v，期望一个占用信道、一个与之碰撞", accepted)
+	}
+	stats := ch.GetRawStats()
+	if stats.OverlapCollisions != 1 || stats.TotalMessagesTransmitted != 0 {
+		t.Errorf("重叠碰撞 %d 次、送达 %d 个报文，期望 1 次碰撞、0 个送达", stats.OverlapCollisions, stats.TotalMessagesTransmitted)
+	}
+}
+
+// 时隙 ALOHA 下在帧传输期间到达的尝试推迟到下一个时隙起点，不与已在传输的帧碰撞，该帧正常送达。
+func TestSlottedALOHADefersToNextSlot(t *testing.T) {
+	ch := newTestChannel("Primary")
+	ch.CollisionModel = CollisionSlottedALOHA
+	ch.StartDispatching()
+	a, b := newTestAircraft("A00001", "CCA101"), newTestAircraft("A00002", "CCA102")
+
+	if !ch.AttemptTransmit(newTestMessage(t, a, "CCA101-POS-1", MsgTypePosition, config.HighPriority, a.GetPosition()), a.CurrentFlightID) {
+		t.Fatal("空闲信道拒绝了报文")
+	}
+	sentAt := time.Now()
+	time.Sleep(alohaSlotLength() / 2)
+	ch.AttemptTransmit(newTestMessage(t, b, "CCA102-POS-1", MsgTypePosition, config.HighPriority, b.GetPosition()), b.CurrentFlightID)
+	if waited := time.Since(sentAt); waited < alohaSlotLength()*9/10 {
+		t.Errorf("帧开始后 %v 就开始了下一次尝试，期望等到下一个时隙起点", waited)
+	}
+	waitFor(t, time.Second, "信道空闲", func() bool { return ch.Occupancy() == 0 })
+
+	stats := ch.GetRawStats()
+	if stats.OverlapCollisions != 0 {
+		t.Errorf("重叠碰撞 %d 次，相邻时隙的尝试不应碰撞", stats.OverlapCollisions)
+	}
+	if stats.TotalMessagesTransmitted == 0 {
+		t.Error("先开始的帧没有送达")
+	}
+}
@@ -2,22 +2,37 @@ package simulation
 
 import (
 	"Air-Simulator/config"
-	"log"
+	"log/slog"
 	"math/rand/v2"
 	"sync"
 )
 
 // CommunicationSystem 封装了主备双信道，为实体提供统一的通信接口。
+// 划分扇区后，每个扇区拥有自己的主备信道，发送方使用其所在扇区的信道；
+// PrimaryChannel / BackupChannel 则是尚未分配扇区的发送方使用的默认信道。
+// 上下行分频时，飞机只使用 DownlinkChannel，地面站只使用 UplinkChannel。
 type CommunicationSystem struct {
 	PrimaryChannel *Channel
-	BackupChannel  *Channel // 在单信道模式下，此字段为 nil
+	BackupChannel  *Channel     // 在单信道模式下，此字段为 nil
+	MediumAccess   MediumAccess // 所有发送方共用的信道接入策略
+
+	DownlinkChannel *Channel // 上下行分频时飞机发送使用的信道，未分频时为 nil
+	UplinkChannel   *Channel // 上下行分频时地面站发送使用的信道，未分频时为 nil
+
+	// GuardChannel 是只供 CRITICAL 报文使用的保护 (应急) 信道，发送方的常规信道忙时 CRITICAL 报文直接改用它，
+	// 不受切换概率影响；其他优先级的报文永远不会选择它。未启用时为 nil。
+	GuardChannel *Channel
+
+	sectors       []*Sector // 在开始调度前通过 AddSector 添加，之后只读
+	senderSectors sync.Map  // 发送方ID -> 其当前所在的 *Sector
 
 	switchoverProbabilities      map[config.Priority]float64
 	switchoverProbabilitiesMutex sync.RWMutex
 }
 
 // NewCommunicationSystem 是 CommunicationSystem 的构造函数。
-func NewCommunicationSystem(primary, backup *Channel, initialProbs map[config.Priority]float64) *CommunicationSystem {
+// access 为 nil 时使用默认的 p-坚持 CSMA。
+func NewCommunicationSystem(primary, backup *Channel, initialProbs map[config.Priority]float64, access MediumAccess) *CommunicationSystem {
 	// 最佳实践：创建一个副本，以避免外部对原始map的修改影响到系统内部状态
 	probs := make(map[config.Priority]float64)
 	if initialProbs != nil {
@@ -26,9 +41,14 @@ func NewCommunicationSystem(primary, backup *Channel, initialProbs map[config.Pr
 		}
 	}
 
+	if access == nil {
+		access = PPersistentCSMA{}
+	}
+
 	return &CommunicationSystem{
 		PrimaryChannel:          primary,
 		BackupChannel:           backup,
+		MediumAccess:            access,
 		switchoverProbabilities: probs,
 	}
 }
@@ -42,53 +62,209 @@ func (cs *CommunicationSystem) UpdateSwitchoverProbabilities(newProbs map[config
 	for k, v := range newProbs {
 		cs.switchoverProbabilities[k] = v
 	}
-	log.Printf("🔄 通信系统的备用信道切换概率已更新。")
+	slog.Info("🔄 通信系统的备用信道切换概率已更新")
 }
 
-func (cs *CommunicationSystem) StartDispatching() {
-	if cs.PrimaryChannel != nil {
-		cs.PrimaryChannel.StartDispatching()
+// AddSector 添加一个扇区，扇区的地面站此后使用该扇区的信道发送报文。
+// 必须在 StartDispatching 与注册任何监听者之前调用。
+func (cs *CommunicationSystem) AddSector(sector *Sector) {
+	cs.sectors = append(cs.sectors, sector)
+	cs.senderSectors.Store(sector.Station.ID, sector)
+}
+
+// SplitLinks 启用上下行分频：飞机此后只在 downlink 上发送，地面站只在 uplink 上发送。
+// 必须在 StartDispatching 与注册任何监听者之前调用。
+func (cs *CommunicationSystem) SplitLinks(downlink, uplink *Channel) {
+	cs.DownlinkChannel, cs.UplinkChannel = downlink, uplink
+}
+
+// LinksSplit 判断是否启用了上下行分频。
+func (cs *CommunicationSystem) LinksSplit() bool {
+	return cs.UplinkChannel != nil
+}
+
+// Sectors 返回所有扇区，未划分扇区时为空。
+func (cs *CommunicationSystem) Sectors() []*Sector {
+	return cs.sectors
+}
+
+// Channels 返回通信系统中所有已启用的信道 (默认信道与各扇区的信道，不重复)。
+func (cs *CommunicationSystem) Channels() []*Channel {
+	var channels []*Channel
+	seen := make(map[*Channel]bool)
+	add := func(ch *Channel) {
+		if ch != nil && !seen[ch] {
+			seen[ch] = true
+			channels = append(channels, ch)
+		}
 	}
-	if cs.BackupChannel != nil {
-		cs.BackupChannel.StartDispatching()
+	add(cs.PrimaryChannel)
+	add(cs.BackupChannel)
+	add(cs.DownlinkChannel)
+	add(cs.UplinkChannel)
+	add(cs.GuardChannel)
+	for _, sector := range cs.sectors {
+		add(sector.PrimaryChannel)
+		add(sector.BackupChannel)
+	}
+	return channels
+}
+
+func (cs *CommunicationSystem) StartDispatching() {
+	for _, ch := range cs.Channels() {
+		ch.StartDispatching()
 	}
 }
 
 // RegisterListener 将一个监听者注册到所有可用的信道。
-func (cs *CommunicationSystem) RegisterListener(listener chan<- ACARSMessageInterface) {
-	cs.PrimaryChannel.RegisterListener(listener)
-	if cs.BackupChannel != nil {
-		cs.BackupChannel.RegisterListener(listener)
+func (cs *CommunicationSystem) RegisterListener(listener *Listener) {
+	for _, ch := range cs.Channels() {
+		ch.RegisterListener(listener)
+	}
+}
+
+// RegisterReceiver 注册一个只接收单一方向报文的监听者：uplink 为 true 时接收上行报文 (飞机)，否则接收下行报文 (地面站)。
+// 未分频时与 RegisterListener 相同，监听者收到所有信道上的报文。
+func (cs *CommunicationSystem) RegisterReceiver(listener *Listener, uplink bool) {
+	if !cs.LinksSplit() {
+		cs.RegisterListener(listener)
+		return
+	}
+	if uplink {
+		cs.UplinkChannel.RegisterListener(listener)
+	} else {
+		cs.DownlinkChannel.RegisterListener(listener)
+	}
+	if cs.GuardChannel != nil {
+		cs.GuardChannel.RegisterListener(listener) // 两个方向的 CRITICAL 报文都可能使用保护信道
 	}
 }
 
-// SelectChannelForMessage 根据报文优先级和信道状态选择合适的信道。
-func (cs *CommunicationSystem) SelectChannelForMessage(msg ACARSMessageInterface, senderID string) *Channel {
-	// 规则 1: 如果没有备用信道，或者主信道空闲，总是使用主信道。
-	if cs.BackupChannel == nil || !cs.PrimaryChannel.IsBusy() {
-		return cs.PrimaryChannel
+// channelsFor 返回发送方当前应使用的主备信道：已分配扇区时为该扇区的信道，否则为默认信道。
+func (cs *CommunicationSystem) channelsFor(senderID string) (primary, backup *Channel) {
+	if sector, ok := cs.senderSectors.Load(senderID); ok {
+		return sector.(*Sector).PrimaryChannel, sector.(*Sector).BackupChannel
+	}
+	return cs.PrimaryChannel, cs.BackupChannel
+}
+
+// isPrimary 判断 ch 是否为默认信道或某个扇区的主信道；上下行分频时两个方向的信道都视为主信道。
+func (cs *CommunicationSystem) isPrimary(ch *Channel) bool {
+	if ch == cs.PrimaryChannel || (ch != nil && (ch == cs.DownlinkChannel || ch == cs.UplinkChannel)) {
+		return true
+	}
+	for _, sector := range cs.sectors {
+		if ch == sector.PrimaryChannel {
+			return true
+		}
+	}
+	return false
+}
+
+// isBackup 判断 ch 是否为默认信道或某个扇区的备用信道。
+func (cs *CommunicationSystem) isBackup(ch *Channel) bool {
+	return ch != nil && ch != cs.GuardChannel && !cs.isPrimary(ch)
+}
+
+// guardFor 在启用了保护信道、报文为 CRITICAL 且发送方的常规信道 regular 忙时返回保护信道，否则返回 nil。
+// 只看报文自身的优先级：经优先级老化升为 CRITICAL 的报文不能使用保护信道。
+func (cs *CommunicationSystem) guardFor(msg ACARSMessageInterface, regular *Channel, senderID string) *Channel {
+	if cs.GuardChannel == nil || msg.GetPriority() != config.CriticalPriority || !regular.IsBusy() {
+		return nil
+	}
+	slog.Debug("🆘 常规信道忙，CRITICAL 报文改用保护信道", "sender", senderID,
+		"msgID", msg.GetBaseMessage().MessageID, "channel", cs.GuardChannel.ID)
+	return cs.GuardChannel
+}
+
+// switchover 记录一次发送 (一次 acquireChannel) 中主备信道切换的状态，在各时隙之间保持。
+// 切换概率每次发送只抽签一次，各时隙沿用同一抽签结果，使配置的切换概率仍是“主信道忙时切换的概率”，
+// 而不是随等待时隙数累积放大；因等待预算耗尽而强制切换后，报文留在备用信道上直到发出。
+type switchover struct {
+	roll       float64 // 本次发送的切换抽签，小于切换概率即切换
+	busyStreak int     // 连续观察到主信道忙的次数
+	forced     bool    // 是否已强制切换到备用信道
+}
+
+// newSwitchover 为一次新的发送创建切换状态。
+func newSwitchover() *switchover {
+	return &switchover{roll: rand.Float64()}
+}
+
+// selectChannel 为发送方选择本时隙使用的信道：上下行分频时为发送方所在方向的信道，否则在主备信道中选择。
+// 第二个返回值为 true 表示本时隙发生了强制切换。
+func (cs *CommunicationSystem) selectChannel(msg ACARSMessageInterface, priority config.Priority, from sender, sw *switchover) (*Channel, bool) {
+	if cs.LinksSplit() {
+		link := cs.DownlinkChannel
+		if from.uplink {
+			link = cs.UplinkChannel
+		}
+		if guard := cs.guardFor(msg, link, from.id); guard != nil {
+			return guard, false
+		}
+		return link, false
+	}
+	return cs.chooseChannel(msg, priority, from.id, sw)
+}
+
+// SelectChannelForMessage 根据报文优先级和信道状态，在发送方当前所在扇区的主备信道中选择合适的信道。
+// priority 是报文当前的有效优先级 (启用优先级老化时可能高于报文自身的优先级)。
+// primaryBusyStreak 是发送方连续观察到主信道忙的次数；当其达到该优先级的等待预算时，
+// 无论切换概率如何都会强制切换到备用信道，此时第二个返回值为 true。
+func (cs *CommunicationSystem) SelectChannelForMessage(msg ACARSMessageInterface, priority config.Priority, senderID string, primaryBusyStreak int) (*Channel, bool) {
+	sw := newSwitchover()
+	sw.busyStreak = primaryBusyStreak
+	return cs.chooseChannel(msg, priority, senderID, sw)
+}
+
+// chooseChannel 实现 SelectChannelForMessage，切换抽签与强制切换状态取自 sw。
+func (cs *CommunicationSystem) chooseChannel(msg ACARSMessageInterface, priority config.Priority, senderID string, sw *switchover) (*Channel, bool) {
+	primary, backup := cs.channelsFor(senderID)
+	// 规则 1: 主信道忙时，CRITICAL 报文直接使用保护信道 (若已启用)，不受切换概率影响。
+	if guard := cs.guardFor(msg, primary, senderID); guard != nil {
+		return guard, false
+	}
+
+	// 规则 2: 没有备用信道时总是使用主信道；已强制切换时留在备用信道上，直到报文发出。
+	if backup == nil {
+		return primary, false
+	}
+	if sw.forced {
+		return backup, false
+	}
+
+	// 规则 3: 主信道空闲时使用主信道。
+	if !primary.IsBusy() {
+		return primary, false
+	}
+
+	// 规则 4: 等待预算已耗尽，强制切换到备用信道，避免在忙碌的主信道上无限等待。
+	if budget := config.ForcedSwitchoverBudget[priority]; budget > 0 && sw.busyStreak >= budget {
+		slog.Debug("⚠️  主信道连续忙，强制切换至备用信道", "sender", senderID, "busyStreak", sw.busyStreak,
+			"msgID", msg.GetBaseMessage().MessageID, "priority", priority, "channel", backup.ID)
+		sw.forced = true
+		return backup, true
 	}
 
-	// 规则 2: 主信道忙碌，从系统属性中安全地读取切换概率
+	// 规则 5: 主信道忙碌，从系统属性中安全地读取切换概率
 	cs.switchoverProbabilitiesMutex.RLock()
-	priority := msg.GetPriority()
 	// 从map中获取当前优先级的切换概率，如果不存在则默认为0
 	switchoverP := cs.switchoverProbabilities[priority]
 	cs.switchoverProbabilitiesMutex.RUnlock()
 
-	// 规则 3: 执行概率判断。如果随机数小于设定的概率，则切换。
-	if rand.Float64() < switchoverP {
+	// 规则 6: 用本次发送的抽签结果执行概率判断。如果抽签值小于设定的概率，则切换。
+	if sw.roll < switchoverP {
 		// 切换成功
-		log.Printf("⚠️  [%s] 主信道忙，报文 (ID: %s, Prio: %s) 概率切换 (p=%.2f) 至备用信道 [%s]。",
-			senderID, msg.GetBaseMessage().MessageID, priority, switchoverP, cs.BackupChannel.ID)
-		return cs.BackupChannel
+		slog.Debug("⚠️  主信道忙，概率切换至备用信道", "sender", senderID,
+			"msgID", msg.GetBaseMessage().MessageID, "priority", priority, "p", switchoverP, "channel", backup.ID)
+		return backup, false
 	}
 
-	// 规则 4: 概率判断未通过，或概率为0，继续等待主信道。
+	// 规则 7: 概率判断未通过，或概率为0，继续等待主信道。
 	if switchoverP > 0 {
-		log.Printf("⏳ [%s] 主信道忙，报文 (ID: %s, Prio: %s) 概率决定 (p=%.2f) 等待主信道 [%s]。",
-			senderID, msg.GetBaseMessage().MessageID, priority, switchoverP, cs.PrimaryChannel.ID)
+		slog.Debug("⏳ 主信道忙，概率决定等待主信道", "sender", senderID,
+			"msgID", msg.GetBaseMessage().MessageID, "priority", priority, "p", switchoverP, "channel", primary.ID)
 	}
 
-	return cs.PrimaryChannel
+	return primary, false
 }
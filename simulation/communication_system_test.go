@@ -0,0 +1,83 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"testing"
+	"time"
+)
+
+// 切换概率每次发送只抽签一次：抽签未通过时，无论主信道忙多少个时隙都留在主信道上 (直到等待预算耗尽)。
+func TestSwitchoverRollOncePerSend(t *testing.T) {
+	withTransmissionTimes(t, map[MessageType]time.Duration{MsgTypeEngineReport: time.Second})
+	primary, backup := newTestChannel("Primary"), newTestChannel("Backup")
+	comms := NewCommunicationSystem(primary, backup, map[config.Priority]float64{config.LowPriority: 0.3}, nil)
+	a := newTestAircraft("A00001", "CCA101")
+	msg := newTestMessage(t, a, "CCA101-ENG-1", MsgTypeEngineReport, config.LowPriority, map[string]int{"n1": 85})
+	if !primary.AttemptTransmit(msg, "BLOCKER") {
+		t.Fatal("空闲信道拒绝了占用信道的报文")
+	}
+
+	from := sender{id: a.CurrentFlightID}
+	for _, tc := range []struct {
+		roll float64
+		want *Channel
+	}{{roll: 0.5, want: primary}, {roll: 0.1, want: backup}} {
+		sw := &switchover{roll: tc.roll}
+		for slot := 0; slot < config.ForcedSwitchoverBudget[config.LowPriority]; slot++ {
+			if got, _ := comms.selectChannel(msg, config.LowPriority, from, sw); got != tc.want {
+				t.Fatalf("抽签 %.1f 在第 %d 个时隙选择了 %s，期望 %s", tc.roll, slot, got.ID, tc.want.ID)
+			}
+			sw.busyStreak++
+		}
+	}
+}
+
+// 主信道持续忙、等待预算耗尽后强制切换到备用信道；即使备用信道此时也忙，报文仍留在备用信道上等待，
+// 不会回到忙碌的主信道重新累积等待预算，最终从备用信道发出，只计一次强制切换。
+func TestForcedSwitchoverPersistsUntilSent(t *testing.T) {
+	withTransmissionTimes(t, map[MessageType]time.Duration{
+		MsgTypeEngineReport: 2 * time.Second,
+		MsgTypeWeather:      400 * time.Millisecond,
+		MsgTypePosition:     20 * time.Millisecond,
+	})
+	const slot = 50 * time.Millisecond
+	primary := NewChannel("Primary", config.PrimaryPMap, slot)
+	backup := NewChannel("Backup", config.BackupPMap, slot)
+	comms := NewCommunicationSystem(primary, backup, nil, OnePersistentCSMA{})
+	primary.StartDispatching()
+	backup.StartDispatching()
+	inbox := make(chan ACARSMessageInterface, 4)
+	capture := NewListener("CAPTURE", inbox)
+	capture.ground = true
+	backup.RegisterListener(capture)
+
+	blocker := newTestAircraft("B00001", "BLK001")
+	if !primary.AttemptTransmit(newTestMessage(t, blocker, "BLK001-ENG-1", MsgTypeEngineReport, config.LowPriority, map[string]int{"n1": 85}), blocker.CurrentFlightID) ||
+		!backup.AttemptTransmit(newTestMessage(t, blocker, "BLK001-WX-1", MsgTypeWeather, config.LowPriority, map[string]string{"wx": "CAVOK"}), blocker.CurrentFlightID) {
+		t.Fatal("空闲信道拒绝了占用信道的报文")
+	}
+
+	gcc := newTestStation("GND")
+	a := newTestAircraft("A00001", "CCA101")
+	msg := newTestMessage(t, a, "CCA101-POS-1", MsgTypePosition, config.HighPriority, a.GetPosition())
+	gcc.SendMessage(msg, comms)
+
+	if got := gcc.GetRawStats().ForcedSwitchovers; got != 1 {
+		t.Errorf("强制切换次数 = %d，期望 1", got)
+	}
+	if !primary.IsBusy() {
+		t.Fatal("主信道应仍被占用，测试时序失效")
+	}
+	waitFor(t, time.Second, "报文在备用信道上送达", func() bool {
+		for {
+			select {
+			case got := <-inbox:
+				if got.GetBaseMessage().MessageID == msg.GetBaseMessage().MessageID {
+					return true
+				}
+			default:
+				return false
+			}
+		}
+	})
+}
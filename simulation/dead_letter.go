@@ -0,0 +1,174 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DeadLetterReason 枚举了报文最终未能送达的原因。
+type DeadLetterReason string
+
+const (
+	DeadLetterMaxRetriesExceeded DeadLetterReason = "MAX_RETRIES_EXCEEDED" // 达到最大重传次数仍未收到 ACK
+	DeadLetterPendingAtEnd       DeadLetterReason = "PENDING_AT_END"       // 模拟结束时仍在发送或等待 ACK
+	DeadLetterRejected           DeadLetterReason = "REJECTED"             // 地面站以 NACK 拒绝了格式错误的报文
+	DeadLetterQueueOverflow      DeadLetterReason = "QUEUE_OVERFLOW"       // 发送中的报文已达上限，报文被拒绝或被更高优先级的报文挤掉
+)
+
+// DeadLetter 记录一条未能送达的报文及其原因。
+type DeadLetter struct {
+	MessageID  string           `json:"messageID"`
+	Type       MessageType      `json:"type"`
+	Priority   config.Priority  `json:"priority"`
+	Reason     DeadLetterReason `json:"reason"`
+	EnqueuedAt time.Time        `json:"enqueuedAt"` // 报文开始发送流程的时间
+
+	message ACARSMessageInterface // 报文本身，用于快照时保存仍在发送中的报文
+}
+
+// deadLetterBook 跟踪一个实体正在发送中的报文以及已确认无法送达的报文。
+type deadLetterBook struct {
+	mutex   sync.Mutex
+	letters []DeadLetter
+	pending map[string]DeadLetter // 正在发送中的报文，按报文ID索引
+
+	abandoned map[string]bool // 因发送队列溢出被放弃、但发送方尚未停止发送的报文 (仍在 pending 中)
+}
+
+// trackPendingLocked 登记一条开始发送流程的报文，调用方须持有 mutex。
+func (b *deadLetterBook) trackPendingLocked(letter DeadLetter) {
+	if b.pending == nil {
+		b.pending = make(map[string]DeadLetter)
+	}
+	b.pending[letter.MessageID] = letter
+}
+
+// newDeadLetter 为开始发送流程的报文创建记录，原因待定。
+func newDeadLetter(msg ACARSMessageInterface, enqueuedAt time.Time) DeadLetter {
+	baseMsg := msg.GetBaseMessage()
+	return DeadLetter{
+		MessageID:  baseMsg.MessageID,
+		Type:       baseMsg.Type,
+		Priority:   msg.GetPriority(),
+		EnqueuedAt: enqueuedAt,
+		message:    msg,
+	}
+}
+
+// admit 登记一条开始发送流程的报文；发送中的报文数已达 limit 时按 policy 处理溢出 (limit <= 0 表示不限制)。
+// 新报文被拒绝时直接记为死信并返回 ok=false；evicted 非空时为被放弃的发送中报文，其发送方应在察觉后停止发送。
+func (b *deadLetterBook) admit(msg ACARSMessageInterface, enqueuedAt time.Time, limit int, policy string) (evicted string, ok bool) {
+	letter := newDeadLetter(msg, enqueuedAt)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if limit <= 0 || len(b.pending)-len(b.abandoned) < limit {
+		b.trackPendingLocked(letter)
+		return "", true
+	}
+
+	if policy == OutboundDropLowest {
+		// 选出优先级最低的发送中报文，优先级相同时选开始最晚的 (已等待的时间最短)
+		var victim *DeadLetter
+		for _, pending := range b.pending {
+			if b.abandoned[pending.MessageID] {
+				continue
+			}
+			if victim == nil || priorityValue(pending.Priority) < priorityValue(victim.Priority) ||
+				(pending.Priority == victim.Priority && pending.EnqueuedAt.After(victim.EnqueuedAt)) {
+				victim = &pending
+			}
+		}
+		if victim != nil && priorityValue(victim.Priority) < priorityValue(letter.Priority) {
+			if b.abandoned == nil {
+				b.abandoned = make(map[string]bool)
+			}
+			b.abandoned[victim.MessageID] = true
+			b.trackPendingLocked(letter)
+			return victim.MessageID, true
+		}
+	}
+
+	letter.Reason = DeadLetterQueueOverflow
+	b.letters = append(b.letters, letter)
+	return "", false
+}
+
+// isAbandoned 判断报文是否已因发送队列溢出被放弃。
+func (b *deadLetterBook) isAbandoned(messageID string) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.abandoned[messageID]
+}
+
+// resolve 将报文移出发送中列表。reason 非空时，报文被记为死信。
+func (b *deadLetterBook) resolve(messageID string, reason DeadLetterReason) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	letter, ok := b.pending[messageID]
+	if !ok {
+		return
+	}
+	delete(b.pending, messageID)
+	delete(b.abandoned, messageID)
+	if reason != "" {
+		letter.Reason = reason
+		b.letters = append(b.letters, letter)
+	}
+}
+
+// list 返回所有死信，以及当前仍在发送中的报文 (原因记为 PENDING_AT_END)，按开始时间排序。
+func (b *deadLetterBook) list() []DeadLetter {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	letters := append([]DeadLetter(nil), b.letters...)
+	for _, letter := range b.pending {
+		letter.Reason = DeadLetterPendingAtEnd
+		letters = append(letters, letter)
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i].EnqueuedAt.Before(letters[j].EnqueuedAt) })
+	return letters
+}
+
+// pendingMessages 返回仍在发送中 (未被放弃) 的报文，按开始时间排序。
+func (b *deadLetterBook) pendingMessages() []DeadLetter {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	var pending []DeadLetter
+	for id, letter := range b.pending {
+		if !b.abandoned[id] {
+			pending = append(pending, letter)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if !pending[i].EnqueuedAt.Equal(pending[j].EnqueuedAt) {
+			return pending[i].EnqueuedAt.Before(pending[j].EnqueuedAt)
+		}
+		return pending[i].MessageID < pending[j].MessageID
+	})
+	return pending
+}
+
+// deadLetters 返回已确认无法送达的报文 (不含仍在发送中的报文)。
+func (b *deadLetterBook) deadLetters() []DeadLetter {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]DeadLetter(nil), b.letters...)
+}
+
+// restoreDeadLetters 用 letters 替换已确认无法送达的报文记录。
+func (b *deadLetterBook) restoreDeadLetters(letters []DeadLetter) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.letters = append([]DeadLetter(nil), letters...)
+}
+
+// reset 清空所有记录。
+func (b *deadLetterBook) reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.letters = nil
+	b.pending = nil
+	b.abandoned = nil
+}
@@ -0,0 +1,65 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"testing"
+	"time"
+)
+
+func TestAdmitRejectNewWhenFull(t *testing.T) {
+	a := newTestAircraft("A00001", "CCA101")
+	var book deadLetterBook
+	start := time.Now()
+	for i, id := range []string{"M1", "M2"} {
+		msg := newTestMessage(t, a, id, MsgTypePosition, config.LowPriority, a.GetPosition())
+		if _, ok := book.admit(msg, start.Add(time.Duration(i)*time.Second), 2, OutboundRejectNew); !ok {
+			t.Fatalf("未满时拒绝了报文 %s", id)
+		}
+	}
+	critical := newTestMessage(t, a, "M3", MsgTypeAircraftFault, config.CriticalPriority, map[string]string{"code": "ENG1"})
+	if evicted, ok := book.admit(critical, start.Add(2*time.Second), 2, OutboundRejectNew); ok || evicted != "" {
+		t.Fatalf("reject-new 策略下已满时 admit = (%q, %v)，期望拒绝新报文", evicted, ok)
+	}
+	letters := book.deadLetters()
+	if len(letters) != 1 || letters[0].MessageID != "M3" || letters[0].Reason != DeadLetterQueueOverflow {
+		t.Errorf("死信 = %+v，期望 M3 因队列溢出被拒绝", letters)
+	}
+}
+
+// drop-lowest 策略放弃优先级最低、其中开始最晚的发送中报文；新报文的优先级不高于所有发送中报文时被拒绝。
+func TestAdmitDropLowestEvictionOrder(t *testing.T) {
+	a := newTestAircraft("A00001", "CCA101")
+	var book deadLetterBook
+	start := time.Now()
+	admit := func(id string, priority config.Priority, at int) (string, bool) {
+		msg := newTestMessage(t, a, id, MsgTypePosition, priority, a.GetPosition())
+		return book.admit(msg, start.Add(time.Duration(at)*time.Second), 3, OutboundDropLowest)
+	}
+	admit("LOW-OLD", config.LowPriority, 0)
+	admit("LOW-NEW", config.LowPriority, 1)
+	admit("MEDIUM", config.MediumPriority, 2)
+
+	// 同为最低优先级时，开始最晚的先被放弃
+	for _, want := range []string{"LOW-NEW", "LOW-OLD", "MEDIUM"} {
+		evicted, ok := admit("HIGH-"+want, config.HighPriority, 10)
+		if !ok || evicted != want {
+			t.Fatalf("admit 高优先级报文 = (%q, %v)，期望放弃 %s", evicted, ok, want)
+		}
+		if !book.isAbandoned(want) {
+			t.Errorf("%s 应被标记为已放弃", want)
+		}
+	}
+
+	// 发送中的报文都已是 HIGH，同优先级的新报文不能挤掉它们
+	if evicted, ok := admit("HIGH-LATE", config.HighPriority, 11); ok || evicted != "" {
+		t.Errorf("admit 同优先级报文 = (%q, %v)，期望被拒绝", evicted, ok)
+	}
+	// 被放弃的报文不计入上限，但在发送方察觉并 resolve 之前仍在 pending 中
+	if got := len(book.pendingMessages()); got != 3 {
+		t.Errorf("未被放弃的发送中报文有 %d 个，期望 3", got)
+	}
+	book.resolve("LOW-NEW", DeadLetterQueueOverflow)
+	if book.isAbandoned("LOW-NEW") {
+		t.Error("resolve 后报文不应仍标记为已放弃")
+	}
+}
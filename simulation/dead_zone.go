@@ -0,0 +1,55 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// deadZoneSampleStep 是计算飞机在盲区内停留时间时沿航迹采样的间隔。
+const deadZoneSampleStep = 10 * time.Second
+
+// inDeadZone 判断给定位置是否位于 config.DeadZones 中的任一通信盲区内。
+func inDeadZone(pos PositionReportData) bool {
+	for _, zone := range config.DeadZones {
+		region := CoverageRegion{CenterLatitude: zone.Latitude, CenterLongitude: zone.Longitude, RadiusKM: zone.RadiusKM}
+		if region.Contains(pos.Latitude, pos.Longitude) {
+			return true
+		}
+	}
+	return false
+}
+
+// timeInDeadZone 沿航迹采样，估算飞机在 [from, to) 内位于通信盲区的时间。
+func timeInDeadZone(track flightTrack, from, to time.Time) time.Duration {
+	if len(config.DeadZones) == 0 || from.IsZero() {
+		return 0
+	}
+	var total time.Duration
+	sampleStep := max(Scaled(deadZoneSampleStep), 1)
+	for t := from; t.Before(to); t = t.Add(sampleStep) {
+		step := min(sampleStep, to.Sub(t))
+		if inDeadZone(track.positionAt(t.Add(step / 2))) {
+			total += step
+		}
+	}
+	return Unscaled(total)
+}
+
+// DeadZoneTime 返回飞机在统计期间位于通信盲区内的总时间。
+func (a *Aircraft) DeadZoneTime() time.Duration {
+	a.positionMutex.RLock()
+	defer a.positionMutex.RUnlock()
+	return a.deadZoneTime + timeInDeadZone(a.track, a.trackSince, time.Now())
+}
+
+// withDeadZone 在飞机位于通信盲区时标记即将发出的帧：帧照常占用信道，但到不了任何地面站。
+func (a *Aircraft) withDeadZone(frame ACARSMessageInterface) ACARSMessageInterface {
+	if len(config.DeadZones) == 0 || !inDeadZone(a.GetPosition()) {
+		return frame
+	}
+	atomic.AddUint64(&a.deadZoneLosses, 1)
+	slog.Debug("🕳️  飞机位于通信盲区，报文无法到达地面站", "flight", a.CurrentFlightID, "msgID", frame.GetBaseMessage().MessageID)
+	return withBase(frame, func(base *ACARSBaseMessage) { base.fromDeadZone = true })
+}
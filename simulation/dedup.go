@@ -0,0 +1,54 @@
+package simulation
+
+import (
+	"container/list"
+	"sync"
+)
+
+// recentMessageCache 是一个容量有限的 LRU 缓存，记录最近收到的报文ID，用于识别重传造成的重复报文。
+type recentMessageCache struct {
+	mutex    sync.Mutex
+	capacity int
+	order    *list.List               // 最近使用的报文ID位于队首
+	entries  map[string]*list.Element // 报文ID -> 链表节点
+}
+
+// newRecentMessageCache 创建一个最多记录 capacity 个报文ID的缓存。
+func newRecentMessageCache(capacity int) *recentMessageCache {
+	return &recentMessageCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// seen 记录一个报文ID，并返回它此前是否已经出现过。
+// 缓存满时淘汰最久未出现的报文ID。
+func (c *recentMessageCache) seen(messageID string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if elem, ok := c.entries[messageID]; ok {
+		c.order.MoveToFront(elem)
+		return true
+	}
+
+	c.entries[messageID] = c.order.PushFront(messageID)
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(string))
+	}
+	return false
+}
+
+// ids 返回缓存中的报文ID，最久未出现的在前。
+func (c *recentMessageCache) ids() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	ids := make([]string, 0, c.order.Len())
+	for elem := c.order.Back(); elem != nil; elem = elem.Prev() {
+		ids = append(ids, elem.Value.(string))
+	}
+	return ids
+}
@@ -0,0 +1,107 @@
+package simulation
+
+import (
+	"bufio"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// MessageEventKind 是报文事件日志中的事件种类。
+type MessageEventKind string
+
+const (
+	MessageEventEnqueued    MessageEventKind = "ENQUEUED"    // 发送方开始发送报文
+	MessageEventTransmitted MessageEventKind = "TRANSMITTED" // 报文获得信道，开始传输
+	MessageEventCollided    MessageEventKind = "COLLIDED"    // 传输尝试失败，或进行中的传输与其他传输重叠而损坏
+	MessageEventPreempted   MessageEventKind = "PREEMPTED"   // 进行中的传输被更高优先级的报文抢占
+	MessageEventDelivered   MessageEventKind = "DELIVERED"   // 传输完成，报文交给信道分发 (此后仍可能因误帧或弱信号丢失)
+	MessageEventAcked       MessageEventKind = "ACKED"       // 发送方收到 ACK，报文送达
+	MessageEventDropped     MessageEventKind = "DROPPED"     // 报文成为死信，Reason 为死信原因
+)
+
+// MessageEvent 是报文事件日志中的一条记录。
+type MessageEvent struct {
+	Offset    time.Duration    `json:"offset"` // 相对日志开始的时刻
+	Kind      MessageEventKind `json:"kind"`
+	Sender    string           `json:"sender"`
+	MessageID string           `json:"messageID"`
+	Channel   string           `json:"channel,omitempty"`
+	Reason    string           `json:"reason,omitempty"`
+}
+
+// MessageEventLog 将一次模拟中每个报文的事件 (入队、传输、碰撞、送达、确认、丢弃) 按发生顺序
+// 逐行写入 JSON Lines 格式的文件，供离线分析。文件边写边落盘，长时间的模拟也不会占用大量内存。
+type MessageEventLog struct {
+	mutex   sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder // Close 后为 nil，此后的事件被忽略
+	start   time.Time
+	count   int
+}
+
+// NewMessageEventLog 创建报文事件日志文件 (必要时创建其所在目录)，事件时刻从此时开始计算。
+func NewMessageEventLog(path string) (*MessageEventLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	writer := bufio.NewWriter(file)
+	return &MessageEventLog{file: file, writer: writer, encoder: json.NewEncoder(writer), start: time.Now()}, nil
+}
+
+// record 记录一个事件，写入失败时只记录日志，不影响模拟。
+func (l *MessageEventLog) record(kind MessageEventKind, senderID, messageID, channelID, reason string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.encoder == nil {
+		return
+	}
+	entry := MessageEvent{
+		Offset:    simSince(l.start),
+		Kind:      kind,
+		Sender:    senderID,
+		MessageID: messageID,
+		Channel:   channelID,
+		Reason:    reason,
+	}
+	if err := l.encoder.Encode(entry); err != nil {
+		slog.Error("无法记录报文事件", "sender", senderID, "msgID", messageID, "err", err)
+		return
+	}
+	l.count++
+}
+
+// Close 将缓冲的事件写入文件并关闭文件，返回记录的事件数。仍在进行的通信此后产生的事件不再记录。
+func (l *MessageEventLog) Close() (int, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.encoder = nil
+	if err := l.writer.Flush(); err != nil {
+		l.file.Close()
+		return l.count, err
+	}
+	return l.count, l.file.Close()
+}
+
+// messageEventLog 非 nil 时记录每个报文的事件
+var messageEventLog *MessageEventLog
+
+// SetMessageEventLog 设置记录报文事件的日志，为 nil 时不记录。必须在模拟开始前调用。
+func SetMessageEventLog(l *MessageEventLog) {
+	messageEventLog = l
+}
+
+// logMessageEvent 在启用了报文事件日志时记录一个事件。
+func logMessageEvent(kind MessageEventKind, senderID, messageID, channelID, reason string) {
+	if messageEventLog != nil {
+		messageEventLog.record(kind, senderID, messageID, channelID, reason)
+	}
+}
@@ -0,0 +1,79 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"log/slog"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// 故障风暴的状态，由 newFaultStorm 安排的定时器设置
+var (
+	faultStormStart    atomic.Int64  // 故障风暴开始的时刻 (UnixNano)，0 表示尚未开始
+	faultStormInjected atomic.Uint64 // 故障风暴中注入的故障报告数
+)
+
+// newFaultStorm 安排“故障风暴”场景：模拟开始 config.FaultStormAt 后返回的通道被关闭，
+// 此时处于巡航阶段的飞机中约 FaultStormFraction 比例同时各发送 FaultStormFaultsPerAircraft 份 CRITICAL 故障报告，
+// 用于检验优先级机制能否在突发高峰下保护关键报文。未启用时返回 nil (永不触发)。
+func newFaultStorm() <-chan struct{} {
+	if config.FaultStormAt <= 0 || config.FaultStormFraction <= 0 || config.FaultStormFaultsPerAircraft <= 0 {
+		return nil
+	}
+	faultStormStart.Store(0)
+	faultStormInjected.Store(0)
+	storm := make(chan struct{})
+	time.AfterFunc(Scaled(config.FaultStormAt), func() {
+		faultStormStart.Store(time.Now().UnixNano())
+		slog.Warn("🌪️  故障风暴开始", "fraction", config.FaultStormFraction, "faultsPerAircraft", config.FaultStormFaultsPerAircraft)
+		close(storm)
+	})
+	return storm
+}
+
+// joinFaultStorm 在故障风暴开始时由每架巡航中的飞机调用：飞机以 FaultStormFraction 的概率参与，
+// 参与时立即发送 FaultStormFaultsPerAircraft 份 CRITICAL 故障报告。
+func joinFaultStorm(a *Aircraft, rng *rand.Rand, commsSystem *CommunicationSystem) {
+	if rng.Float64() >= config.FaultStormFraction {
+		return
+	}
+	var critical []AircraftFaultData
+	for _, fault := range flightFaults {
+		if fault.Severity == "CRITICAL" {
+			critical = append(critical, fault)
+		}
+	}
+	for range config.FaultStormFaultsPerAircraft {
+		fault := critical[rng.IntN(len(critical))]
+		fault.Timestamp = time.Now().UTC()
+		faultStormInjected.Add(1)
+		sendFaultReport(a, fault, commsSystem)
+	}
+}
+
+// inFaultStorm 判断 t 是否处于故障风暴期间 (风暴开始后的 config.FaultStormWindow 内)。
+func inFaultStorm(t time.Time) bool {
+	start := faultStormStart.Load()
+	if start == 0 {
+		return false
+	}
+	since := Unscaled(t.Sub(time.Unix(0, start)))
+	return since >= 0 && since < config.FaultStormWindow
+}
+
+// FaultStormStats 描述故障风暴的触发时刻与注入的故障报告数。
+type FaultStormStats struct {
+	Started  time.Time // 零值表示尚未触发或未启用
+	Injected uint64
+}
+
+// GetFaultStormStats 返回故障风暴的统计数据，用于写入报告。
+func GetFaultStormStats() FaultStormStats {
+	var stats FaultStormStats
+	if start := faultStormStart.Load(); start != 0 {
+		stats.Started = time.Unix(0, start)
+	}
+	stats.Injected = faultStormInjected.Load()
+	return stats
+}
@@ -0,0 +1,138 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// NewMessage 按优先级创建报文。数据超出 config.MaxPayloadBytes 时自动分片，
+// 返回的切片中依次是各个分片；未超出时只包含一条完整报文。
+func NewMessage(base ACARSBaseMessage, priority config.Priority, data interface{}) ([]ACARSMessageInterface, error) {
+	rawData, err := marshalPayload(data)
+	if err == nil {
+		return []ACARSMessageInterface{wrapPayload(priority, base, rawData)}, nil
+	}
+	if !errors.Is(err, ErrPayloadTooLarge) {
+		return nil, err
+	}
+	return NewFragmentedMessage(base, priority, data)
+}
+
+// NewFragmentedMessage 将报文数据拆分为多个相互关联的分片。
+// 每个分片的数据是原始 JSON 的一段 (以 JSON 字符串形式保存)，长度不超过 config.MaxPayloadBytes；
+// 分片的报文ID为 "<原报文ID>/<序号>"，FragmentGroupID 指向原报文ID，接收方据此重组。
+func NewFragmentedMessage(base ACARSBaseMessage, priority config.Priority, data interface{}) ([]ACARSMessageInterface, error) {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks, err := splitPayload(string(rawData), config.MaxPayloadBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	fragments := make([]ACARSMessageInterface, 0, len(chunks))
+	for i, chunk := range chunks {
+		fragBase := base
+		fragBase.MessageID = fmt.Sprintf("%s/%d", base.MessageID, i+1)
+		fragBase.FragmentGroupID = base.MessageID
+		fragBase.FragmentIndex = i + 1
+		fragBase.FragmentCount = len(chunks)
+		fragments = append(fragments, wrapPayload(priority, fragBase, chunk))
+	}
+	return fragments, nil
+}
+
+// splitPayload 将字符串按字符边界切分，使每一段编码为 JSON 字符串后都不超过 limit 字节。
+func splitPayload(s string, limit int) ([]json.RawMessage, error) {
+	const quotes = 2 // JSON 字符串两端的引号
+	var chunks []json.RawMessage
+	start, size := 0, quotes
+	for i, r := range s {
+		encoded, _ := json.Marshal(string(r))
+		runeSize := len(encoded) - quotes
+		if quotes+runeSize > limit {
+			return nil, fmt.Errorf("%w: 单个字符编码后超出上限 %d 字节", ErrPayloadTooLarge, limit)
+		}
+		if size+runeSize > limit {
+			chunk, _ := json.Marshal(s[start:i])
+			chunks = append(chunks, chunk)
+			start, size = i, quotes
+		}
+		size += runeSize
+	}
+	if start < len(s) || len(chunks) == 0 {
+		chunk, _ := json.Marshal(s[start:])
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// fragmentReassembler 缓存收到的分片，在一组分片全部到齐后重组出原始报文。
+type fragmentReassembler struct {
+	mutex  sync.Mutex
+	groups map[string]map[int]ACARSMessageInterface // FragmentGroupID -> 分片序号 -> 分片
+
+	groupsStarted   atomic.Uint64 // 收到第一个分片的分片组数
+	groupsCompleted atomic.Uint64 // 成功重组的分片组数
+}
+
+func newFragmentReassembler() *fragmentReassembler {
+	return &fragmentReassembler{groups: make(map[string]map[int]ACARSMessageInterface)}
+}
+
+// add 缓存一个分片。分片到齐时返回重组后的原始报文以及 true；
+// 重复的分片会被忽略。
+func (r *fragmentReassembler) add(fragment ACARSMessageInterface) (ACARSMessageInterface, bool) {
+	base := fragment.GetBaseMessage()
+
+	r.mutex.Lock()
+	group, ok := r.groups[base.FragmentGroupID]
+	if !ok {
+		group = make(map[int]ACARSMessageInterface, base.FragmentCount)
+		r.groups[base.FragmentGroupID] = group
+		r.groupsStarted.Add(1)
+	}
+	group[base.FragmentIndex] = fragment
+	if len(group) < base.FragmentCount {
+		r.mutex.Unlock()
+		return nil, false
+	}
+	delete(r.groups, base.FragmentGroupID)
+	r.mutex.Unlock()
+
+	var payload []byte
+	for i := 1; i <= base.FragmentCount; i++ {
+		var chunk string
+		raw, _ := group[i].GetData().(json.RawMessage)
+		if err := json.Unmarshal(raw, &chunk); err != nil || !utf8.ValidString(chunk) {
+			return nil, false
+		}
+		payload = append(payload, chunk...)
+	}
+
+	r.groupsCompleted.Add(1)
+	original := base
+	original.MessageID = base.FragmentGroupID
+	original.FragmentGroupID = ""
+	original.FragmentIndex = 0
+	original.FragmentCount = 0
+	return wrapPayload(fragment.GetPriority(), original, payload), true
+}
+
+// stats 返回已开始接收的分片组数和成功重组的分片组数。
+func (r *fragmentReassembler) stats() (started, completed uint64) {
+	return r.groupsStarted.Load(), r.groupsCompleted.Load()
+}
+
+// reset 清空统计计数。尚未收齐的分片仍保留，以便继续重组。
+func (r *fragmentReassembler) reset() {
+	r.groupsStarted.Store(0)
+	r.groupsCompleted.Store(0)
+}
@@ -0,0 +1,88 @@
+package simulation
+
+import (
+	"math"
+	"time"
+)
+
+// earthRadiusKM 地球平均半径 (公里)
+const earthRadiusKM = 6371.0
+
+// knotsToKMPH 节 -> 公里/小时 的换算系数
+const knotsToKMPH = 1.852
+
+// haversineKM 计算两个经纬度坐标之间的大圆距离 (公里)。
+func haversineKM(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dPhi := (lat2 - lat1) * math.Pi / 180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+
+	h := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * earthRadiusKM * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// destinationPoint 计算从起点沿给定航向飞行指定距离后到达的坐标。
+func destinationPoint(lat, lon, headingDeg, distanceKM float64) (float64, float64) {
+	phi1, lambda1 := lat*math.Pi/180, lon*math.Pi/180
+	theta := headingDeg * math.Pi / 180
+	delta := distanceKM / earthRadiusKM
+
+	phi2 := math.Asin(math.Sin(phi1)*math.Cos(delta) + math.Cos(phi1)*math.Sin(delta)*math.Cos(theta))
+	lambda2 := lambda1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(phi1), math.Cos(delta)-math.Sin(phi1)*math.Sin(phi2))
+	return phi2 * 180 / math.Pi, lambda2 * 180 / math.Pi
+}
+
+// CoverageRegion 描述地面站的圆形覆盖区域。
+type CoverageRegion struct {
+	CenterLatitude  float64
+	CenterLongitude float64
+	RadiusKM        float64 // 覆盖半径 (公里)，<=0 表示不限覆盖范围
+}
+
+// Contains 判断给定坐标是否位于覆盖区域内。
+func (r CoverageRegion) Contains(lat, lon float64) bool {
+	if r.RadiusKM <= 0 {
+		return true
+	}
+	return haversineKM(r.CenterLatitude, r.CenterLongitude, lat, lon) <= r.RadiusKM
+}
+
+// flightTrack 描述飞机沿直线航迹的运动。
+// 飞机在 t0 时刻位于距 origin 沿 headingDeg 方向 startDistanceKM 处，
+// 之后以 speedKMPH 的速度沿航迹移动 (负速度表示飞向 origin)。
+type flightTrack struct {
+	originLat       float64
+	originLon       float64
+	headingDeg      float64
+	startDistanceKM float64
+	speedKMPH       float64
+	altitudeFt      float64
+	t0              time.Time
+}
+
+// stationaryTrack 返回一个停留在给定坐标的航迹 (例如在机场地面滑行)。
+func stationaryTrack(lat, lon float64) flightTrack {
+	return flightTrack{originLat: lat, originLon: lon, t0: time.Now()}
+}
+
+// positionAt 计算飞机在给定时刻的位置。
+func (t flightTrack) positionAt(now time.Time) PositionReportData {
+	distance := t.startDistanceKM + t.speedKMPH*Unscaled(now.Sub(t.t0)).Hours()
+	if distance < 0 {
+		distance = 0
+	}
+	lat, lon := destinationPoint(t.originLat, t.originLon, t.headingDeg, distance)
+
+	heading := t.headingDeg
+	if t.speedKMPH < 0 {
+		heading = math.Mod(heading+180, 360)
+	}
+	return PositionReportData{
+		Latitude:  lat,
+		Longitude: lon,
+		Altitude:  t.altitudeFt,
+		Speed:     math.Abs(t.speedKMPH) / knotsToKMPH,
+		Heading:   heading,
+		Timestamp: now,
+	}
+}
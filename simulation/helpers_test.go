@@ -0,0 +1,72 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"testing"
+	"time"
+)
+
+// newTestChannel 创建一个使用主信道默认参数的信道。
+func newTestChannel(id string) *Channel {
+	return NewChannel(id, config.PrimaryPMap, config.PrimaryTimeSlot)
+}
+
+// newTestComms 创建一个以 ch 为唯一信道的通信系统并开始调度。
+func newTestComms(ch *Channel, access MediumAccess) *CommunicationSystem {
+	comms := NewCommunicationSystem(ch, nil, nil, access)
+	comms.StartDispatching()
+	return comms
+}
+
+// newTestStation 创建一个位于机场、覆盖范围不限的地面站。
+func newTestStation(id string) *GroundControlCenter {
+	return NewGroundControlCenter(id, CoverageRegion{CenterLatitude: config.AirportLatitude, CenterLongitude: config.AirportLongitude})
+}
+
+// newTestAircraft 创建一架停在机场的飞机。
+func newTestAircraft(icao, flightID string) *Aircraft {
+	a := NewAircraft(icao, "B-"+icao, "A320", "Airbus", "SN-"+icao, "CCA")
+	a.CurrentFlightID = flightID
+	return a
+}
+
+// startTestEntities 将实体注册到通信系统并启动它们的收件箱处理。
+// 注册在返回前完成，调用方随后发送的报文不会因监听者尚未注册而丢失。
+func startTestEntities(comms *CommunicationSystem, aircraftList []*Aircraft, stations []*GroundControlCenter) {
+	for _, a := range aircraftList {
+		comms.RegisterReceiver(a.listener, true)
+		go a.StartListening(comms)
+	}
+	for _, gcc := range stations {
+		comms.RegisterReceiver(gcc.listener, false)
+		go gcc.StartListening(comms)
+	}
+}
+
+// newTestMessage 创建一个由飞机 a 发出的单帧报文。
+func newTestMessage(t *testing.T, a *Aircraft, messageID string, msgType MessageType, priority config.Priority, data interface{}) ACARSMessageInterface {
+	t.Helper()
+	msgs, err := NewMessage(ACARSBaseMessage{
+		AircraftICAOAddress: a.ICAOAddress, FlightID: a.CurrentFlightID,
+		MessageID: messageID, Type: msgType,
+	}, priority, data)
+	if err != nil {
+		t.Fatalf("创建报文失败: %v", err)
+	}
+	if len(msgs) != 1 {
+		t.Fatalf("报文 %s 被分成了 %d 片", messageID, len(msgs))
+	}
+	return msgs[0]
+}
+
+// waitFor 轮询 cond 直到其成立，超时后以 what 描述失败原因。
+func waitFor(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待超时: %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,63 @@
+package simulation
+
+import (
+	"time"
+)
+
+// IdleGapBounds 是信道空闲间隔直方图各区间的上界 (含)，最后一个区间统计长于最后一个上界的间隔。
+var IdleGapBounds = []time.Duration{20 * time.Millisecond, 100 * time.Millisecond, 500 * time.Millisecond, 2 * time.Second, 10 * time.Second}
+
+// idleTracker 记录信道完全空闲 (没有任何传输) 的总时间以及每段空闲间隔的长度分布，
+// 用于区分“没有流量”造成的低利用率与争用损失造成的低利用率。受 Channel.mutex 保护。
+type idleTracker struct {
+	emptySince time.Time     // 信道最近一次变为完全空闲的时刻，信道上有传输时为零值
+	total      time.Duration // 已结束的空闲间隔之和
+	gaps       []uint64      // 已结束的空闲间隔按 IdleGapBounds 分区间的计数
+}
+
+// begin 记录信道在 now 变为完全空闲。
+func (t *idleTracker) begin(now time.Time) {
+	t.emptySince = now
+}
+
+// end 记录信道在 now 开始一次传输，结束当前的空闲间隔。
+func (t *idleTracker) end(now time.Time) {
+	if t.emptySince.IsZero() {
+		return
+	}
+	gap := Unscaled(now.Sub(t.emptySince))
+	t.emptySince = time.Time{}
+	t.total += gap
+	if t.gaps == nil {
+		t.gaps = make([]uint64, len(IdleGapBounds)+1)
+	}
+	bucket := len(IdleGapBounds)
+	for i, bound := range IdleGapBounds {
+		if gap <= bound {
+			bucket = i
+			break
+		}
+	}
+	t.gaps[bucket]++
+}
+
+// snapshot 返回截至 now 的空闲总时间 (包括尚未结束的空闲间隔) 与各区间的计数。
+func (t *idleTracker) snapshot(now time.Time) (time.Duration, []uint64) {
+	total := t.total
+	if !t.emptySince.IsZero() {
+		total += Unscaled(now.Sub(t.emptySince))
+	}
+	gaps := make([]uint64, len(IdleGapBounds)+1)
+	copy(gaps, t.gaps)
+	return total, gaps
+}
+
+// reset 清零统计；信道此刻完全空闲时从 now 开始计算新的空闲间隔。
+func (t *idleTracker) reset(now time.Time, idle bool) {
+	t.total = 0
+	t.gaps = nil
+	t.emptySince = time.Time{}
+	if idle {
+		t.emptySince = now
+	}
+}
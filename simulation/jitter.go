@@ -0,0 +1,74 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+)
+
+// jitteredTicker 是带随机抖动的周期定时器：每次触发间隔为 interval ± uniform(jitter)。
+// 与 time.Ticker 不同，它基于 time.Timer 实现，调用方在每次从 C 读取后需调用 Reset 安排下一次触发。
+// 抖动可以避免多架飞机的例行报告在运行中相位对齐，造成同步的信道争用高峰。
+type jitteredTicker struct {
+	C        <-chan time.Time
+	timer    *time.Timer
+	interval time.Duration
+	jitter   time.Duration
+	rng      *rand.Rand
+}
+
+// newJitteredTicker 创建并启动一个带抖动的定时器。jitter 为 0 时等价于固定间隔的定时器。
+func newJitteredTicker(interval, jitter time.Duration, rng *rand.Rand) *jitteredTicker {
+	t := &jitteredTicker{interval: interval, jitter: jitter, rng: rng}
+	t.timer = time.NewTimer(Scaled(t.next()))
+	t.C = t.timer.C
+	return t
+}
+
+// next 计算下一次触发前的等待时间，至少为 interval 的十分之一。
+func (t *jitteredTicker) next() time.Duration {
+	d := t.interval
+	if t.jitter > 0 {
+		d += time.Duration(t.rng.Int64N(int64(2*t.jitter)+1)) - t.jitter
+	}
+	return max(d, t.interval/10)
+}
+
+// Reset 安排下一次触发，应在每次从 C 读取后调用。
+func (t *jitteredTicker) Reset() {
+	t.timer.Reset(Scaled(t.next()))
+}
+
+// Stop 停止定时器。
+func (t *jitteredTicker) Stop() {
+	t.timer.Stop()
+}
+
+// slotWait 返回发送方在 waitSlots (至少为 1) 个时隙后进行下一次信道判断前需要等待的时间，
+// 其中包含 uniform[0, jitter) 个时隙的随机抖动 (见 Channel.SlotJitter)，抖动取自 rng。
+func slotWait(waitSlots int, timeSlot time.Duration, jitter float64, rng *rand.Rand) time.Duration {
+	wait := time.Duration(max(waitSlots, 1)) * timeSlot
+	if extra := time.Duration(jitter * float64(timeSlot)); extra > 0 {
+		wait += time.Duration(rng.Int64N(int64(extra)))
+	}
+	return Scaled(wait)
+}
+
+// newSendRand 为一次发送创建独立的随机数生成器，由 config.FlightPlanSeed、发送方ID与报文ID共同决定：
+// 同一配置下每次运行的时隙抖动序列相同，且并发的各次发送不共享 (非并发安全的) 生成器。
+func newSendRand(senderID, msgID string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(senderID))
+	h.Write([]byte{0})
+	h.Write([]byte(msgID))
+	return rand.New(rand.NewPCG(uint64(config.FlightPlanSeed), h.Sum64()))
+}
+
+// newAircraftRand 为飞机创建独立的随机数生成器，由 config.FlightPlanSeed 与飞机 ICAO 地址共同决定，
+// 保证同一配置下每次运行的抖动序列相同。
+func newAircraftRand(icaoAddr string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(icaoAddr))
+	return rand.New(rand.NewPCG(uint64(config.FlightPlanSeed), h.Sum64()))
+}
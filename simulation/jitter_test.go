@@ -0,0 +1,110 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 间隔与抖动按同一负载倍数缩放时，平均报告间隔应为 interval / multiplier，即负载与倍数成正比。
+func TestReportIntervalScalesLinearlyWithLoad(t *testing.T) {
+	const interval, jitter = 5 * time.Minute, 30 * time.Second
+	for _, multiplier := range []float64{1, 10, 50} {
+		ticker := jitteredTicker{
+			interval: scaleIntervalBy(interval, multiplier),
+			jitter:   scaleIntervalBy(jitter, multiplier),
+			rng:      rand.New(rand.NewPCG(1, 2)),
+		}
+		const samples = 20000
+		var total time.Duration
+		for range samples {
+			total += ticker.next()
+		}
+		mean, want := total/samples, scaleIntervalBy(interval, multiplier)
+		if diff := mean - want; diff > want/100 || diff < -want/100 {
+			t.Errorf("负载倍数 %v: 平均间隔 %v，期望 %v", multiplier, mean, want)
+		}
+	}
+}
+
+func TestJitteredTickerLowerBound(t *testing.T) {
+	ticker := jitteredTicker{interval: time.Second, jitter: 10 * time.Second, rng: rand.New(rand.NewPCG(3, 4))}
+	for range 1000 {
+		if d := ticker.next(); d < 100*time.Millisecond {
+			t.Fatalf("等待时间 %v 小于间隔的十分之一", d)
+		}
+	}
+}
+
+// 时隙抖动为 1 时等待时间在 [时隙, 2×时隙) 内；同一发送方与报文ID的抖动序列每次相同。
+func TestSlotWaitJitterRangeAndSeed(t *testing.T) {
+	const slot = 100 * time.Millisecond
+	first, second := newSendRand("GND", "MSG-1"), newSendRand("GND", "MSG-1")
+	for range 1000 {
+		d := slotWait(1, slot, 1, first)
+		if d < slot || d >= 2*slot {
+			t.Fatalf("等待时间 %v 不在 [%v, %v) 内", d, slot, 2*slot)
+		}
+		if again := slotWait(1, slot, 1, second); again != d {
+			t.Fatalf("相同种子的抖动序列不同: %v 与 %v", d, again)
+		}
+	}
+	if d := slotWait(3, slot, 0, first); d != 3*slot {
+		t.Errorf("无抖动时等待 3 个时隙为 %v，期望 %v", d, 3*slot)
+	}
+}
+
+// runLockstepContention 让 senders 个地面站同时开始，各自以 1-坚持 CSMA 连续发送 perSender 个报文，返回信道上的重叠碰撞数。
+// 信道侦听没有传播时延，真实并发下几乎不会有两个发送方在对方开始传输之前同时侦听到空闲；
+// 因此用 OrderedScheduler 把 10ms 内先后到达的尝试归为同一批，模拟同一时刻侦听到空闲而同时发送的发送方。
+func runLockstepContention(t *testing.T, senders, perSender int, slotJitter float64) uint64 {
+	t.Helper()
+	ch := NewChannel("Primary", config.PrimaryPMap, 200*time.Millisecond)
+	ch.CollisionWindow = 20 * time.Millisecond
+	ch.SlotJitter = slotJitter
+	ch.Scheduler = &OrderedScheduler{Window: 10 * time.Millisecond}
+	comms := newTestComms(ch, OnePersistentCSMA{})
+	a := newTestAircraft("A00001", "CCA101")
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := range senders {
+		gcc := newTestStation(fmt.Sprintf("GND-%d", i))
+		msgs := make([]ACARSMessageInterface, perSender)
+		for j := range msgs {
+			msgs[j] = newTestMessage(t, a, fmt.Sprintf("%s-POS-%d", gcc.ID, j), MsgTypePosition, config.HighPriority, a.GetPosition())
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			for _, msg := range msgs {
+				gcc.SendMessage(msg, comms)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	waitFor(t, 2*time.Second, "传输结束", func() bool { return ch.Occupancy() == 0 })
+	return ch.GetRawStats().OverlapCollisions
+}
+
+// 同时开始的发送方在无抖动时每个时隙都同时重试而反复碰撞；时隙抖动为 1 时重试错开，碰撞明显减少。
+// 抖动序列由固定的种子决定，缩放时间使测试在一秒左右完成。
+func TestSlotJitterReducesLockstepCollisions(t *testing.T) {
+	withTransmissionTimes(t, map[MessageType]time.Duration{MsgTypePosition: 50 * time.Millisecond})
+	withTimeScale(t, 0.2)
+
+	lockstep := runLockstepContention(t, 4, 3, 0)
+	jittered := runLockstepContention(t, 4, 3, 1)
+	t.Logf("重叠碰撞数: 无抖动 %d，抖动 %d", lockstep, jittered)
+	if lockstep < 10 {
+		t.Fatalf("无抖动时只发生 %d 次碰撞，同步重试没有形成", lockstep)
+	}
+	if jittered*2 > lockstep {
+		t.Errorf("时隙抖动下发生 %d 次碰撞，没有明显少于无抖动时的 %d 次", jittered, lockstep)
+	}
+}
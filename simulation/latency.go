@@ -0,0 +1,144 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+)
+
+// LatencyPercentiles 是一组端到端时延样本的分位数。
+type LatencyPercentiles struct {
+	Count uint64 // 记录的时延总数 (不只是保留的样本数)
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+}
+
+// latencyReservoir 以蓄水池抽样保留至多 config.LatencySampleSize 个时延样本，使任意长的运行都只占用有界的内存。
+type latencyReservoir struct {
+	samples []time.Duration
+	seen    uint64 // 记录过的时延总数
+}
+
+// add 记录一个时延：样本未满时直接保留，否则以 size/seen 的概率替换一个随机样本。
+func (r *latencyReservoir) add(latency time.Duration) {
+	r.seen++
+	size := max(config.LatencySampleSize, 1)
+	if len(r.samples) < size {
+		r.samples = append(r.samples, latency)
+		return
+	}
+	if i := rand.Uint64N(r.seen); i < uint64(size) {
+		r.samples[i] = latency
+	}
+}
+
+// latencyStats 按报文优先级记录端到端时延。
+type latencyStats struct {
+	mutex      sync.Mutex
+	byPriority map[config.Priority]*latencyReservoir
+}
+
+// record 记录一个优先级为 priority 的报文的端到端时延。
+func (s *latencyStats) record(priority config.Priority, latency time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.byPriority == nil {
+		s.byPriority = make(map[config.Priority]*latencyReservoir)
+	}
+	r, ok := s.byPriority[priority]
+	if !ok {
+		r = &latencyReservoir{}
+		s.byPriority[priority] = r
+	}
+	r.add(latency)
+}
+
+// reset 清空所有样本。
+func (s *latencyStats) reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.byPriority = nil
+}
+
+// weightedLatency 是一个时延样本及其代表的时延数 (蓄水池的 seen / 样本数)。
+type weightedLatency struct {
+	latency time.Duration
+	weight  float64
+}
+
+// weightedSamples 返回给定优先级 (为空时为所有优先级) 的样本及其权重，以及记录过的时延总数。
+func (s *latencyStats) weightedSamples(priority config.Priority) ([]weightedLatency, uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var samples []weightedLatency
+	var count uint64
+	for p, r := range s.byPriority {
+		if priority != "" && p != priority {
+			continue
+		}
+		count += r.seen
+		weight := float64(r.seen) / float64(len(r.samples))
+		for _, latency := range r.samples {
+			samples = append(samples, weightedLatency{latency, weight})
+		}
+	}
+	return samples, count
+}
+
+// latencyPercentiles 由带权重的样本计算分位数。不同蓄水池的样本按其代表的时延数加权，
+// 因此可以合并多个优先级或多架飞机的样本。
+func latencyPercentiles(samples []weightedLatency, count uint64) LatencyPercentiles {
+	result := LatencyPercentiles{Count: count}
+	if len(samples) == 0 {
+		return result
+	}
+	slices.SortFunc(samples, func(a, b weightedLatency) int { return cmp.Compare(a.latency, b.latency) })
+	var total float64
+	for _, s := range samples {
+		total += s.weight
+	}
+	quantile := func(q float64) time.Duration {
+		var cumulative float64
+		for _, s := range samples {
+			cumulative += s.weight
+			if cumulative >= q*total {
+				return s.latency
+			}
+		}
+		return samples[len(samples)-1].latency
+	}
+	result.P50, result.P95, result.P99 = quantile(0.50), quantile(0.95), quantile(0.99)
+	return result
+}
+
+// LatencyPercentiles 返回飞机报文的端到端时延 (开始发送到收到 ACK，包括等待信道与重传) 分位数。
+// priority 为空时统计所有优先级。
+func (a *Aircraft) LatencyPercentiles(priority config.Priority) LatencyPercentiles {
+	return latencyPercentiles(a.latencies.weightedSamples(priority))
+}
+
+// FleetLatencyPercentiles 合并所有飞机的样本，返回给定优先级 (为空时为所有优先级) 的端到端时延分位数。
+func FleetLatencyPercentiles(aircraft []*Aircraft, priority config.Priority) LatencyPercentiles {
+	return fleetPercentiles(aircraft, priority, func(a *Aircraft) *latencyStats { return &a.latencies })
+}
+
+// FleetStormLatencyPercentiles 与 FleetLatencyPercentiles 相同，但只统计在故障风暴期间开始发送的报文。
+func FleetStormLatencyPercentiles(aircraft []*Aircraft, priority config.Priority) LatencyPercentiles {
+	return fleetPercentiles(aircraft, priority, func(a *Aircraft) *latencyStats { return &a.stormLatencies })
+}
+
+// fleetPercentiles 合并所有飞机由 stats 选出的时延样本并计算分位数。
+func fleetPercentiles(aircraft []*Aircraft, priority config.Priority, stats func(*Aircraft) *latencyStats) LatencyPercentiles {
+	var samples []weightedLatency
+	var count uint64
+	for _, a := range aircraft {
+		s, n := stats(a).weightedSamples(priority)
+		samples = append(samples, s...)
+		count += n
+	}
+	return latencyPercentiles(samples, count)
+}
@@ -0,0 +1,67 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"testing"
+	"time"
+)
+
+func TestLatencyReservoirIsBounded(t *testing.T) {
+	size := max(config.LatencySampleSize, 1)
+	var r latencyReservoir
+	for i := range 10 * size {
+		r.add(time.Duration(i) * time.Millisecond)
+	}
+	if len(r.samples) != size {
+		t.Errorf("保留了 %d 个样本，期望 %d", len(r.samples), size)
+	}
+	if r.seen != uint64(10*size) {
+		t.Errorf("seen = %d，期望 %d", r.seen, 10*size)
+	}
+}
+
+func TestLatencyPercentilesExact(t *testing.T) {
+	if config.LatencySampleSize < 100 {
+		t.Skip("样本容量小于 100，分位数不再精确")
+	}
+	var stats latencyStats
+	for i := 100; i >= 1; i-- {
+		stats.record(config.HighPriority, time.Duration(i)*time.Millisecond)
+	}
+	stats.record(config.LowPriority, time.Hour)
+
+	got := latencyPercentiles(stats.weightedSamples(config.HighPriority))
+	want := LatencyPercentiles{Count: 100, P50: 50 * time.Millisecond, P95: 95 * time.Millisecond, P99: 99 * time.Millisecond}
+	if got != want {
+		t.Errorf("HIGH 分位数 = %+v，期望 %+v", got, want)
+	}
+	if all := latencyPercentiles(stats.weightedSamples("")); all.Count != 101 {
+		t.Errorf("所有优先级的时延数 = %d，期望 101", all.Count)
+	}
+}
+
+// 合并多架飞机的样本时按各蓄水池代表的时延数加权：记录了 1000 个时延的飞机比只记录了 10 个的飞机权重大得多。
+func TestFleetPercentilesWeightByReservoirSize(t *testing.T) {
+	busy, quiet := newTestAircraft("A00001", "CCA101"), newTestAircraft("A00002", "CCA102")
+	fill := func(a *Aircraft, latency time.Duration, seen uint64) {
+		r := &latencyReservoir{seen: seen}
+		for range 10 {
+			r.samples = append(r.samples, latency)
+		}
+		a.latencies.byPriority = map[config.Priority]*latencyReservoir{config.HighPriority: r}
+	}
+	fill(busy, 10*time.Millisecond, 1000)
+	fill(quiet, 100*time.Millisecond, 10)
+
+	got := FleetLatencyPercentiles([]*Aircraft{busy, quiet}, config.HighPriority)
+	if got.Count != 1010 {
+		t.Errorf("时延总数 = %d，期望 1010", got.Count)
+	}
+	// 不加权时一半样本为 100ms，P95 将是 100ms
+	if got.P95 != 10*time.Millisecond {
+		t.Errorf("P95 = %v，期望 10ms (按时延数加权)", got.P95)
+	}
+	if all := FleetLatencyPercentiles([]*Aircraft{busy, quiet}, config.LowPriority); all != (LatencyPercentiles{}) {
+		t.Errorf("没有 LOW 样本时分位数 = %+v，期望全为零", all)
+	}
+}
@@ -0,0 +1,103 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"sync/atomic"
+)
+
+// feetToKM 英尺 -> 公里 的换算系数
+const feetToKM = 0.0003048
+
+// minLinkDistanceKM 是计算路径损耗时的最小距离，避免飞机位于地面站正上方时距离为 0。
+const minLinkDistanceKM = 0.1
+
+// freeSpacePathLossDB 计算自由空间路径损耗 (dB)，distanceKM 为直线距离，frequencyMHz 为载波频率。
+func freeSpacePathLossDB(distanceKM, frequencyMHz float64) float64 {
+	distanceKM = math.Max(distanceKM, minLinkDistanceKM)
+	return 20*math.Log10(distanceKM) + 20*math.Log10(frequencyMHz) + 32.44
+}
+
+// slantRangeKM 计算地面站与高度为 altitudeFt 的飞机之间的直线距离 (公里)。
+func slantRangeKM(station CoverageRegion, pos PositionReportData) float64 {
+	ground := haversineKM(station.CenterLatitude, station.CenterLongitude, pos.Latitude, pos.Longitude)
+	altitude := pos.Altitude * feetToKM
+	return math.Hypot(ground, altitude)
+}
+
+// receivedPowerDBm 计算发射功率为 txPowerDBm 的信号经过 distanceKM 后的接收功率。
+func receivedPowerDBm(txPowerDBm, distanceKM float64) float64 {
+	return txPowerDBm - freeSpacePathLossDB(distanceKM, config.CarrierFrequencyMHz)
+}
+
+// weakSignalLossProbability 返回接收功率为 rxPowerDBm 的帧因信号过弱而无法解调的概率。
+// 链路余量 (接收功率高出接收灵敏度的部分) 不小于 config.LinkFadeMarginDB 时不会丢失，
+// 低于接收灵敏度时必定丢失，两者之间按余量线性插值 (简化的衰落模型)。
+func weakSignalLossProbability(rxPowerDBm float64) float64 {
+	margin := rxPowerDBm - config.ReceiverSensitivityDBm
+	switch {
+	case margin >= config.LinkFadeMarginDB:
+		return 0
+	case margin <= 0:
+		return 1
+	default:
+		return 1 - margin/config.LinkFadeMarginDB
+	}
+}
+
+// servingStationRangeKM 返回飞机此刻与其服务地面站之间的直线距离，尚未分配服务地面站时返回 ok=false。
+func (a *Aircraft) servingStationRangeKM() (float64, bool) {
+	pos := a.GetPosition()
+	a.positionMutex.RLock()
+	station, ok := a.stationCoverage[a.servingStationID]
+	a.positionMutex.RUnlock()
+	if !ok {
+		return 0, false
+	}
+	return slantRangeKM(station, pos), true
+}
+
+// downlinkPowerDBm 返回飞机此刻发出的帧在其服务地面站处的接收功率。
+// 尚未分配服务地面站时返回 ok=false，此时不模拟路径损耗。
+func (a *Aircraft) downlinkPowerDBm() (float64, bool) {
+	distanceKM, ok := a.servingStationRangeKM()
+	if !ok {
+		return 0, false
+	}
+	return receivedPowerDBm(a.TxPowerDBm, distanceKM), true
+}
+
+// ReceivedPowerFrom 返回飞机此刻发出的帧在本站处的接收功率 (dBm)。
+func (gcc *GroundControlCenter) ReceivedPowerFrom(a *Aircraft) float64 {
+	return receivedPowerDBm(a.TxPowerDBm, slantRangeKM(gcc.Coverage, a.GetPosition()))
+}
+
+// ReceivedPowerAt 返回本站此刻发出的帧在飞机处的接收功率 (dBm)。
+func (gcc *GroundControlCenter) ReceivedPowerAt(a *Aircraft) float64 {
+	return receivedPowerDBm(gcc.TxPowerDBm, slantRangeKM(gcc.Coverage, a.GetPosition()))
+}
+
+// withLinkBudget 在飞机已分配服务地面站时，为即将发出的帧记录其在地面站处的接收功率。
+func (a *Aircraft) withLinkBudget(frame ACARSMessageInterface) ACARSMessageInterface {
+	if rxPower, ok := a.downlinkPowerDBm(); ok {
+		return withReceivedPower(frame, rxPower)
+	}
+	return frame
+}
+
+// withLinkQuality 按飞机的链路质量系数模拟机载设备造成的额外误帧：帧以 1 - LinkQuality 的概率在传输中损坏。
+func (a *Aircraft) withLinkQuality(frame ACARSMessageInterface) ACARSMessageInterface {
+	if a.LinkQuality <= 0 || a.LinkQuality >= 1 || rand.Float64() < a.LinkQuality {
+		return frame
+	}
+	atomic.AddUint64(&a.linkQualityLosses, 1)
+	slog.Debug("📉 报文因机载链路质量较差而损坏", "flight", a.CurrentFlightID, "msgID", frame.GetBaseMessage().MessageID, "linkQuality", a.LinkQuality)
+	return corruptFrame(frame)
+}
+
+// prepareFrame 在每次传输前为飞机的下行帧应用链路预算、链路质量与通信盲区。
+func (a *Aircraft) prepareFrame(frame ACARSMessageInterface) ACARSMessageInterface {
+	return a.withDeadZone(a.withLinkQuality(a.withLinkBudget(frame)))
+}
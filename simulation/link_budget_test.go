@@ -0,0 +1,72 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWeakSignalLossProbability(t *testing.T) {
+	sensitivity, fade := config.ReceiverSensitivityDBm, config.LinkFadeMarginDB
+	for _, tc := range []struct {
+		rxPowerDBm float64
+		want       float64
+	}{
+		{sensitivity - 1, 1},
+		{sensitivity, 1},
+		{sensitivity + fade/2, 0.5},
+		{sensitivity + fade, 0},
+		{sensitivity + 3*fade, 0},
+	} {
+		if got := weakSignalLossProbability(tc.rxPowerDBm); got != tc.want {
+			t.Errorf("weakSignalLossProbability(%v) = %v，期望 %v", tc.rxPowerDBm, got, tc.want)
+		}
+	}
+}
+
+// 远距离、低功率的飞机在地面站处的接收功率更低，经信道发出的帧因信号过弱而丢失的也更多。
+func TestDistantLowPowerAircraftLosesMoreFrames(t *testing.T) {
+	gcc := newTestStation("GND")
+	near := newTestAircraft("A00001", "CCA101")
+	far := newTestAircraft("A00002", "CCA102")
+	far.setTrack(stationaryTrack(config.AirportLatitude+1, config.AirportLongitude)) // 约 111 公里外
+	far.TxPowerDBm = 20
+	gcc.TrackAircraft([]*Aircraft{near, far})
+	near.swapServingStation(gcc.ID)
+	far.swapServingStation(gcc.ID)
+
+	nearPower, farPower := gcc.ReceivedPowerFrom(near), gcc.ReceivedPowerFrom(far)
+	if farPower >= nearPower {
+		t.Fatalf("远处飞机的接收功率 %.1f dBm 不低于近处飞机的 %.1f dBm", farPower, nearPower)
+	}
+	if p := weakSignalLossProbability(farPower); p <= 0 || p >= 1 {
+		t.Fatalf("远处飞机的丢帧概率 %.2f 应介于 0 与 1 之间 (接收功率 %.1f dBm)", p, farPower)
+	}
+
+	// losses 经一个新信道发出 frames 个帧，返回因信号过弱而丢失的帧数
+	losses := func(a *Aircraft, frames int) uint64 {
+		ch := newTestChannel("Primary")
+		ch.StartDispatching()
+		inbox := make(chan ACARSMessageInterface, frames)
+		capture := NewListener(gcc.ID, inbox)
+		capture.ground = true
+		ch.RegisterListener(capture)
+		for i := range frames {
+			msg := newTestMessage(t, a, fmt.Sprintf("%s-POS-%d", a.CurrentFlightID, i), MsgTypePosition, config.HighPriority, a.GetPosition())
+			ch.deliverDirect(a.withLinkBudget(msg))
+		}
+		waitFor(t, 5*time.Second, "所有帧都已分发", func() bool {
+			return uint64(len(inbox))+ch.GetRawStats().WeakSignalLosses == uint64(frames)
+		})
+		return ch.GetRawStats().WeakSignalLosses
+	}
+	const frames = 200
+	nearLosses, farLosses := losses(near, frames), losses(far, frames)
+	if nearLosses != 0 {
+		t.Errorf("近处飞机丢失了 %d 帧，期望 0", nearLosses)
+	}
+	if farLosses == 0 || farLosses == frames {
+		t.Errorf("远处飞机丢失了 %d/%d 帧，期望部分丢失", farLosses, frames)
+	}
+}
@@ -0,0 +1,113 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// 监听者收件箱已满时可选的处理策略
+const (
+	ListenerDropNew    = "drop-new"    // 丢弃新到达的报文 (默认)
+	ListenerDropOldest = "drop-oldest" // 淘汰收件箱中最早的报文，为新报文腾出空间
+	ListenerBlock      = "block"       // 阻塞等待收件箱出现空位，超时后丢弃新报文
+)
+
+// Listener 是注册到信道上的一个接收方 (飞机或地面站) 的收件箱。
+type Listener struct {
+	OwnerID string
+	ground  bool // 所有者是否为地面站，用于按地址投递
+	queue   chan ACARSMessageInterface
+	drops   atomic.Uint64 // 因收件箱已满而丢弃的报文数
+
+	// --- 半双工 ---
+	transmitting atomic.Int32                // 所有者正在进行的半双工传输数，大于 0 时收不到任何报文
+	onMissed     func(ACARSMessageInterface) // 因所有者正在发送而错过报文时调用，可以为 nil；须在注册到信道前设置
+
+	// --- 可调谐接收机 ---
+	tuner        *tuner                                         // 非 nil 时所有者只有一部可调谐接收机
+	onOffChannel func(msg ACARSMessageInterface, settling bool) // 因未调谐到报文所在信道或正在切换信道而错过报文时调用，可以为 nil
+}
+
+// NewListener 为 ownerID 的收件箱 queue 创建一个监听者。
+func NewListener(ownerID string, queue chan ACARSMessageInterface) *Listener {
+	return &Listener{OwnerID: ownerID, queue: queue}
+}
+
+// Drops 返回因收件箱已满而丢弃的报文数。
+func (l *Listener) Drops() uint64 {
+	return l.drops.Load()
+}
+
+// Depth 返回收件箱中尚未处理的报文数。
+func (l *Listener) Depth() int {
+	return len(l.queue)
+}
+
+// resetDrops 清零丢弃计数。
+func (l *Listener) resetDrops() {
+	l.drops.Store(0)
+}
+
+// addressedTo 判断按地址投递时报文是否应投递给该监听者：广播报文与未注明收件人的报文投递给所有监听者，
+// 飞机发出的报文只投递给地面站，地面站发出的报文只投递给其收件飞机。
+func (l *Listener) addressedTo(msg ACARSMessageInterface) bool {
+	base := msg.GetBaseMessage()
+	switch {
+	case base.Broadcast:
+		return true
+	case !base.FromGround():
+		return l.ground
+	case len(base.Recipients) == 0:
+		return true
+	default:
+		return slices.Contains(base.Recipients, l.OwnerID)
+	}
+}
+
+// receiving 判断所有者此刻能否接收报文：半双工的所有者在发送期间无法接收。
+func (l *Listener) receiving() bool {
+	return l.transmitting.Load() == 0
+}
+
+// miss 记录所有者因正在发送而错过的一个报文。
+func (l *Listener) miss(msg ACARSMessageInterface) {
+	if l.onMissed != nil {
+		l.onMissed(msg)
+	}
+}
+
+// deliver 按 config.ListenerOverflowPolicy 将报文投递到收件箱，返回新报文是否被投递。
+// 注意 "block" 策略会阻塞信道的分发 goroutine，最长 config.ListenerBlockTimeout。
+func (l *Listener) deliver(msg ACARSMessageInterface) bool {
+	select {
+	case l.queue <- msg:
+		return true
+	default:
+	}
+
+	switch config.ListenerOverflowPolicy {
+	case ListenerDropOldest:
+		for {
+			select {
+			case <-l.queue:
+				l.drops.Add(1) // 被淘汰的旧报文计为一次丢弃
+			default:
+			}
+			select {
+			case l.queue <- msg:
+				return true
+			default:
+			}
+		}
+	case ListenerBlock:
+		select {
+		case l.queue <- msg:
+			return true
+		case <-time.After(Scaled(config.ListenerBlockTimeout)):
+		}
+	}
+	l.drops.Add(1)
+	return false
+}
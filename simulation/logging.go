@@ -0,0 +1,35 @@
+package simulation
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// NewLogger 创建一个按级别过滤的结构化日志记录器，输出 key=value 格式便于机器解析。
+// level 的取值见 ParseLogLevel：
+// 信道接入等逐时隙的细节记录在 DEBUG 级别，飞行计划与模拟阶段的边界记录在 INFO 级别。
+func NewLogger(w io.Writer, level string) (*slog.Logger, error) {
+	lvl, err := ParseLogLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: lvl})), nil
+}
+
+// ParseLogLevel 解析日志级别，可选 "DEBUG"、"INFO"、"WARN"、"ERROR" (不区分大小写)，空字符串视为 "INFO"。
+func ParseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToUpper(level) {
+	case "DEBUG":
+		return slog.LevelDebug, nil
+	case "INFO", "":
+		return slog.LevelInfo, nil
+	case "WARN":
+		return slog.LevelWarn, nil
+	case "ERROR":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("未知的日志级别: %q", level)
+	}
+}
@@ -0,0 +1,85 @@
+package simulation
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// MediumAccess 定义了信道接入策略。发送方在每个时隙调用一次 ShouldTransmit：
+//   - channelBusy: 目标信道此刻是否被占用
+//   - p: 目标信道为该报文优先级配置的 p 值 (不使用 p 的策略可以忽略)
+//   - attempt: 该报文在本轮发送中已经经历的时隙数 (从 0 开始)
+//
+// 返回值 transmit 表示是否立即尝试传输；waitSlots 表示本时隙结束后 (未传输或传输发生碰撞时)
+// 需要等待多少个时隙再进行下一次判断，至少为 1。
+type MediumAccess interface {
+	Name() string
+	ShouldTransmit(channelBusy bool, p float64, attempt int) (transmit bool, waitSlots int)
+}
+
+// 可通过配置选择的信道接入策略名称
+const (
+	AccessPPersistentCSMA = "p-persistent-CSMA"
+	AccessOnePersistent   = "1-persistent-CSMA"
+	AccessPureALOHA       = "pure-ALOHA"
+	AccessSlottedALOHA    = "slotted-ALOHA"
+)
+
+// NewMediumAccess 根据策略名称创建对应的信道接入策略。
+func NewMediumAccess(name string) (MediumAccess, error) {
+	switch name {
+	case AccessPPersistentCSMA, "":
+		return PPersistentCSMA{}, nil
+	case AccessOnePersistent:
+		return OnePersistentCSMA{}, nil
+	case AccessPureALOHA:
+		return PureALOHA{MaxBackoffExponent: 6}, nil
+	case AccessSlottedALOHA:
+		return SlottedALOHA{}, nil
+	default:
+		return nil, fmt.Errorf("未知的信道接入策略: %q", name)
+	}
+}
+
+// PPersistentCSMA 是 p-坚持 CSMA：信道空闲时以概率 p 传输，否则等待一个时隙后重新监听。
+type PPersistentCSMA struct{}
+
+func (PPersistentCSMA) Name() string { return AccessPPersistentCSMA }
+
+func (PPersistentCSMA) ShouldTransmit(channelBusy bool, p float64, _ int) (bool, int) {
+	if channelBusy {
+		return false, 1
+	}
+	return rand.Float64() < p, 1
+}
+
+// OnePersistentCSMA 是 1-坚持 CSMA：信道一旦空闲立即传输。
+type OnePersistentCSMA struct{}
+
+func (OnePersistentCSMA) Name() string { return AccessOnePersistent }
+
+func (OnePersistentCSMA) ShouldTransmit(channelBusy bool, _ float64, _ int) (bool, int) {
+	return !channelBusy, 1
+}
+
+// PureALOHA 是纯 ALOHA：不监听信道，有报文就立即传输；
+// 发生碰撞后按二进制指数退避随机等待若干时隙。
+type PureALOHA struct {
+	MaxBackoffExponent int // 退避窗口的最大指数，窗口为 [1, 2^k]
+}
+
+func (PureALOHA) Name() string { return AccessPureALOHA }
+
+func (a PureALOHA) ShouldTransmit(_ bool, _ float64, attempt int) (bool, int) {
+	window := 1 << min(attempt, a.MaxBackoffExponent)
+	return true, 1 + rand.IntN(window)
+}
+
+// SlottedALOHA 是时隙 ALOHA：不监听信道，每个时隙以概率 p 传输。
+type SlottedALOHA struct{}
+
+func (SlottedALOHA) Name() string { return AccessSlottedALOHA }
+
+func (SlottedALOHA) ShouldTransmit(_ bool, p float64, _ int) (bool, int) {
+	return rand.Float64() < p, 1
+}
@@ -3,9 +3,15 @@ package simulation
 import (
 	"Air-Simulator/config"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 )
 
+// ErrPayloadTooLarge 表示报文数据超出了单个 ACARS 报文块的最大长度 (config.MaxPayloadBytes)。
+// 调用方可以改用 NewFragmentedMessage 将其拆分为多个分片发送。
+var ErrPayloadTooLarge = errors.New("报文数据超出最大报文块长度")
+
 // MessageType 定义了 ACARS 报文的类型，便于识别
 type MessageType string
 
@@ -17,25 +23,61 @@ const (
 	MsgTypePosition MessageType = "POSITION_REPORT" // 位置报告
 	MsgTypeFuel     MessageType = "FUEL_REPORT"     // 燃油报告
 
-	MsgTypeEngineReport MessageType = "ENGINE_REPORT"  // 发动机性能报告
-	MsgTypeWeather      MessageType = "WEATHER_REPORT" // 天气请求/报告
-	MsgTypePDC          MessageType = "PDC"            // 预发离港许可
-	MsgTypeDATIS        MessageType = "D_ATIS"         // 数字自动终端信息服务
+	MsgTypeEngineReport   MessageType = "ENGINE_REPORT"   // 发动机性能报告
+	MsgTypeWeather        MessageType = "WEATHER_REPORT"  // 天气报告 (也用于地面站对气象请求的上行回复)
+	MsgTypeWeatherRequest MessageType = "WEATHER_REQUEST" // 向地面站请求气象信息
+	MsgTypePDC            MessageType = "PDC"             // 预发离港许可
+	MsgTypeDATIS          MessageType = "D_ATIS"          // 数字自动终端信息服务
 
 	MsgTypeFreeText MessageType = "FREE_TEXT"       // 自由文本消息
 	MsgTypeLinkTest MessageType = "LINK_TEST"       // ACARS 链路测试
 	MsgTypeAck      MessageType = "ACKNOWLEDGEMENT" // 确认消息
 )
 
+// MessageOrigin 标识报文的发送方角色。
+type MessageOrigin string
+
+const (
+	OriginAircraft MessageOrigin = ""       // 飞机发出的报文 (零值)
+	OriginGround   MessageOrigin = "GROUND" // 地面站发出的报文 (ACK/NACK、D-ATIS 广播等)
+)
+
 // ACARSBaseMessage 包含了所有 ACARS 报文的通用头部信息
 type ACARSBaseMessage struct {
-	AircraftICAOAddress string      `json:"aircraftICAOAddress"` // 飞机ICAO地址 (例如: "A87654")
-	FlightID            string      `json:"flightID"`            // 航班号 (例如: "CCA123")
-	MessageID           string      `json:"messageID"`           // 唯一的报文ID
-	Timestamp           time.Time   `json:"timestamp"`           // 报文发送时间
-	Type                MessageType `json:"type"`                // 报文的具体类型
+	AircraftICAOAddress string        `json:"aircraftICAOAddress"` // 飞机ICAO地址 (例如: "A87654")
+	FlightID            string        `json:"flightID"`            // 航班号 (例如: "CCA123")
+	MessageID           string        `json:"messageID"`           // 唯一的报文ID
+	Timestamp           time.Time     `json:"timestamp"`           // 报文发送时间
+	Type                MessageType   `json:"type"`                // 报文的具体类型
+	Origin              MessageOrigin `json:"origin,omitempty"`    // 发送方角色，地面站发出的报文为 OriginGround
+
+	// --- 寻址信息 (仅地面站发出的报文使用，见 config.AddressedDelivery) ---
+	Recipients []string `json:"recipients,omitempty"` // 收件飞机的 ICAO 地址 (累积 ACK 可能有多个)
+	Broadcast  bool     `json:"broadcast,omitempty"`  // 是否为投递给所有监听者的广播
+
+	// --- 分片信息 (仅分片报文使用) ---
+	FragmentGroupID string `json:"fragmentGroupID,omitempty"` // 所属原始报文的ID
+	FragmentIndex   int    `json:"fragmentIndex,omitempty"`   // 分片序号，从 1 开始
+	FragmentCount   int    `json:"fragmentCount,omitempty"`   // 该报文的分片总数
+
+	Checksum uint32 `json:"checksum"` // 头部与数据的 CRC-32 校验和，由构造函数计算，发送时随时间戳一并更新
+
+	// --- 物理层信息 (不属于报文内容，不参与序列化) ---
+	rxPowerDBm   float64 // 该帧在接收端的接收功率，0 表示未模拟路径损耗
+	fromDeadZone bool    // 发送方位于通信盲区内，该帧到不了任何地面站
 }
 
+// FromGround 判断报文是否由地面站发出。
+func (b ACARSBaseMessage) FromGround() bool { return b.Origin == OriginGround }
+
+// Ackable 判断地面站收到该报文后是否应当回复 ACK：只有飞机发出的、非 ACK 且非广播的报文需要确认。
+func (b ACARSBaseMessage) Ackable() bool {
+	return !b.FromGround() && b.Type != MsgTypeAck && b.Type != MsgTypeDATIS
+}
+
+// IsFragment 判断报文是否为一个分片。
+func (b ACARSBaseMessage) IsFragment() bool { return b.FragmentCount > 0 }
+
 // ACARSMessageInterface 定义一个接口，用于统一处理所有优先级的 ACARS 消息
 type ACARSMessageInterface interface {
 	GetBaseMessage() ACARSBaseMessage
@@ -45,11 +87,12 @@ type ACARSMessageInterface interface {
 
 // AircraftFaultData 飞机系统故障数据
 type AircraftFaultData struct {
-	FaultCode   string    `json:"faultCode"`   // 故障代码 (例如: "ENG1-OVHT")
-	Description string    `json:"description"` // 故障描述
-	Severity    string    `json:"severity"`    // 严重性 (例如: "CRITICAL", "MAJOR")
-	Timestamp   time.Time `json:"faultTime"`   // 故障发生时间
-	System      string    `json:"system"`      // 发生故障的系统 (例如: "ENGINE_1", "HYDRAULIC")
+	FaultCode   string    `json:"faultCode"`            // 故障代码 (例如: "ENG1-OVHT")
+	Description string    `json:"description"`          // 故障描述
+	Severity    string    `json:"severity"`             // 严重性 (例如: "CRITICAL", "MAJOR")
+	Timestamp   time.Time `json:"faultTime"`            // 故障发生时间
+	System      string    `json:"system"`               // 发生故障的系统 (例如: "ENGINE_1", "HYDRAULIC")
+	Escalation  int       `json:"escalation,omitempty"` // 升级级别：此前的上报成为死信后重新上报的次数
 }
 
 // ATCMessageData 空中交通管制消息数据
@@ -60,8 +103,41 @@ type ATCMessageData struct {
 }
 
 type AcknowledgementData struct {
-	OriginalMessageID string `json:"originalMessageID"` // 确认的是哪条原始报文的ID
-	Status            string `json:"status"`            // 确认状态 (例如: "RECEIVED", "FAILED")
+	OriginalMessageID string    `json:"originalMessageID,omitempty"` // 确认的是哪条原始报文的ID
+	Status            string    `json:"status,omitempty"`            // 确认状态 (例如: "RECEIVED", "FAILED")
+	OriginalTimestamp time.Time `json:"originalTimestamp,omitzero"`  // 原始报文的发送时间，用于发送方计算往返时间
+	Reason            string    `json:"reason,omitempty"`            // NACK (Status 为 AckStatusFailed) 的拒绝原因
+
+	Acks []AckEntry `json:"acks,omitempty"` // 累积 ACK 中一并确认的所有报文，此时上面的字段为空
+}
+
+// AckEntry 是累积 ACK 中的一条确认，使用紧凑的字段名以便一帧容纳更多确认。
+type AckEntry struct {
+	OriginalMessageID string `json:"i"`
+	Status            string `json:"s"`
+	SentAtUnixMilli   int64  `json:"t"` // 原始报文的发送时间 (Unix 毫秒)，0 表示未知
+	Reason            string `json:"r,omitempty"`
+}
+
+// AckStatusFailed 是 NACK 的状态：地面站拒绝了格式错误的报文，发送方不应再重传。
+const AckStatusFailed = "FAILED"
+
+// IsNack 判断一条确认是否为 NACK。
+func (d AcknowledgementData) IsNack() bool { return d.Status == AckStatusFailed }
+
+// Entries 将单个 ACK 或累积 ACK 统一展开为逐条的确认。
+func (d AcknowledgementData) Entries() []AcknowledgementData {
+	if len(d.Acks) == 0 {
+		return []AcknowledgementData{d}
+	}
+	entries := make([]AcknowledgementData, len(d.Acks))
+	for i, ack := range d.Acks {
+		entries[i] = AcknowledgementData{OriginalMessageID: ack.OriginalMessageID, Status: ack.Status, Reason: ack.Reason}
+		if ack.SentAtUnixMilli != 0 {
+			entries[i].OriginalTimestamp = time.UnixMilli(ack.SentAtUnixMilli)
+		}
+	}
+	return entries
 }
 
 // CriticalPriorityMessage 封装了紧急/高优先级的 ACARS 报文
@@ -81,10 +157,11 @@ func (m CriticalPriorityMessage) GetPriority() config.Priority { return config.C
 
 // 实例化一个高风险的Message
 func NewCriticalPriorityMessage(base ACARSBaseMessage, data interface{}) (CriticalPriorityMessage, error) {
-	rawData, err := json.Marshal(data)
+	rawData, err := marshalPayload(data)
 	if err != nil {
 		return CriticalPriorityMessage{}, err
 	}
+	base.Checksum = payloadChecksum(base, rawData)
 	return CriticalPriorityMessage{
 		ACARSBaseMessage: base,
 		Data:             rawData,
@@ -135,10 +212,11 @@ func (m HighMediumPriorityMessage) GetPriority() config.Priority { return config
 
 // Helper function to create HighMediumPriorityMessage
 func NewHighMediumPriorityMessage(base ACARSBaseMessage, data interface{}) (HighMediumPriorityMessage, error) {
-	rawData, err := json.Marshal(data)
+	rawData, err := marshalPayload(data)
 	if err != nil {
 		return HighMediumPriorityMessage{}, err
 	}
+	base.Checksum = payloadChecksum(base, rawData)
 	return HighMediumPriorityMessage{
 		ACARSBaseMessage: base,
 		Data:             rawData,
@@ -158,9 +236,10 @@ type EngineReportData struct {
 
 // WeatherData 天气请求/报告数据
 type WeatherData struct {
-	RequestType string `json:"requestType"` // 请求类型 (例如: "METAR", "TAF", "WINDS ALOFT")
-	Location    string `json:"location"`    // 机场ICAO代码或区域 (例如: "ZSSS")
-	Content     string `json:"content"`     // 实际天气信息文本
+	RequestType string `json:"requestType"`         // 请求类型 (例如: "METAR", "TAF", "WINDS ALOFT")
+	Location    string `json:"location"`            // 机场ICAO代码或区域 (例如: "ZSSS")
+	Content     string `json:"content"`             // 实际天气信息文本
+	RequestID   string `json:"requestID,omitempty"` // 地面站上行回复所对应的请求报文ID
 }
 
 // PDCData 预发离港许可数据
@@ -196,10 +275,11 @@ func (m MediumLowPriorityMessage) GetPriority() config.Priority { return config.
 
 // Helper function to create MediumLowPriorityMessage
 func NewMediumLowPriorityMessage(base ACARSBaseMessage, data interface{}) (MediumLowPriorityMessage, error) {
-	rawData, err := json.Marshal(data)
+	rawData, err := marshalPayload(data)
 	if err != nil {
 		return MediumLowPriorityMessage{}, err
 	}
+	base.Checksum = payloadChecksum(base, rawData)
 	return MediumLowPriorityMessage{
 		ACARSBaseMessage: base,
 		Data:             rawData,
@@ -235,12 +315,82 @@ func (m LowAuxiliaryPriorityMessage) GetPriority() config.Priority { return conf
 
 // LowAuxiliaryPriorityMessage实例化函数
 func NewLowAuxiliaryPriorityMessage(base ACARSBaseMessage, data interface{}) (LowAuxiliaryPriorityMessage, error) {
-	rawData, err := json.Marshal(data)
+	rawData, err := marshalPayload(data)
 	if err != nil {
 		return LowAuxiliaryPriorityMessage{}, err
 	}
+	base.Checksum = payloadChecksum(base, rawData)
 	return LowAuxiliaryPriorityMessage{
 		ACARSBaseMessage: base,
 		Data:             rawData,
 	}, nil
 }
+
+// SizeBytes 返回报文在空中占用的字节数：固定的帧头开销 config.FrameHeaderBytes 加上数据部分的长度。
+func SizeBytes(msg ACARSMessageInterface) int {
+	rawData, _ := msg.GetData().(json.RawMessage)
+	return config.FrameHeaderBytes + len(rawData)
+}
+
+// withTimestamp 返回一个报文头部发送时间被更新为 t 的报文副本。
+// 发送方在每次真正发出报文前调用，使接收方回执中的时间戳反映实际的发送时刻。
+// 时间戳属于校验范围，因此同时重新计算校验和。
+func withTimestamp(msg ACARSMessageInterface, t time.Time) ACARSMessageInterface {
+	rawData, _ := msg.GetData().(json.RawMessage)
+	return withBase(msg, func(base *ACARSBaseMessage) {
+		base.Timestamp = t
+		base.Checksum = payloadChecksum(*base, rawData)
+	})
+}
+
+// withReceivedPower 返回一个记录了接收功率的报文副本，信道分发时据此模拟弱信号丢帧。
+func withReceivedPower(msg ACARSMessageInterface, rxPowerDBm float64) ACARSMessageInterface {
+	return withBase(msg, func(base *ACARSBaseMessage) { base.rxPowerDBm = rxPowerDBm })
+}
+
+// withBase 返回一个报文头部经 update 修改后的报文副本。
+func withBase(msg ACARSMessageInterface, update func(*ACARSBaseMessage)) ACARSMessageInterface {
+	switch m := msg.(type) {
+	case CriticalPriorityMessage:
+		update(&m.ACARSBaseMessage)
+		return m
+	case HighMediumPriorityMessage:
+		update(&m.ACARSBaseMessage)
+		return m
+	case MediumLowPriorityMessage:
+		update(&m.ACARSBaseMessage)
+		return m
+	case LowAuxiliaryPriorityMessage:
+		update(&m.ACARSBaseMessage)
+		return m
+	default:
+		return msg
+	}
+}
+
+// marshalPayload 将报文数据序列化为 JSON，并检查其长度是否超出 config.MaxPayloadBytes。
+func marshalPayload(data interface{}) (json.RawMessage, error) {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rawData) > config.MaxPayloadBytes {
+		return nil, fmt.Errorf("%w: %d 字节 (上限 %d 字节)", ErrPayloadTooLarge, len(rawData), config.MaxPayloadBytes)
+	}
+	return rawData, nil
+}
+
+// wrapPayload 按优先级将已序列化的数据封装为对应类型的报文，不做长度检查。
+func wrapPayload(priority config.Priority, base ACARSBaseMessage, rawData json.RawMessage) ACARSMessageInterface {
+	base.Checksum = payloadChecksum(base, rawData)
+	switch priority {
+	case config.CriticalPriority:
+		return CriticalPriorityMessage{ACARSBaseMessage: base, Data: rawData}
+	case config.HighPriority:
+		return HighMediumPriorityMessage{ACARSBaseMessage: base, Data: rawData}
+	case config.MediumPriority:
+		return MediumLowPriorityMessage{ACARSBaseMessage: base, Data: rawData}
+	default:
+		return LowAuxiliaryPriorityMessage{ACARSBaseMessage: base, Data: rawData}
+	}
+}
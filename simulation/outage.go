@@ -0,0 +1,94 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"log/slog"
+	"time"
+)
+
+// 地面站离线期间对收到的报文可选的处理策略
+const (
+	OutageBuffer = "buffer" // 缓存报文，恢复在线后补处理并回复 ACK (默认)
+	OutageDrop   = "drop"   // 直接丢弃报文，由发送方自行重传
+)
+
+// OutageWindow 记录地面站的一次离线时段，End 为零值表示仍处于离线状态。
+type OutageWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// SetAvailable 设置地面站是否在线。离线期间地面站不回复 ACK、不发布 D-ATIS，
+// 收到的报文按 config.GroundStationOutagePolicy 缓存或丢弃；恢复在线时补处理缓存的报文。
+func (gcc *GroundControlCenter) SetAvailable(available bool) {
+	gcc.outageMutex.Lock()
+	if gcc.available == available {
+		gcc.outageMutex.Unlock()
+		return
+	}
+	gcc.available = available
+	now := time.Now()
+	if !available {
+		gcc.outages = append(gcc.outages, OutageWindow{Start: now})
+		gcc.outageMutex.Unlock()
+		slog.Warn("🔌 地面站离线", "station", gcc.ID, "policy", config.GroundStationOutagePolicy)
+		return
+	}
+	gcc.outages[len(gcc.outages)-1].End = now
+	buffered, comms := gcc.outageBuffer, gcc.comms
+	gcc.outageBuffer = nil
+	gcc.outageMutex.Unlock()
+
+	slog.Info("🔌 地面站恢复在线，开始补处理离线期间缓存的报文", "station", gcc.ID, "buffered", len(buffered))
+	go func() {
+		for _, msg := range buffered {
+			gcc.startProcessing(msg, comms)
+		}
+	}()
+}
+
+// IsAvailable 返回地面站当前是否在线。
+func (gcc *GroundControlCenter) IsAvailable() bool {
+	gcc.outageMutex.Lock()
+	defer gcc.outageMutex.Unlock()
+	return gcc.available
+}
+
+// Outages 返回地面站所有离线时段的副本。
+func (gcc *GroundControlCenter) Outages() []OutageWindow {
+	gcc.outageMutex.Lock()
+	defer gcc.outageMutex.Unlock()
+	return append([]OutageWindow(nil), gcc.outages...)
+}
+
+// ScheduleOutage 在 start 之后让地面站离线 duration，然后恢复在线。
+// 它应该在一个单独的goroutine中运行；done 被关闭时立即恢复在线并返回。
+func (gcc *GroundControlCenter) ScheduleOutage(start, duration time.Duration, done <-chan struct{}) {
+	select {
+	case <-time.After(Scaled(start)):
+	case <-done:
+		return
+	}
+	gcc.SetAvailable(false)
+	select {
+	case <-time.After(Scaled(duration)):
+	case <-done:
+	}
+	gcc.SetAvailable(true)
+}
+
+// holdDuringOutage 在地面站离线时按策略缓存或丢弃报文，返回报文是否已被截留。
+func (gcc *GroundControlCenter) holdDuringOutage(msg ACARSMessageInterface) bool {
+	gcc.outageMutex.Lock()
+	defer gcc.outageMutex.Unlock()
+	if gcc.available {
+		return false
+	}
+	if config.GroundStationOutagePolicy == OutageBuffer && len(gcc.outageBuffer) < config.GroundStationOutageBufferSize {
+		gcc.outageBuffer = append(gcc.outageBuffer, msg)
+		gcc.outageBuffered++
+	} else {
+		gcc.outageDropped++
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// 发送中的报文已达上限时可选的处理策略
+const (
+	OutboundRejectNew  = "reject-new"  // 拒绝新报文 (默认)
+	OutboundDropLowest = "drop-lowest" // 放弃发送中优先级最低的报文，为更高优先级的新报文腾出位置
+)
+
+// admitOutbound 让报文进入发送流程并登记到 book，发送中的报文已达 MaxQueueLength 时按 config.OutboundQueueOverflowPolicy 处理。
+// 返回 false 表示新报文被拒绝 (已记为死信)；被放弃的发送中报文由其发送方在下一个时隙或重传前察觉并停止。
+func (t *Transmitter) admitOutbound(book *deadLetterBook, msg ACARSMessageInterface, from sender, enqueuedAt time.Time) bool {
+	msgID := msg.GetBaseMessage().MessageID
+	logMessageEvent(MessageEventEnqueued, from.id, msgID, "", "")
+	evicted, ok := book.admit(msg, enqueuedAt, t.MaxQueueLength, config.OutboundQueueOverflowPolicy)
+	switch {
+	case !ok:
+		atomic.AddUint64(&t.queueOverflowDrops, 1)
+		slog.Warn("🚮 发送中的报文已达上限，拒绝新报文", from.logKey, from.id, "msgID", msgID, "priority", msg.GetPriority(), "limit", t.MaxQueueLength)
+	case evicted != "":
+		atomic.AddUint64(&t.queueOverflowDrops, 1)
+		slog.Warn("🚮 发送中的报文已达上限，放弃低优先级报文", from.logKey, from.id, "msgID", evicted, "for", msgID, "limit", t.MaxQueueLength)
+	}
+	return ok
+}
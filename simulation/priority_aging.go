@@ -0,0 +1,44 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"time"
+)
+
+// priorityLevels 按从低到高的顺序列出所有优先级，下标即为优先级的数值。
+var priorityLevels = []config.Priority{
+	config.LowPriority,
+	config.MediumPriority,
+	config.HighPriority,
+	config.CriticalPriority,
+}
+
+// priorityValue 返回优先级的数值，数值越大优先级越高。
+func priorityValue(priority config.Priority) int {
+	for i, p := range priorityLevels {
+		if p == priority {
+			return i
+		}
+	}
+	return 0
+}
+
+// effectivePriority 返回报文等待 wait 时长后的有效优先级数值：
+// 基础数值 + floor(wait / config.PriorityAgingStep)，最高不超过 CRITICAL。
+// PriorityAgingStep 为 0 时不进行老化，始终返回基础数值。
+func effectivePriority(priority config.Priority, wait time.Duration) int {
+	value := priorityValue(priority)
+	step := config.PriorityAgingStep
+	if step <= 0 {
+		return value
+	}
+	return min(value+int(wait/step), len(priorityLevels)-1)
+}
+
+// agedPriority 将 effectivePriority 的数值映射回优先级，用于查询 p 值和信道切换策略。
+func agedPriority(priority config.Priority, wait time.Duration) config.Priority {
+	if config.PriorityAgingStep <= 0 {
+		return priority
+	}
+	return priorityLevels[effectivePriority(priority, wait)]
+}
@@ -0,0 +1,61 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"log/slog"
+)
+
+// contention 跟踪一个发送方当前在哪个信道上等待发送，用于检测优先级反转。
+// 发送方每个时隙可能选择不同的信道，wait 会自动从之前的信道上撤销登记。
+type contention struct {
+	channel  *Channel
+	priority config.Priority
+}
+
+// wait 登记发送方正以 priority 在信道 c 上等待。
+func (ct *contention) wait(c *Channel, priority config.Priority) {
+	if ct.channel == c {
+		return
+	}
+	ct.leave()
+	c.enterContention(priority)
+	ct.channel, ct.priority = c, priority
+}
+
+// leave 撤销发送方的等待登记 (获得信道或放弃发送时调用)。
+func (ct *contention) leave() {
+	if ct.channel != nil {
+		ct.channel.leaveContention(ct.priority)
+		ct.channel = nil
+	}
+}
+
+// enterContention 记录一个以 priority 在本信道上等待的发送方。
+func (c *Channel) enterContention(priority config.Priority) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.contenders == nil {
+		c.contenders = make(map[config.Priority]int)
+	}
+	c.contenders[priority]++
+}
+
+// leaveContention 撤销一个以 priority 在本信道上等待的发送方。
+func (c *Channel) leaveContention(priority config.Priority) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.contenders[priority]--
+}
+
+// checkPriorityInversionLocked 在一个优先级为 priority 的帧获得信道时，检查是否有更高优先级的发送方仍在等待，
+// 有则记为一次优先级反转。调用方必须持有 c.mutex。
+func (c *Channel) checkPriorityInversionLocked(priority config.Priority, msgID, senderID string) {
+	for waiting, count := range c.contenders {
+		if count > 0 && priorityValue(waiting) > priorityValue(priority) {
+			c.priorityInversions.Add(1)
+			slog.Debug("🔀 优先级反转: 低优先级帧获得信道，而更高优先级的帧仍在等待", "channel", c.ID, "sender", senderID,
+				"msgID", msgID, "priority", priority, "waiting", waiting)
+			return
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 可通过配置选择的飞机接收机模型
+const (
+	ReceiverMulti = "multi" // 每个信道一部接收机，同时收听所有已注册的信道 (默认)
+	ReceiverTuned = "tuned" // 单部可调谐接收机，只收听当前调谐的信道
+)
+
+// CheckAircraftReceiver 检查 mode 是否为已知的接收机模型。
+func CheckAircraftReceiver(mode string) error {
+	switch mode {
+	case ReceiverMulti, ReceiverTuned:
+		return nil
+	default:
+		return fmt.Errorf("未知的接收机模型 %q", mode)
+	}
+}
+
+// tuner 是监听者的单部可调谐接收机：监听者仍注册在所有信道上，但只有当前调谐的信道上的报文才会投递给它。
+type tuner struct {
+	mutex       sync.Mutex
+	channels    []*Channel    // 监听者注册的信道，只能调谐到其中之一
+	current     *Channel      // 当前调谐的信道，注册第一个信道时调谐到该信道
+	settleUntil time.Time     // 切换信道后接收机稳定的时刻，此前收不到任何报文
+	retunes     atomic.Uint64 // 切换信道的次数
+}
+
+// register 记录监听者注册的信道，尚未调谐时调谐到该信道。
+func (t *tuner) register(ch *Channel) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.channels = append(t.channels, ch)
+	if t.current == nil {
+		t.current = ch
+	}
+}
+
+// retune 将接收机调谐到 ch，返回是否切换了信道。ch 不是已注册的信道或已调谐到 ch 时不做任何事。
+func (t *tuner) retune(ch *Channel) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if ch == t.current || !slices.Contains(t.channels, ch) {
+		return false
+	}
+	t.current = ch
+	t.settleUntil = time.Now().Add(Scaled(config.ReceiverRetuneTime))
+	t.retunes.Add(1)
+	return true
+}
+
+// hears 判断接收机此刻能否收到 ch 上的报文；收不到时 settling 表示接收机正调谐到 ch 且尚未稳定。
+func (t *tuner) hears(ch *Channel) (heard, settling bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if ch != t.current {
+		return false, false
+	}
+	if time.Now().Before(t.settleUntil) {
+		return false, true
+	}
+	return true, false
+}
+
+// Retunes 返回可调谐接收机切换信道的次数，未使用可调谐接收机时返回 0。
+func (l *Listener) Retunes() uint64 {
+	if l.tuner == nil {
+		return 0
+	}
+	return l.tuner.retunes.Load()
+}
+
+// useTuner 让监听者改用单部可调谐接收机，须在注册到信道前调用。
+// onOffChannel 在监听者因未调谐到报文所在信道或正在切换信道而错过报文时调用，可以为 nil。
+func (l *Listener) useTuner(onOffChannel func(msg ACARSMessageInterface, settling bool)) {
+	l.tuner = &tuner{}
+	l.onOffChannel = onOffChannel
+}
+
+// tunedTo 判断监听者此刻能否收到 ch 上的报文，收不到时记录错过的报文。
+func (l *Listener) tunedTo(ch *Channel, msg ACARSMessageInterface) bool {
+	if l.tuner == nil {
+		return true
+	}
+	heard, settling := l.tuner.hears(ch)
+	if !heard && l.onOffChannel != nil {
+		l.onOffChannel(msg, settling)
+	}
+	return heard
+}
+
+// retune 在监听者使用可调谐接收机时将其调谐到 ch。
+func (l *Listener) retune(ch *Channel) {
+	if l.tuner != nil && l.tuner.retune(ch) {
+		slog.Debug("📻 接收机切换信道", "owner", l.OwnerID, "channel", ch.ID, "settle", config.ReceiverRetuneTime)
+	}
+}
+
+// resetRetunes 清零切换信道次数。
+func (l *Listener) resetRetunes() {
+	if l.tuner != nil {
+		l.tuner.retunes.Store(0)
+	}
+}
@@ -0,0 +1,92 @@
+package simulation
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// TransmitAttempt 描述一次进入 Channel.AttemptTransmit 的传输尝试，供 Scheduler 排序。
+type TransmitAttempt struct {
+	SenderID string
+	Message  ACARSMessageInterface
+	Arrival  int // 在本批次中到达的先后顺序，从 0 开始
+}
+
+// Scheduler 决定并发到达同一信道的传输尝试以何种顺序进入信道。
+// 它只用于需要可复现争用顺序的测试与实验：Channel.Scheduler 为 nil (默认) 时各尝试按真实并发执行。
+type Scheduler interface {
+	// Admit 阻塞到轮到该尝试为止，返回的 done 须在该尝试完成 (获得信道或被拒绝) 后调用，以放行下一个尝试。
+	Admit(attempt TransmitAttempt) (done func())
+}
+
+// OrderedScheduler 收集在 Window 内先后到达的传输尝试，然后按 Less 的顺序逐个放行，
+// 从而将“同时”发生的争用变为确定的先后顺序，例如让测试可靠地断言飞机 A 先于 B 获得信道，
+// 或复现某个特定的碰撞序列。Less 为 nil 时按到达顺序放行。
+type OrderedScheduler struct {
+	Window time.Duration
+	Less   func(a, b TransmitAttempt) bool
+
+	mutex   sync.Mutex
+	batch   []*scheduledAttempt // 当前批次中尚未放行的尝试
+	running bool                // 是否有批次正在放行
+}
+
+// scheduledAttempt 是批次中等待放行的一个尝试。
+type scheduledAttempt struct {
+	attempt TransmitAttempt
+	turn    chan struct{} // 轮到该尝试时关闭
+	done    chan struct{} // 该尝试完成时关闭
+}
+
+// Admit 实现 Scheduler。
+func (s *OrderedScheduler) Admit(attempt TransmitAttempt) func() {
+	s.mutex.Lock()
+	attempt.Arrival = len(s.batch)
+	entry := &scheduledAttempt{attempt: attempt, turn: make(chan struct{}), done: make(chan struct{})}
+	s.batch = append(s.batch, entry)
+	if !s.running {
+		s.running = true
+		time.AfterFunc(Scaled(s.Window), s.release)
+	}
+	s.mutex.Unlock()
+
+	<-entry.turn
+	var once sync.Once
+	return func() { once.Do(func() { close(entry.done) }) }
+}
+
+// release 按 Less 的顺序逐个放行当前批次，每个尝试完成后再放行下一个；放行期间到达的尝试组成下一批次。
+func (s *OrderedScheduler) release() {
+	s.mutex.Lock()
+	batch := s.batch
+	s.batch = nil
+	s.mutex.Unlock()
+
+	less := s.Less
+	if less == nil {
+		less = func(a, b TransmitAttempt) bool { return a.Arrival < b.Arrival }
+	}
+	slices.SortStableFunc(batch, func(a, b *scheduledAttempt) int {
+		switch {
+		case less(a.attempt, b.attempt):
+			return -1
+		case less(b.attempt, a.attempt):
+			return 1
+		default:
+			return 0
+		}
+	})
+	for _, entry := range batch {
+		close(entry.turn)
+		<-entry.done
+	}
+
+	s.mutex.Lock()
+	if len(s.batch) > 0 {
+		time.AfterFunc(Scaled(s.Window), s.release)
+	} else {
+		s.running = false
+	}
+	s.mutex.Unlock()
+}
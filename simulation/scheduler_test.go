@@ -0,0 +1,88 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingScheduler 包装另一个 Scheduler，记录各尝试实际进入信道的顺序。
+type recordingScheduler struct {
+	inner Scheduler
+	mutex sync.Mutex
+	order []string
+}
+
+// Admit 实现 Scheduler。
+func (s *recordingScheduler) Admit(attempt TransmitAttempt) func() {
+	done := s.inner.Admit(attempt)
+	s.mutex.Lock()
+	s.order = append(s.order, attempt.SenderID)
+	s.mutex.Unlock()
+	return done
+}
+
+// 容量为 1 的信道上两个发送方同时争用：OrderedScheduler 按 Less 而非到达顺序放行，
+// 先放行的获得信道，后放行的被拒绝；碰撞窗口内的后到者使先到者的报文损坏。多次运行结果相同。
+func TestOrderedSchedulerDecidesContention(t *testing.T) {
+	withTransmissionTimes(t, map[MessageType]time.Duration{MsgTypePosition: 100 * time.Millisecond})
+	a, b := newTestAircraft("A00001", "CCA101"), newTestAircraft("A00002", "CCA102")
+	msgA := newTestMessage(t, a, "CCA101-POS-1", MsgTypePosition, config.HighPriority, a.GetPosition())
+	msgB := newTestMessage(t, b, "CCA102-POS-1", MsgTypePosition, config.HighPriority, b.GetPosition())
+	// 后到的 CCA102 优先放行
+	bFirst := func(x, y TransmitAttempt) bool { return x.SenderID > y.SenderID }
+
+	for _, tc := range []struct {
+		name            string
+		collisionWindow time.Duration
+		wantDelivered   uint64
+		wantCollisions  uint64
+	}{
+		{name: "busy", wantDelivered: 1},
+		{name: "collision", collisionWindow: time.Second, wantCollisions: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for run := 0; run < 5; run++ {
+				recorder := &recordingScheduler{inner: &OrderedScheduler{Window: 30 * time.Millisecond, Less: bFirst}}
+				ch := newTestChannel("Primary")
+				ch.Scheduler = recorder
+				ch.CollisionWindow = tc.collisionWindow
+				ch.StartDispatching()
+
+				var wg sync.WaitGroup
+				granted := make(map[string]bool)
+				var grantedMutex sync.Mutex
+				for i, attempt := range []struct {
+					msg    ACARSMessageInterface
+					sender string
+				}{{msgA, a.CurrentFlightID}, {msgB, b.CurrentFlightID}} {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						time.Sleep(time.Duration(i) * 5 * time.Millisecond)
+						ok := ch.AttemptTransmit(attempt.msg, attempt.sender)
+						grantedMutex.Lock()
+						granted[attempt.sender] = ok
+						grantedMutex.Unlock()
+					}()
+				}
+				wg.Wait()
+				waitFor(t, 2*time.Second, "传输结束", func() bool { return ch.Occupancy() == 0 })
+
+				if want := []string{"CCA102", "CCA101"}; !slices.Equal(recorder.order, want) {
+					t.Fatalf("第 %d 次: 放行顺序 = %v，期望 %v", run, recorder.order, want)
+				}
+				if !granted["CCA102"] || granted["CCA101"] {
+					t.Fatalf("第 %d 次: 获得信道 = %v，期望只有 CCA102", run, granted)
+				}
+				stats := ch.GetRawStats()
+				if stats.TotalMessagesTransmitted != tc.wantDelivered || stats.OverlapCollisions != tc.wantCollisions {
+					t.Fatalf("第 %d 次: 送达 %d 个、碰撞 %d 次，期望送达 %d 个、碰撞 %d 次",
+						run, stats.TotalMessagesTransmitted, stats.OverlapCollisions, tc.wantDelivered, tc.wantCollisions)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package simulation
+
+import (
+	"log/slog"
+	"math"
+	"sync/atomic"
+)
+
+// Sector 是空域中的一个扇区，拥有独立的主/备信道以及负责该扇区的地面站。
+// 扇区按距离划分：飞机属于地面站距其最近的扇区，并使用该扇区的信道发送报文。
+type Sector struct {
+	ID             string
+	Station        *GroundControlCenter
+	PrimaryChannel *Channel
+	BackupChannel  *Channel // 在单信道模式下，此字段为 nil
+
+	aircraft atomic.Int64  // 当前位于本扇区的飞机数
+	entries  atomic.Uint64 // 飞机进入本扇区的次数 (包括首次分配扇区)
+}
+
+// NewSector 创建一个由 station 负责、使用 primary/backup 信道的扇区，扇区ID与地面站ID相同。
+func NewSector(station *GroundControlCenter, primary, backup *Channel) *Sector {
+	return &Sector{ID: station.ID, Station: station, PrimaryChannel: primary, BackupChannel: backup}
+}
+
+// Channels 返回扇区拥有的所有已启用信道。
+func (s *Sector) Channels() []*Channel {
+	if s.BackupChannel == nil {
+		return []*Channel{s.PrimaryChannel}
+	}
+	return []*Channel{s.PrimaryChannel, s.BackupChannel}
+}
+
+// distanceKM 返回给定位置到扇区地面站的地面距离。
+func (s *Sector) distanceKM(pos PositionReportData) float64 {
+	coverage := s.Station.Coverage
+	return haversineKM(coverage.CenterLatitude, coverage.CenterLongitude, pos.Latitude, pos.Longitude)
+}
+
+// SectorRawStats 定义了用于数据收集的扇区原始统计数据。
+type SectorRawStats struct {
+	Aircraft int64  // 当前位于本扇区的飞机数
+	Entries  uint64 // 飞机进入本扇区的次数
+}
+
+// GetRawStats 返回原始统计数据，用于写入报告。
+func (s *Sector) GetRawStats() SectorRawStats {
+	return SectorRawStats{Aircraft: s.aircraft.Load(), Entries: s.entries.Load()}
+}
+
+// sectorAt 返回给定位置所属的扇区，未划分扇区时返回 nil。
+func (cs *CommunicationSystem) sectorAt(pos PositionReportData) *Sector {
+	var nearest *Sector
+	nearestKM := math.Inf(1)
+	for _, sector := range cs.sectors {
+		if d := sector.distanceKM(pos); d < nearestKM {
+			nearest, nearestKM = sector, d
+		}
+	}
+	return nearest
+}
+
+// moveSender 将发送方分配到 pos 所属的扇区，返回之前与现在所在的扇区。
+func (cs *CommunicationSystem) moveSender(senderID string, pos PositionReportData) (from, to *Sector) {
+	to = cs.sectorAt(pos)
+	if to == nil {
+		return nil, nil
+	}
+	if prev, loaded := cs.senderSectors.Swap(senderID, to); loaded {
+		from = prev.(*Sector)
+	}
+	if from != to {
+		if from != nil {
+			from.aircraft.Add(-1)
+		}
+		to.aircraft.Add(1)
+		to.entries.Add(1)
+	}
+	return from, to
+}
+
+// updateSector 根据飞机此刻的位置更新其所在扇区；跨越扇区后，飞机此后的报文改用新扇区的信道。
+func (a *Aircraft) updateSector(comms *CommunicationSystem) {
+	from, to := comms.moveSender(a.CurrentFlightID, a.GetPosition())
+	switch {
+	case to == nil || from == to:
+	case from == nil:
+		slog.Debug("🗺️  飞机进入扇区", "flight", a.CurrentFlightID, "sector", to.ID, "channel", to.PrimaryChannel.ID)
+	default:
+		atomic.AddUint64(&a.sectorCrossings, 1)
+		slog.Info("🗺️  飞机跨越扇区，切换信道", "flight", a.CurrentFlightID, "from", from.ID, "to", to.ID, "channel", to.PrimaryChannel.ID)
+	}
+}
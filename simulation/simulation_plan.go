@@ -3,7 +3,9 @@ package simulation
 import (
 	"Air-Simulator/config"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
+	"math/rand/v2"
 	"sync"
 	"time"
 )
@@ -11,12 +13,32 @@ import (
 // FlightPlan 结构体 (无变化)
 type FlightPlan struct {
 	Aircraft         *Aircraft
-	StartTimeMinutes int    // 从模拟开始计算的起飞/进入空域时间 (分钟)
-	Type             string // "Departing" (离港) 或 "Arriving" (进港)
+	StartTimeMinutes int     // 从模拟开始计算的起飞/进入空域时间 (分钟)
+	Type             string  // "Departing" (离港) 或 "Arriving" (进港)
+	HeadingDeg       float64 // 离港航向 / 进港来向 (度)，决定飞机穿越哪些地面站的覆盖区
+
+	StartOffset time.Duration // 精确的开始时间，非零时优先于 StartTimeMinutes
+	Duration    time.Duration // 在空域内的飞行时长，为 0 时使用 config.FlightDuration
+}
+
+// startDelay 返回飞行计划相对模拟开始的启动时间。
+func (p FlightPlan) startDelay() time.Duration {
+	if p.StartOffset > 0 {
+		return p.StartOffset
+	}
+	return time.Duration(p.StartTimeMinutes) * time.Minute
+}
+
+// flightDuration 返回飞机在空域内的飞行时长。
+func (p FlightPlan) flightDuration() time.Duration {
+	if p.Duration > 0 {
+		return p.Duration
+	}
+	return config.FlightDuration
 }
 
-// flightPlans 变量 (无变化)
-var flightPlans = []FlightPlan{
+// scriptedFlightPlans 是脚本化的默认飞行计划
+var scriptedFlightPlans = []FlightPlan{
 	// 20架飞机的飞行计划
 	{Type: "Departing", StartTimeMinutes: 1},
 	//{Type: "Departing", StartTimeMinutes: 3},
@@ -42,44 +64,174 @@ var flightPlans = []FlightPlan{
 	//{Type: "Arriving", StartTimeMinutes: 26},
 	{Type: "Arriving", StartTimeMinutes: 27},
 }
-var AircraftCount = len(flightPlans)
 
-// RunSimulationSession 更新为接收 CommunicationSystem
-func RunSimulationSession(wg *sync.WaitGroup, commsSystem *CommunicationSystem, aircraftList []*Aircraft) {
-	// 为飞行计划分配飞机实例
-	for i := range flightPlans {
-		flightPlans[i].Aircraft = aircraftList[i]
+// SelectFlightPlans 根据配置选择本次模拟使用的飞行计划：脚本化的飞行计划、随机生成的飞行计划或泊松到达的交通流。
+// 飞机数量由返回的计划数量决定。启用 ScaleFleetWithLoad 时，飞机数量 (泊松模型中为到达率) 按 LoadMultiplier 缩放。
+// 返回的切片归调用方所有，修改它不影响脚本化的默认计划。
+func SelectFlightPlans() ([]FlightPlan, error) {
+	fleetScale := 1.0
+	if config.ScaleFleetWithLoad && config.LoadMultiplier > 0 {
+		fleetScale = config.LoadMultiplier
+	}
+
+	if config.TrafficModel == TrafficModelPoisson {
+		source := PoissonTrafficSource{
+			RatePerMinute: config.PoissonArrivalRatePerMinute * fleetScale,
+			Window:        config.PoissonArrivalWindow,
+			MinDuration:   config.PoissonFlightDurationMin,
+			MaxDuration:   config.PoissonFlightDurationMax,
+			Seed:          config.FlightPlanSeed,
+		}
+		return source.Generate(), nil
+	}
+
+	n := config.NumAircraft
+	if n <= 0 {
+		n = len(scriptedFlightPlans)
+	}
+	if fleetScale != 1 {
+		return GenerateFlightPlans(max(int(math.Round(float64(n)*fleetScale)), 1), config.FlightPlanSeed)
+	}
+	if config.NumAircraft > 0 {
+		return GenerateFlightPlans(config.NumAircraft, config.FlightPlanSeed)
+	}
+	return append([]FlightPlan(nil), scriptedFlightPlans...), nil
+}
+
+// scaleInterval 按 LoadMultiplier 缩放报告间隔，倍数越大间隔越短、流量越大。
+func scaleInterval(d time.Duration) time.Duration {
+	return scaleIntervalBy(d, config.LoadMultiplier)
+}
+
+// scaleIntervalBy 将间隔除以 multiplier，multiplier <= 0 时不缩放。
+func scaleIntervalBy(d time.Duration, multiplier float64) time.Duration {
+	if multiplier <= 0 {
+		return d
 	}
+	return time.Duration(float64(d) / multiplier)
+}
+
+// reportJitter 返回经 LoadMultiplier 缩放的例行报告间隔抖动。抖动与间隔按同一倍数缩放，
+// 否则高负载时抖动超过间隔本身，next 的下限截断会使平均间隔偏离 interval。
+func reportJitter() time.Duration {
+	return scaleInterval(config.ReportIntervalJitter)
+}
+
+// GenerateFlightPlans 随机生成 n 个飞行计划：离港与进港各占约一半，
+// 开始时间均匀分布在 [1, FlightPlanWindowMinutes] 分钟内。相同的 seed 总是生成相同的计划。
+// n 为负数或 FlightPlanWindowMinutes 不为正时返回错误。
+func GenerateFlightPlans(n int, seed int64) ([]FlightPlan, error) {
+	return generateFlightPlans(n, seed, config.FlightPlanWindowMinutes)
+}
 
-	// 为每个飞行计划启动一个独立的模拟 goroutine
-	for i := range flightPlans {
+// generateFlightPlans 实现 GenerateFlightPlans，开始时间分布在 [1, windowMinutes] 分钟内。
+func generateFlightPlans(n int, seed int64, windowMinutes int) ([]FlightPlan, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("飞机数量不能为负数: %d", n)
+	}
+	if windowMinutes <= 0 {
+		return nil, fmt.Errorf("飞行计划的开始时间范围必须为正: %d 分钟", windowMinutes)
+	}
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(n)))
+	plans := make([]FlightPlan, n)
+	for i := range plans {
+		planType := "Departing"
+		if rng.IntN(2) == 1 {
+			planType = "Arriving"
+		}
+		plans[i] = FlightPlan{
+			Type:             planType,
+			StartTimeMinutes: 1 + rng.IntN(windowMinutes),
+		}
+	}
+	return plans, nil
+}
+
+// RunSimulationSession 为 aircraftList 中的每架飞机分配 plans 中的一个飞行计划并启动模拟，返回实际执行的飞行计划。
+// 飞机数量与飞行计划数量不一致时，会按实际飞机数量重新生成飞行计划。plans 本身不会被修改。
+// airspace 对同时活动的航班数进行准入控制，traffic 为每个航班创建决定例行报告的流量生成器。
+// stop 被关闭时尚未完成的飞行计划提前结束。
+func RunSimulationSession(wg *sync.WaitGroup, commsSystem *CommunicationSystem, plans []FlightPlan, aircraftList []*Aircraft, airspace *Airspace, traffic TrafficGeneratorFactory, stop <-chan struct{}) ([]FlightPlan, error) {
+	session, err := assignFlightPlans(plans, aircraftList)
+	if err != nil {
+		return nil, err
+	}
+
+	// 为每个飞行计划启动一个独立的模拟 goroutine，所有航班共用同一个故障风暴触发通道
+	storm := newFaultStorm()
+	for _, plan := range session {
 		wg.Add(1)
-		plan := flightPlans[i]
 		// 传递 commsSystem
-		go simulateFlight(plan, wg, commsSystem)
+		go simulateFlight(plan, wg, commsSystem, airspace, traffic, storm, stop)
 	}
+	return session, nil
+}
+
+// assignFlightPlans 返回为每架飞机分配了飞行计划的副本，并将航向均匀分布在各个方向上。
+// 数量不一致时按飞机数量重新生成飞行计划。
+func assignFlightPlans(plans []FlightPlan, aircraftList []*Aircraft) ([]FlightPlan, error) {
+	if len(aircraftList) != len(plans) {
+		slog.Warn("⚠️  飞机数量与飞行计划数量不一致，将重新生成飞行计划", "aircraft", len(aircraftList), "plans", len(plans))
+		var err error
+		if plans, err = GenerateFlightPlans(len(aircraftList), config.FlightPlanSeed); err != nil {
+			return nil, err
+		}
+	}
+
+	session := make([]FlightPlan, len(plans))
+	startJitter := rand.New(rand.NewPCG(uint64(config.FlightPlanSeed), 0x5354415254)) // 开始时间抖动
+	for i, plan := range plans {
+		plan.Aircraft = aircraftList[i]
+		plan.HeadingDeg = float64(i) * 360 / float64(len(plans))
+		if jitter := config.StartTimeJitter; jitter > 0 {
+			start := plan.startDelay() + time.Duration(startJitter.Int64N(int64(2*jitter)+1)) - jitter
+			plan.StartOffset = max(start, time.Nanosecond) // 保证非零，使抖动后的开始时间生效
+		}
+		session[i] = plan
+	}
+	return session, nil
 }
 
 // simulateFlight 更新为接收 CommunicationSystem
-func simulateFlight(plan FlightPlan, wg *sync.WaitGroup, commsSystem *CommunicationSystem) {
+// storm 被关闭时处于巡航阶段的航班按概率加入故障风暴。
+func simulateFlight(plan FlightPlan, wg *sync.WaitGroup, commsSystem *CommunicationSystem, airspace *Airspace, traffic TrafficGeneratorFactory, storm <-chan struct{}, stop <-chan struct{}) {
 	defer wg.Done()
 
 	// 1. 等待至预定的飞行计划开始时间
-	startTime := time.Duration(plan.StartTimeMinutes) * time.Minute
-	time.Sleep(startTime)
-	log.Printf("🛫 [飞机 %s] 飞行计划启动。类型: %s, 计划开始于 %d 分钟", plan.Aircraft.CurrentFlightID, plan.Type, plan.StartTimeMinutes)
+	startTime := plan.startDelay()
+	if !sleepUntilStopped(startTime, stop) {
+		return
+	}
+
+	// 申请进入空域：满容量时等待，进港航班也可能按策略备降
+	if !airspace.admit(plan.Aircraft.CurrentFlightID, plan.Type == "Arriving") {
+		return
+	}
+	defer airspace.release()
+	slog.Info("🛫 飞行计划启动", "flight", plan.Aircraft.CurrentFlightID, "type", plan.Type, "start", startTime, "duration", plan.flightDuration())
 
 	// 2. 根据飞行计划类型执行不同的通信逻辑
+	cruiseSpeedKMPH := config.CruiseSpeedKnots * knotsToKMPH
+	linkTestInterval, engineReportInterval := scaleInterval(config.LinkTestInterval), scaleInterval(1*time.Minute)
+	rng := newAircraftRand(plan.Aircraft.ICAOAddress) // 例行报告的随机抖动与分布
 	if plan.Type == "Departing" {
 		// 离港飞机流程
+		plan.Aircraft.setTrack(stationaryTrack(config.AirportLatitude, config.AirportLongitude))
 		sendOOOIMessage(plan.Aircraft, "OUT", time.Now(), commsSystem) // 推出
-		time.Sleep(config.TaxiTime)                                    // 滑行
+		if !sleepUntilStopped(config.TaxiTime, stop) {                 // 滑行
+			return
+		}
 		sendOOOIMessage(plan.Aircraft, "OFF", time.Now(), commsSystem) // 起飞
+		plan.Aircraft.setTrack(flightTrack{
+			originLat: config.AirportLatitude, originLon: config.AirportLongitude,
+			headingDeg: plan.HeadingDeg, speedKMPH: cruiseSpeedKMPH,
+			altitudeFt: config.CruiseAltitudeFt, t0: time.Now(),
+		})
 
 		// --- 起飞后5分钟，每分钟发送引擎报告 ---
-		log.Printf("✈️  [飞机 %s] 进入起飞后初始爬升阶段，将持续报告引擎状况...", plan.Aircraft.CurrentFlightID)
-		engineReportTicker := time.NewTicker(1 * time.Minute)
-		engineReportTimer := time.NewTimer(5 * time.Minute)
+		slog.Info("✈️  进入起飞后初始爬升阶段，将持续报告引擎状况", "flight", plan.Aircraft.CurrentFlightID)
+		engineReportTicker := time.NewTicker(Scaled(engineReportInterval))
+		engineReportTimer := time.NewTimer(Scaled(5 * time.Minute))
 	initialClimbLoop:
 		for {
 			select {
@@ -88,71 +240,98 @@ func simulateFlight(plan FlightPlan, wg *sync.WaitGroup, commsSystem *Communicat
 			case <-engineReportTimer.C:
 				engineReportTicker.Stop()
 				break initialClimbLoop
+			case <-stop:
+				engineReportTicker.Stop()
+				return
 			}
 		}
-		log.Printf("✈️  [飞机 %s] 初始爬升阶段结束，进入巡航。", plan.Aircraft.CurrentFlightID)
+		slog.Info("✈️  初始爬升阶段结束，进入巡航", "flight", plan.Aircraft.CurrentFlightID)
 
 		// --- 模拟30分钟的离港飞行，包含多种报告 ---
-		posTicker := time.NewTicker(config.PosReportInterval)
-		defer posTicker.Stop()
-		fuelTicker := time.NewTicker(config.FuelReportInterval)
-		defer fuelTicker.Stop()
-		weatherTicker := time.NewTicker(config.WeatherReportInterval)
-		defer weatherTicker.Stop()
-		flightTimer := time.NewTimer(config.FlightDuration)
+		generator := traffic(plan.Aircraft, rng)
+		report, wait := generator.Next(plan.Aircraft, PhaseDepartureCruise)
+		reportTimer := time.NewTimer(Scaled(wait))
+		defer reportTimer.Stop()
+		linkTestTicker := newJitteredTicker(linkTestInterval, reportJitter(), rng)
+		defer linkTestTicker.Stop()
+		flightTimer := time.NewTimer(Scaled(plan.flightDuration()))
 		defer flightTimer.Stop()
+		faultTimer := newFaultTimer(rng, plan.flightDuration())
 
 	flightLoopDepart:
 		for {
 			select {
-			case <-posTicker.C:
-				sendPositionReport(plan.Aircraft, commsSystem)
-			case <-fuelTicker.C:
-				sendFuelReport(plan.Aircraft, commsSystem)
-			case <-weatherTicker.C:
-				sendWeatherReport(plan.Aircraft, commsSystem)
+			case <-reportTimer.C:
+				sendRoutineReport(plan.Aircraft, report, commsSystem)
+				report, wait = generator.Next(plan.Aircraft, PhaseDepartureCruise)
+				reportTimer.Reset(Scaled(wait))
+			case <-linkTestTicker.C:
+				linkTestTicker.Reset()
+				sendLinkTest(plan.Aircraft, commsSystem)
+			case <-faultTimer:
+				sendFaultReport(plan.Aircraft, randomFault(rng), commsSystem)
+			case <-storm:
+				storm = nil // 每个航班只参与一次
+				joinFaultStorm(plan.Aircraft, rng, commsSystem)
 			case <-flightTimer.C:
 				break flightLoopDepart
+			case <-stop:
+				return
 			}
 		}
 
-		log.Printf("✈️  [飞机 %s] 已飞出空域。飞行计划结束。", plan.Aircraft.CurrentFlightID)
+		slog.Info("✈️  已飞出空域，飞行计划结束", "flight", plan.Aircraft.CurrentFlightID)
 
 	} else { // Arriving
 		// 进港飞机流程
+		plan.Aircraft.setTrack(flightTrack{
+			originLat: config.AirportLatitude, originLon: config.AirportLongitude,
+			headingDeg: plan.HeadingDeg, startDistanceKM: config.ArrivalEntryDistanceKM,
+			speedKMPH: -cruiseSpeedKMPH, altitudeFt: config.CruiseAltitudeFt, t0: time.Now(),
+		})
 		sendPositionReport(plan.Aircraft, commsSystem) // 进入空域时首先报告位置
 
 		// --- 模拟30分钟的进港飞行，包含多种报告 ---
-		posTicker := time.NewTicker(config.PosReportInterval)
-		defer posTicker.Stop()
-		fuelTicker := time.NewTicker(config.FuelReportInterval)
-		defer fuelTicker.Stop()
-		weatherTicker := time.NewTicker(config.WeatherReportInterval)
-		defer weatherTicker.Stop()
-		flightTimer := time.NewTimer(config.FlightDuration)
+		generator := traffic(plan.Aircraft, rng)
+		report, wait := generator.Next(plan.Aircraft, PhaseArrival)
+		reportTimer := time.NewTimer(Scaled(wait))
+		defer reportTimer.Stop()
+		linkTestTicker := newJitteredTicker(linkTestInterval, reportJitter(), rng)
+		defer linkTestTicker.Stop()
+		flightTimer := time.NewTimer(Scaled(plan.flightDuration()))
 		defer flightTimer.Stop()
+		faultTimer := newFaultTimer(rng, plan.flightDuration())
 
 	flightLoopArrive:
 		for {
 			select {
-			case <-posTicker.C:
-				sendPositionReport(plan.Aircraft, commsSystem)
-			case <-fuelTicker.C:
-				sendFuelReport(plan.Aircraft, commsSystem)
-			case <-weatherTicker.C:
-				sendWeatherReport(plan.Aircraft, commsSystem)
+			case <-reportTimer.C:
+				sendRoutineReport(plan.Aircraft, report, commsSystem)
+				report, wait = generator.Next(plan.Aircraft, PhaseArrival)
+				reportTimer.Reset(Scaled(wait))
+			case <-linkTestTicker.C:
+				linkTestTicker.Reset()
+				sendLinkTest(plan.Aircraft, commsSystem)
+			case <-faultTimer:
+				sendFaultReport(plan.Aircraft, randomFault(rng), commsSystem)
+			case <-storm:
+				storm = nil // 每个航班只参与一次
+				joinFaultStorm(plan.Aircraft, rng, commsSystem)
 			case <-flightTimer.C:
 				break flightLoopArrive
+			case <-stop:
+				return
 			}
 		}
 
 		onTime := time.Now()
+		plan.Aircraft.setTrack(stationaryTrack(config.AirportLatitude, config.AirportLongitude))
 		sendOOOIMessage(plan.Aircraft, "ON", onTime, commsSystem) // 降落
 
 		// --- 降落后5分钟，每分钟发送引擎报告 ---
-		log.Printf("🛬 [飞机 %s] 完成降落，将持续报告引擎反推及冷却状况...", plan.Aircraft.CurrentFlightID)
-		engineReportTicker := time.NewTicker(1 * time.Minute)
-		engineReportTimer := time.NewTimer(5 * time.Minute)
+		slog.Info("🛬 完成降落，将持续报告引擎反推及冷却状况", "flight", plan.Aircraft.CurrentFlightID)
+		engineReportTicker := time.NewTicker(Scaled(engineReportInterval))
+		engineReportTimer := time.NewTimer(Scaled(5 * time.Minute))
 	landingRollLoop:
 		for {
 			select {
@@ -161,50 +340,68 @@ func simulateFlight(plan FlightPlan, wg *sync.WaitGroup, commsSystem *Communicat
 			case <-engineReportTimer.C:
 				engineReportTicker.Stop()
 				break landingRollLoop
+			case <-stop:
+				engineReportTicker.Stop()
+				return
 			}
 		}
 
-		time.Sleep(config.TaxiTime)                               // 滑行至停机位
+		if !sleepUntilStopped(config.TaxiTime, stop) { // 滑行至停机位
+			return
+		}
 		sendOOOIMessage(plan.Aircraft, "IN", onTime, commsSystem) // 到达
 
-		log.Printf("🛬 [飞机 %s] 已成功降落并抵达停机位。飞行计划结束。", plan.Aircraft.CurrentFlightID)
+		slog.Info("🛬 已成功降落并抵达停机位，飞行计划结束", "flight", plan.Aircraft.CurrentFlightID)
 	}
 }
 
-// sendEngineReport 更新为接收 CommunicationSystem
+// sendEngineReport 为飞机的每台发动机各发送一份引擎报告
 func sendEngineReport(a *Aircraft, commsSystem *CommunicationSystem) {
-	log.Printf("📡 [飞机 %s] 准备发送引擎报告...", a.CurrentFlightID)
-	engineData := EngineReportData{
-		EngineID: 1, N1RPM: 85.5, EGT: 450, FuelFlow: 1200, OilPressure: 75,
-		FlightPhase: "CLIMB", ReportTimeUTC: time.Now().UTC(),
+	engineCount := a.engineCount()
+	slog.Debug("📡 准备发送引擎报告", "flight", a.CurrentFlightID, "engines", engineCount)
+	fuelFlowPerEngine := 1200.0
+	if a.FuelConsumptionRateKGPH > 0 {
+		fuelFlowPerEngine = a.FuelConsumptionRateKGPH / float64(engineCount)
 	}
-	baseMsg := ACARSBaseMessage{
-		AircraftICAOAddress: a.ICAOAddress, FlightID: a.CurrentFlightID,
-		MessageID: fmt.Sprintf("%s-ENG-%d", a.CurrentFlightID, time.Now().Unix()),
-		Type:      MsgTypeEngineReport,
+	for engineID := 1; engineID <= engineCount; engineID++ {
+		engineData := EngineReportData{
+			EngineID: engineID, N1RPM: 85.5, EGT: 450, FuelFlow: fuelFlowPerEngine, OilPressure: 75,
+			FlightPhase: "CLIMB", ReportTimeUTC: time.Now().UTC(),
+		}
+		baseMsg := ACARSBaseMessage{
+			AircraftICAOAddress: a.ICAOAddress, FlightID: a.CurrentFlightID,
+			MessageID: fmt.Sprintf("%s-ENG%d-%d", a.CurrentFlightID, engineID, time.Now().Unix()),
+			Type:      MsgTypeEngineReport,
+		}
+		sendReport(a, baseMsg, config.MediumPriority, engineData, commsSystem)
 	}
-	msg, _ := NewMediumLowPriorityMessage(baseMsg, engineData)
-	go a.SendMessage(msg, commsSystem)
 }
 
 // sendFuelReport 更新为接收 CommunicationSystem
 func sendFuelReport(a *Aircraft, commsSystem *CommunicationSystem) {
-	log.Printf("📡 [飞机 %s] 准备发送燃油报告...", a.CurrentFlightID)
+	slog.Debug("📡 准备发送燃油报告", "flight", a.CurrentFlightID)
 	fuelData := FuelReportData{
 		RemainingFuelKG: 12000.0, FuelFlowKGPH: 200.0, EstimatedTime: time.Now(),
 	}
+	if a.Profile.Name != "" {
+		fuelData.RemainingFuelKG = a.FuelRemainingKG
+		fuelData.FuelFlowKGPH = a.FuelConsumptionRateKGPH
+	}
 	baseMsg := ACARSBaseMessage{
 		AircraftICAOAddress: a.ICAOAddress, FlightID: a.CurrentFlightID,
 		MessageID: fmt.Sprintf("%s-FUEL-%d", a.CurrentFlightID, time.Now().Unix()),
 		Type:      MsgTypeFuel,
 	}
-	msg, _ := NewHighMediumPriorityMessage(baseMsg, fuelData)
-	go a.SendMessage(msg, commsSystem)
+	sendReport(a, baseMsg, config.HighPriority, fuelData, commsSystem)
 }
 
 // sendWeatherReport 更新为接收 CommunicationSystem
 func sendWeatherReport(a *Aircraft, commsSystem *CommunicationSystem) {
-	log.Printf("📡 [飞机 %s] 准备发送气象报告...", a.CurrentFlightID)
+	if config.WeatherRequests {
+		sendWeatherRequest(a, commsSystem)
+		return
+	}
+	slog.Debug("📡 准备发送气象报告", "flight", a.CurrentFlightID)
 	type WeatherReportData struct {
 		TemperatureC  float64
 		WindSpeedKPH  float64
@@ -219,26 +416,36 @@ func sendWeatherReport(a *Aircraft, commsSystem *CommunicationSystem) {
 		MessageID: fmt.Sprintf("%s-WX-%d", a.CurrentFlightID, time.Now().Unix()),
 		Type:      MsgTypeWeather,
 	}
-	msg, _ := NewMediumLowPriorityMessage(baseMsg, weatherData)
-	go a.SendMessage(msg, commsSystem)
+	sendReport(a, baseMsg, config.MediumPriority, weatherData, commsSystem)
 }
 
 // sendPositionReport 更新为接收 CommunicationSystem
 func sendPositionReport(a *Aircraft, commsSystem *CommunicationSystem) {
-	log.Printf("📡 [飞机 %s] 准备发送例行位置报告...", a.CurrentFlightID)
-	posData := PositionReportData{Latitude: 39.9, Longitude: 116.3, Altitude: 35000}
+	slog.Debug("📡 准备发送例行位置报告", "flight", a.CurrentFlightID)
+	posData := a.GetPosition()
+	a.recordPositionReport(posData)
 	baseMsg := ACARSBaseMessage{
 		AircraftICAOAddress: a.ICAOAddress, FlightID: a.CurrentFlightID,
 		MessageID: fmt.Sprintf("%s-POS-%d", a.CurrentFlightID, time.Now().Unix()),
 		Type:      MsgTypePosition,
 	}
-	msg, _ := NewHighMediumPriorityMessage(baseMsg, posData)
-	go a.SendMessage(msg, commsSystem)
+	sendReport(a, baseMsg, config.HighPriority, posData, commsSystem)
+}
+
+// sendLinkTest 发起一次 ACARS 链路测试，地面站的回复用于测量端到端往返时间
+func sendLinkTest(a *Aircraft, commsSystem *CommunicationSystem) {
+	slog.Debug("📡 准备发起链路测试", "flight", a.CurrentFlightID)
+	baseMsg := ACARSBaseMessage{
+		AircraftICAOAddress: a.ICAOAddress, FlightID: a.CurrentFlightID,
+		MessageID: fmt.Sprintf("%s-LT-%d", a.CurrentFlightID, time.Now().Unix()),
+		Type:      MsgTypeLinkTest,
+	}
+	sendReport(a, baseMsg, config.LowPriority, LinkTestData{Result: "PENDING"}, commsSystem)
 }
 
 // sendOOOIMessage 更新为接收 CommunicationSystem
 func sendOOOIMessage(a *Aircraft, oooiType string, eventTime time.Time, commsSystem *CommunicationSystem) {
-	log.Printf("📡 [飞机 %s] 准备发送 OOOI 报告: %s", a.CurrentFlightID, oooiType)
+	slog.Debug("📡 准备发送 OOOI 报告", "flight", a.CurrentFlightID, "oooi", oooiType)
 	var oooiData OOOIReportData
 	switch oooiType {
 	case "OUT":
@@ -255,6 +462,123 @@ func sendOOOIMessage(a *Aircraft, oooiType string, eventTime time.Time, commsSys
 		MessageID: fmt.Sprintf("%s-%s-%d", a.CurrentFlightID, oooiType, time.Now().Unix()),
 		Type:      MsgTypeOOOI,
 	}
-	msg, _ := NewHighMediumPriorityMessage(baseMsg, oooiData)
-	go a.SendMessage(msg, commsSystem)
+	sendReport(a, baseMsg, config.HighPriority, oooiData, commsSystem)
+}
+
+// sendFaultReport 发送一份故障报告。严重性为 CRITICAL 的报告达到最大重传次数成为死信时，
+// 在 config.FaultEscalationLimit 次以内提升升级级别、以新的报文ID和完整的重传预算重新上报。
+func sendFaultReport(a *Aircraft, fault AircraftFaultData, commsSystem *CommunicationSystem) {
+	slog.Warn("🚨 准备发送故障报告", "flight", a.CurrentFlightID, "fault", fault.FaultCode, "severity", fault.Severity, "escalation", fault.Escalation)
+	baseMsg := ACARSBaseMessage{
+		AircraftICAOAddress: a.ICAOAddress, FlightID: a.CurrentFlightID,
+		MessageID: a.nextFaultMessageID(fault),
+		Type:      MsgTypeAircraftFault,
+	}
+	sendReportNotify(a, baseMsg, config.CriticalPriority, fault, commsSystem, func(reason DeadLetterReason) {
+		if reason != DeadLetterMaxRetriesExceeded || fault.Severity != "CRITICAL" {
+			return
+		}
+		if fault.Escalation >= config.FaultEscalationLimit {
+			slog.Error("❌ CRITICAL 故障报告多次升级后仍未能送达", "flight", a.CurrentFlightID, "msgID", baseMsg.MessageID, "fault", fault.FaultCode)
+			return
+		}
+		fault.Escalation++
+		sendFaultReport(a, fault, commsSystem)
+	})
+}
+
+// nextFaultMessageID 为飞机的下一份故障报告生成报文ID。同一时刻可能发出多份故障报告 (故障风暴、升级重发)，
+// 因此用飞机内递增的序号而不是时间戳区分。
+func (a *Aircraft) nextFaultMessageID(fault AircraftFaultData) string {
+	return fmt.Sprintf("%s-FLT-%d-E%d", a.CurrentFlightID, a.faultSeq.Add(1), fault.Escalation)
+}
+
+// flightFaults 是模拟故障时随机选用的故障。
+var flightFaults = []AircraftFaultData{
+	{FaultCode: "ENG1-OVHT", Description: "ENGINE 1 OVERHEAT", Severity: "CRITICAL", System: "ENGINE_1"},
+	{FaultCode: "HYD-B-LOPR", Description: "HYDRAULIC SYSTEM B LOW PRESSURE", Severity: "CRITICAL", System: "HYDRAULIC"},
+	{FaultCode: "CAB-PRESS", Description: "CABIN ALTITUDE WARNING", Severity: "CRITICAL", System: "PRESSURIZATION"},
+	{FaultCode: "GEN2-OFF", Description: "GENERATOR 2 OFFLINE", Severity: "MAJOR", System: "ELECTRICAL"},
+}
+
+// newFaultTimer 按 config.FaultProbabilityPerFlight 决定本次飞行是否发生故障，
+// 发生时返回在飞行期间随机时刻触发的通道，否则返回 nil (永不触发)。
+func newFaultTimer(rng *rand.Rand, flightDuration time.Duration) <-chan time.Time {
+	if config.FaultProbabilityPerFlight <= 0 || rng.Float64() >= config.FaultProbabilityPerFlight {
+		return nil
+	}
+	return time.After(Scaled(time.Duration(rng.Int64N(int64(flightDuration)))))
+}
+
+// randomFault 随机选用一个故障，发生时间为当前时刻。
+func randomFault(rng *rand.Rand) AircraftFaultData {
+	fault := flightFaults[rng.IntN(len(flightFaults))]
+	fault.Timestamp = time.Now().UTC()
+	return fault
+}
+
+// sendReport 发送飞行过程中生成的一份报告：记录报文轨迹时先写入轨迹，回放报文轨迹时直接丢弃。
+// priority 是该类报告的默认优先级，config.MessageTypePriorities 中的配置优先于它。
+func sendReport(a *Aircraft, baseMsg ACARSBaseMessage, priority config.Priority, data interface{}, commsSystem *CommunicationSystem) {
+	sendReportNotify(a, baseMsg, priority, data, commsSystem, nil)
+}
+
+// sendReportNotify 与 sendReport 相同，并在报告的最终结果确定后调用 onDone (见 transmitReport)。
+func sendReportNotify(a *Aircraft, baseMsg ACARSBaseMessage, priority config.Priority, data interface{}, commsSystem *CommunicationSystem, onDone func(reason DeadLetterReason)) {
+	if replayingTrace {
+		return
+	}
+	priority = priorityFor(baseMsg.Type, priority)
+	if traceRecorder != nil {
+		traceRecorder.record(baseMsg, priority, data)
+	}
+	transmitReport(a, baseMsg, priority, data, commsSystem, onDone)
+}
+
+// priorityFor 返回 config.MessageTypePriorities 为报文类型配置的优先级，未配置时返回 fallback。
+func priorityFor(msgType MessageType, fallback config.Priority) config.Priority {
+	if priority, ok := config.MessageTypePriorities[string(msgType)]; ok {
+		return priority
+	}
+	return fallback
+}
+
+// transmitReport 创建并发送一份报告。数据超出最大报文块长度时自动分片，
+// 每个分片独立竞争信道、独立等待 ACK 与重传，由地面站负责重组。
+// onDone 非 nil 时，在所有分片的结果确定后调用：全部送达时 reason 为空，否则为第一个失败分片的死信原因。
+func transmitReport(a *Aircraft, baseMsg ACARSBaseMessage, priority config.Priority, data interface{}, commsSystem *CommunicationSystem, onDone func(reason DeadLetterReason)) {
+	msgs, err := NewMessage(baseMsg, priority, data)
+	if err != nil {
+		slog.Error("创建报文失败", "flight", a.CurrentFlightID, "msgID", baseMsg.MessageID, "err", err)
+		return
+	}
+	if len(msgs) > 1 {
+		slog.Debug("✂️  报文超出最大报文块长度，拆分为分片发送", "flight", a.CurrentFlightID, "msgID", baseMsg.MessageID, "fragments", len(msgs))
+	}
+	if onDone == nil {
+		for _, msg := range msgs {
+			go a.SendMessage(msg, commsSystem)
+		}
+		return
+	}
+
+	reasons := make([]DeadLetterReason, len(msgs))
+	var fragmentsWg sync.WaitGroup
+	for i, msg := range msgs {
+		fragmentsWg.Add(1)
+		go a.SendMessageNotify(msg, commsSystem, func(reason DeadLetterReason) {
+			reasons[i] = reason
+			fragmentsWg.Done()
+		})
+	}
+	go func() {
+		fragmentsWg.Wait()
+		for _, reason := range reasons {
+			if reason != "" {
+				onDone(reason)
+				return
+			}
+		}
+		onDone("")
+	}()
 }
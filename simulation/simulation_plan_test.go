@@ -0,0 +1,120 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestGenerateFlightPlansDeterministic(t *testing.T) {
+	for _, n := range []int{0, 1, 25} {
+		plans, err := GenerateFlightPlans(n, 42)
+		if err != nil {
+			t.Fatalf("GenerateFlightPlans(%d) 返回错误: %v", n, err)
+		}
+		if len(plans) != n {
+			t.Fatalf("GenerateFlightPlans(%d) 返回 %d 个计划", n, len(plans))
+		}
+		for i, plan := range plans {
+			if plan.Type != "Departing" && plan.Type != "Arriving" {
+				t.Errorf("计划 %d 的类型无效: %q", i, plan.Type)
+			}
+			if plan.StartTimeMinutes < 1 || plan.StartTimeMinutes > config.FlightPlanWindowMinutes {
+				t.Errorf("计划 %d 的开始时间 %d 超出 [1, %d]", i, plan.StartTimeMinutes, config.FlightPlanWindowMinutes)
+			}
+		}
+		if again, _ := GenerateFlightPlans(n, 42); !reflect.DeepEqual(plans, again) {
+			t.Errorf("相同 seed 生成的 %d 个计划不一致", n)
+		}
+	}
+
+	first, _ := GenerateFlightPlans(25, 1)
+	second, _ := GenerateFlightPlans(25, 2)
+	if reflect.DeepEqual(first, second) {
+		t.Error("不同 seed 生成了相同的计划")
+	}
+}
+
+// 飞机数量为负数或开始时间范围不为正时返回错误，而不是 panic。
+func TestGenerateFlightPlansRejectsInvalidInput(t *testing.T) {
+	if _, err := GenerateFlightPlans(-1, 42); err == nil {
+		t.Error("飞机数量为负数时应返回错误")
+	}
+	for _, window := range []int{0, -5} {
+		if _, err := generateFlightPlans(5, 42, window); err == nil {
+			t.Errorf("开始时间范围为 %d 分钟时应返回错误", window)
+		}
+	}
+}
+
+// 为 5 架与 100 架飞机分配计划：每架飞机恰好一个计划，航向均匀分布，传入的计划不被修改。
+func TestAssignFlightPlansFollowsFleetSize(t *testing.T) {
+	for _, n := range []int{5, 100} {
+		aircraftList := make([]*Aircraft, n)
+		for i := range aircraftList {
+			aircraftList[i] = newTestAircraft(fmt.Sprintf("A%05d", i), fmt.Sprintf("CCA%d", 100+i))
+		}
+		plans, err := GenerateFlightPlans(n, 42)
+		if err != nil {
+			t.Fatal(err)
+		}
+		original := append([]FlightPlan(nil), plans...)
+
+		session, err := assignFlightPlans(plans, aircraftList)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(session) != n {
+			t.Fatalf("%d 架飞机得到 %d 个计划", n, len(session))
+		}
+		for i, plan := range session {
+			if plan.Aircraft != aircraftList[i] {
+				t.Errorf("计划 %d 分配给了 %v，期望飞机 %s", i, plan.Aircraft, aircraftList[i].ICAOAddress)
+			}
+			if want := float64(i) * 360 / float64(n); plan.HeadingDeg != want {
+				t.Errorf("计划 %d 的航向 = %.1f，期望 %.1f", i, plan.HeadingDeg, want)
+			}
+		}
+		if !reflect.DeepEqual(plans, original) {
+			t.Errorf("%d 架飞机: 分配计划修改了传入的计划", n)
+		}
+	}
+
+	// 数量不一致时按飞机数量重新生成
+	session, err := assignFlightPlans(nil, []*Aircraft{newTestAircraft("A00001", "CCA101")})
+	if err != nil || len(session) != 1 {
+		t.Errorf("数量不一致时得到 %d 个计划 (err=%v)，期望按飞机数量重新生成 1 个", len(session), err)
+	}
+}
+
+// 在同一时刻连续生成的故障报告ID (故障风暴) 各不相同，并发生成时也不重复。
+func TestFaultMessageIDsUnique(t *testing.T) {
+	a := newTestAircraft("A00001", "CCA101")
+	fault := flightFaults[0]
+
+	const workers, perWorker = 4, 250
+	ids := make([][]string, workers)
+	var wg sync.WaitGroup
+	for w := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perWorker {
+				ids[w] = append(ids[w], a.nextFaultMessageID(fault))
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool, workers*perWorker)
+	for _, batch := range ids {
+		for _, id := range batch {
+			if seen[id] {
+				t.Fatalf("故障报告ID %s 重复", id)
+			}
+			seen[id] = true
+		}
+	}
+}
@@ -0,0 +1,612 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// ActiveTransmissionSnapshot 记录快照时信道上一次正在进行的传输 (时长均为模拟时间)。
+type ActiveTransmissionSnapshot struct {
+	Priority  config.Priority `json:"priority"`
+	Elapsed   time.Duration   `json:"elapsed"`   // 已占用信道的时间
+	Remaining time.Duration   `json:"remaining"` // 距离释放信道的剩余时间
+	Corrupted bool            `json:"corrupted"`
+}
+
+// ChannelSnapshot 记录一个信道在某一时刻的完整状态，时长均为模拟时间。
+type ChannelSnapshot struct {
+	ID     string                       `json:"id"`
+	Active []ActiveTransmissionSnapshot `json:"active,omitempty"` // 快照时正在进行的传输
+
+	TotalMessagesTransmitted uint64        `json:"totalMessagesTransmitted"`
+	CriticalTransmitted      uint64        `json:"criticalTransmitted"`
+	TotalBusyTime            time.Duration `json:"totalBusyTime"`
+	OccupancyTime            time.Duration `json:"occupancyTime"`
+	TransmitAttempts         uint64        `json:"transmitAttempts"`
+	OfferedAirTime           time.Duration `json:"offeredAirTime"`
+	SuccessfulAirTime        time.Duration `json:"successfulAirTime"`
+	StatsElapsed             time.Duration `json:"statsElapsed"` // 统计开始至快照的时长
+	FramesCorrupted          uint64        `json:"framesCorrupted"`
+	WeakSignalLosses         uint64        `json:"weakSignalLosses"`
+	OverlapCollisions        uint64        `json:"overlapCollisions"`
+	RecoveredTime            time.Duration `json:"recoveredTime"`
+	Preemptions              uint64        `json:"preemptions"`
+	PreemptedTime            time.Duration `json:"preemptedTime"`
+	PriorityInversions       uint64        `json:"priorityInversions"`
+	IdleTime                 time.Duration `json:"idleTime"`
+	IdleGaps                 []uint64      `json:"idleGaps,omitempty"`
+
+	PValues  map[config.Priority]float64 `json:"pValues"`
+	TimeSlot time.Duration               `json:"timeSlot"`
+}
+
+// Snapshot 捕获信道当前的状态。
+func (c *Channel) Snapshot() ChannelSnapshot {
+	c.pValuesMutex.RLock()
+	pValues := make(map[config.Priority]float64, len(c.pValues))
+	for k, v := range c.pValues {
+		pValues[k] = v
+	}
+	c.pValuesMutex.RUnlock()
+
+	snap := ChannelSnapshot{
+		ID:                       c.ID,
+		TotalMessagesTransmitted: c.totalMessagesTransmitted.Load(),
+		CriticalTransmitted:      c.criticalTransmitted.Load(),
+		TransmitAttempts:         c.transmitAttempts.Load(),
+		OfferedAirTime:           Unscaled(time.Duration(c.offeredAirTime.Load())),
+		FramesCorrupted:          c.framesCorrupted.Load(),
+		WeakSignalLosses:         c.weakSignalLosses.Load(),
+		OverlapCollisions:        c.overlapCollisions.Load(),
+		Preemptions:              c.preemptions.Load(),
+		PriorityInversions:       c.priorityInversions.Load(),
+		PValues:                  pValues,
+		TimeSlot:                 c.GetCurrentTimeSlot(),
+	}
+
+	c.mutex.Lock()
+	now := time.Now()
+	for _, tx := range c.active {
+		if tx.released {
+			continue
+		}
+		snap.Active = append(snap.Active, ActiveTransmissionSnapshot{
+			Priority:  tx.priority,
+			Elapsed:   Unscaled(now.Sub(tx.start)),
+			Remaining: Unscaled(max(tx.end.Sub(now), 0)),
+			Corrupted: tx.corrupted,
+		})
+	}
+	busyTime := c.totalBusyTime
+	if c.occupancy >= c.capacity() {
+		busyTime += now.Sub(c.lastBusyTimestamp) // 仍处于满载的这一段也计入
+	}
+	snap.TotalBusyTime = Unscaled(busyTime)
+	snap.OccupancyTime = Unscaled(c.occupancyTime)
+	snap.SuccessfulAirTime = Unscaled(c.successfulAirTime)
+	snap.StatsElapsed = Unscaled(now.Sub(c.statsSince))
+	snap.RecoveredTime = Unscaled(c.recoveredTime)
+	snap.PreemptedTime = Unscaled(c.preemptedTime)
+	snap.IdleTime, snap.IdleGaps = c.idle.snapshot(now)
+	c.mutex.Unlock()
+	return snap
+}
+
+// Restore 将快照恢复到一个尚未使用的信道上。
+// 快照时正在进行的传输按其剩余时间继续占用信道，结束时不投递任何报文：
+// 报文本身由其发送方的发送中报文快照重新发送 (见 SimulationState.RestoreInto)。
+func (c *Channel) Restore(snap ChannelSnapshot) {
+	c.mutex.Lock()
+	now := time.Now()
+	restored := make([]*activeTransmission, 0, len(snap.Active))
+	for _, active := range snap.Active {
+		restored = append(restored, &activeTransmission{
+			start:     now.Add(-Scaled(active.Elapsed)),
+			end:       now.Add(Scaled(active.Remaining)),
+			wake:      make(chan struct{}, 1),
+			priority:  active.Priority,
+			corrupted: active.Corrupted,
+		})
+	}
+	c.active = restored
+	c.occupancy = len(restored)
+	if c.occupancy >= c.capacity() {
+		c.lastBusyTimestamp = now
+	} else {
+		c.idleSince = now
+	}
+	c.totalBusyTime = Scaled(snap.TotalBusyTime)
+	c.occupancyTime = Scaled(snap.OccupancyTime)
+	c.successfulAirTime = Scaled(snap.SuccessfulAirTime)
+	c.statsSince = now.Add(-Scaled(snap.StatsElapsed))
+	c.recoveredTime = Scaled(snap.RecoveredTime)
+	c.preemptedTime = Scaled(snap.PreemptedTime)
+	c.idle = idleTracker{total: snap.IdleTime, gaps: append([]uint64(nil), snap.IdleGaps...)}
+	if c.occupancy == 0 {
+		c.idle.begin(now)
+	}
+	c.mutex.Unlock()
+
+	c.totalMessagesTransmitted.Store(snap.TotalMessagesTransmitted)
+	c.criticalTransmitted.Store(snap.CriticalTransmitted)
+	c.transmitAttempts.Store(snap.TransmitAttempts)
+	c.offeredAirTime.Store(int64(Scaled(snap.OfferedAirTime)))
+	c.framesCorrupted.Store(snap.FramesCorrupted)
+	c.weakSignalLosses.Store(snap.WeakSignalLosses)
+	c.overlapCollisions.Store(snap.OverlapCollisions)
+	c.preemptions.Store(snap.Preemptions)
+	c.priorityInversions.Store(snap.PriorityInversions)
+	c.UpdatePValues(snap.PValues)
+	c.UpdateCurrentTimeSlot(snap.TimeSlot)
+
+	for _, tx := range restored {
+		go func() {
+			c.awaitEnd(tx)
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			if !tx.released {
+				tx.released = true
+				c.releaseLocked(tx, time.Now(), txRestored)
+			}
+		}()
+	}
+	if len(restored) > 0 {
+		slog.Info("♻️  信道在快照时有正在进行的传输，按剩余时间继续占用信道", "channel", c.ID, "active", len(restored))
+	}
+}
+
+// CommunicationSystemSnapshot 记录通信系统及其所有信道的状态。
+type CommunicationSystemSnapshot struct {
+	Channels                []ChannelSnapshot           `json:"channels"` // 顺序与 CommunicationSystem.Channels 相同
+	SwitchoverProbabilities map[config.Priority]float64 `json:"switchoverProbabilities"`
+}
+
+// Snapshot 捕获通信系统当前的状态。
+func (cs *CommunicationSystem) Snapshot() CommunicationSystemSnapshot {
+	var snap CommunicationSystemSnapshot
+	for _, ch := range cs.Channels() {
+		snap.Channels = append(snap.Channels, ch.Snapshot())
+	}
+
+	cs.switchoverProbabilitiesMutex.RLock()
+	snap.SwitchoverProbabilities = make(map[config.Priority]float64, len(cs.switchoverProbabilities))
+	for k, v := range cs.switchoverProbabilities {
+		snap.SwitchoverProbabilities[k] = v
+	}
+	cs.switchoverProbabilitiesMutex.RUnlock()
+	return snap
+}
+
+// Restore 将快照恢复到通信系统，信道按ID匹配。监听者的注册由 SimulationState.RestoreInto 负责。
+func (cs *CommunicationSystem) Restore(snap CommunicationSystemSnapshot) error {
+	channelByID := make(map[string]*Channel)
+	for _, ch := range cs.Channels() {
+		channelByID[ch.ID] = ch
+	}
+	for _, chSnap := range snap.Channels {
+		ch, ok := channelByID[chSnap.ID]
+		if !ok {
+			return fmt.Errorf("快照中的信道 [%s] 在当前通信系统中不存在", chSnap.ID)
+		}
+		ch.Restore(chSnap)
+	}
+	cs.UpdateSwitchoverProbabilities(snap.SwitchoverProbabilities)
+	return nil
+}
+
+// TransmitterSnapshot 记录飞机与地面站共用的信道接入统计。
+type TransmitterSnapshot struct {
+	TotalTxAttempts    uint64 `json:"totalTxAttempts"`
+	TotalCollisions    uint64 `json:"totalCollisions"`
+	TotalRqTunnel      uint64 `json:"totalRqTunnel"`
+	TotalFailRqTunnel  uint64 `json:"totalFailRqTunnel"`
+	TotalWaitTimeNs    int64  `json:"totalWaitTimeNs"`
+	ForcedSwitchovers  uint64 `json:"forcedSwitchovers"`
+	QueueOverflowDrops uint64 `json:"queueOverflowDrops"`
+}
+
+func (t *Transmitter) snapshot() TransmitterSnapshot {
+	return TransmitterSnapshot{
+		TotalTxAttempts:    atomic.LoadUint64(&t.totalTxAttempts),
+		TotalCollisions:    atomic.LoadUint64(&t.totalCollisions),
+		TotalRqTunnel:      atomic.LoadUint64(&t.totalRqTunnel),
+		TotalFailRqTunnel:  atomic.LoadUint64(&t.totalFailRqTunnel),
+		TotalWaitTimeNs:    t.totalWaitTimeNs.Load(),
+		ForcedSwitchovers:  atomic.LoadUint64(&t.forcedSwitchovers),
+		QueueOverflowDrops: atomic.LoadUint64(&t.queueOverflowDrops),
+	}
+}
+
+func (t *Transmitter) restore(snap TransmitterSnapshot) {
+	atomic.StoreUint64(&t.totalTxAttempts, snap.TotalTxAttempts)
+	atomic.StoreUint64(&t.totalCollisions, snap.TotalCollisions)
+	atomic.StoreUint64(&t.totalRqTunnel, snap.TotalRqTunnel)
+	atomic.StoreUint64(&t.totalFailRqTunnel, snap.TotalFailRqTunnel)
+	t.totalWaitTimeNs.Store(snap.TotalWaitTimeNs)
+	atomic.StoreUint64(&t.forcedSwitchovers, snap.ForcedSwitchovers)
+	atomic.StoreUint64(&t.queueOverflowDrops, snap.QueueOverflowDrops)
+}
+
+// PendingMessage 记录快照时仍在发送中 (正在竞争信道或等待 ACK) 的一个报文。
+type PendingMessage struct {
+	Header     ACARSBaseMessage `json:"header"`
+	Priority   config.Priority  `json:"priority"`
+	Data       json.RawMessage  `json:"data"`
+	EnqueuedAt time.Time        `json:"enqueuedAt"`
+}
+
+// message 重建报文，校验和按头部与数据重新计算。
+func (p PendingMessage) message() ACARSMessageInterface {
+	return wrapPayload(p.Priority, p.Header, p.Data)
+}
+
+// DeadLetterSnapshot 记录一个实体已确认无法送达的报文以及仍在发送中的报文。
+type DeadLetterSnapshot struct {
+	DeadLetters []DeadLetter     `json:"deadLetters,omitempty"`
+	Pending     []PendingMessage `json:"pending,omitempty"`
+}
+
+func (b *deadLetterBook) snapshot() DeadLetterSnapshot {
+	snap := DeadLetterSnapshot{DeadLetters: b.deadLetters()}
+	for _, letter := range b.pendingMessages() {
+		if letter.message == nil {
+			continue
+		}
+		data, _ := letter.message.GetData().(json.RawMessage)
+		snap.Pending = append(snap.Pending, PendingMessage{
+			Header:     letter.message.GetBaseMessage(),
+			Priority:   letter.Priority,
+			Data:       data,
+			EnqueuedAt: letter.EnqueuedAt,
+		})
+	}
+	return snap
+}
+
+// LatencySnapshot 记录一种优先级的时延蓄水池。
+type LatencySnapshot struct {
+	Samples []time.Duration `json:"samples"`
+	Seen    uint64          `json:"seen"`
+}
+
+func (s *latencyStats) snapshot() map[config.Priority]LatencySnapshot {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	snap := make(map[config.Priority]LatencySnapshot, len(s.byPriority))
+	for priority, r := range s.byPriority {
+		snap[priority] = LatencySnapshot{Samples: append([]time.Duration(nil), r.samples...), Seen: r.seen}
+	}
+	return snap
+}
+
+func (s *latencyStats) restore(snap map[config.Priority]LatencySnapshot) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.byPriority = make(map[config.Priority]*latencyReservoir, len(snap))
+	for priority, r := range snap {
+		s.byPriority[priority] = &latencyReservoir{samples: append([]time.Duration(nil), r.Samples...), seen: r.Seen}
+	}
+}
+
+// AircraftSnapshot 记录一架飞机的全部统计与仍在发送中的报文。
+type AircraftSnapshot struct {
+	ICAOAddress string              `json:"icaoAddress"`
+	FlightID    string              `json:"flightID"`
+	Transmitter TransmitterSnapshot `json:"transmitter"`
+
+	SuccessfulTx      uint64        `json:"successfulTx"`
+	TotalRetries      uint64        `json:"totalRetries"`
+	PermanentFailures uint64        `json:"permanentFailures"`
+	NacksReceived     uint64        `json:"nacksReceived"`
+	ChecksumFailures  uint64        `json:"checksumFailures"`
+	SectorCrossings   uint64        `json:"sectorCrossings"`
+	AckMissedDueToTx  uint64        `json:"ackMissedDueToTx"`
+	AckMissedTuning   uint64        `json:"ackMissedTuning"`
+	AckMissedOffTuned uint64        `json:"ackMissedOffTuned"`
+	LinkQualityLosses uint64        `json:"linkQualityLosses"`
+	DeadZoneLosses    uint64        `json:"deadZoneLosses"`
+	DeadZoneTime      time.Duration `json:"deadZoneTime"`
+	ListenerDrops     uint64        `json:"listenerDrops"`
+	ReceiverRetunes   uint64        `json:"receiverRetunes"`
+
+	DATISReceived          uint64       `json:"datisReceived"`
+	LatestDATIS            *DATISData   `json:"latestDATIS,omitempty"`
+	WeatherRequestsSent    uint64       `json:"weatherRequestsSent"`
+	WeatherRepliesReceived uint64       `json:"weatherRepliesReceived"`
+	LatestWeather          *WeatherData `json:"latestWeather,omitempty"`
+
+	LinkTestRTTs      []time.Duration                     `json:"linkTestRTTs,omitempty"`
+	AckRTTs           []time.Duration                     `json:"ackRTTs,omitempty"`
+	MaxWaitByPriority map[config.Priority]time.Duration   `json:"maxWaitByPriority,omitempty"`
+	Latencies         map[config.Priority]LatencySnapshot `json:"latencies,omitempty"`
+	StormLatencies    map[config.Priority]LatencySnapshot `json:"stormLatencies,omitempty"`
+	Outbound          DeadLetterSnapshot                  `json:"outbound"`
+
+	InboundQueueDepth int                `json:"inboundQueueDepth"` // 快照时收件箱中未处理的报文数 (仅供参考，不恢复)
+	Position          PositionReportData `json:"position"`          // 快照时的位置 (仅供参考，航迹由飞行计划重新驱动)
+}
+
+// Snapshot 捕获飞机当前的状态。
+func (a *Aircraft) Snapshot() AircraftSnapshot {
+	snap := AircraftSnapshot{
+		ICAOAddress: a.ICAOAddress,
+		FlightID:    a.CurrentFlightID,
+		Transmitter: a.Transmitter.snapshot(),
+
+		SuccessfulTx:      atomic.LoadUint64(&a.successfulTx),
+		TotalRetries:      atomic.LoadUint64(&a.totalRetries),
+		PermanentFailures: atomic.LoadUint64(&a.permanentFailures),
+		NacksReceived:     atomic.LoadUint64(&a.nacksReceived),
+		ChecksumFailures:  atomic.LoadUint64(&a.checksumFailures),
+		SectorCrossings:   atomic.LoadUint64(&a.sectorCrossings),
+		AckMissedDueToTx:  atomic.LoadUint64(&a.ackMissedDueToTx),
+		AckMissedTuning:   atomic.LoadUint64(&a.ackMissedTuning),
+		AckMissedOffTuned: atomic.LoadUint64(&a.ackMissedOffTuned),
+		LinkQualityLosses: atomic.LoadUint64(&a.linkQualityLosses),
+		DeadZoneLosses:    atomic.LoadUint64(&a.deadZoneLosses),
+		DeadZoneTime:      a.DeadZoneTime(),
+		ListenerDrops:     a.listener.Drops(),
+		ReceiverRetunes:   a.listener.Retunes(),
+
+		DATISReceived:          atomic.LoadUint64(&a.datisReceived),
+		LatestDATIS:            a.LatestDATIS(),
+		WeatherRequestsSent:    atomic.LoadUint64(&a.weatherRequestsSent),
+		WeatherRepliesReceived: atomic.LoadUint64(&a.weatherRepliesReceived),
+		LatestWeather:          a.LatestWeather(),
+
+		Latencies:      a.latencies.snapshot(),
+		StormLatencies: a.stormLatencies.snapshot(),
+		Outbound:       a.deadLetters.snapshot(),
+
+		InboundQueueDepth: len(a.inboundQueue),
+		Position:          a.GetPosition(),
+	}
+
+	a.linkTestMutex.Lock()
+	snap.LinkTestRTTs = append([]time.Duration(nil), a.linkTestRTTs...)
+	a.linkTestMutex.Unlock()
+
+	a.ackRTTMutex.Lock()
+	snap.AckRTTs = append([]time.Duration(nil), a.ackRTTs...)
+	a.ackRTTMutex.Unlock()
+
+	a.maxWaitMutex.Lock()
+	snap.MaxWaitByPriority = maps.Clone(a.maxWaitByPriority)
+	a.maxWaitMutex.Unlock()
+	return snap
+}
+
+// Restore 将快照中的统计恢复到飞机上。仍在发送中的报文由 SimulationState.RestoreInto 重新发送。
+func (a *Aircraft) Restore(snap AircraftSnapshot) {
+	a.Transmitter.restore(snap.Transmitter)
+	atomic.StoreUint64(&a.successfulTx, snap.SuccessfulTx)
+	atomic.StoreUint64(&a.totalRetries, snap.TotalRetries)
+	atomic.StoreUint64(&a.permanentFailures, snap.PermanentFailures)
+	atomic.StoreUint64(&a.nacksReceived, snap.NacksReceived)
+	atomic.StoreUint64(&a.checksumFailures, snap.ChecksumFailures)
+	atomic.StoreUint64(&a.sectorCrossings, snap.SectorCrossings)
+	atomic.StoreUint64(&a.ackMissedDueToTx, snap.AckMissedDueToTx)
+	atomic.StoreUint64(&a.ackMissedTuning, snap.AckMissedTuning)
+	atomic.StoreUint64(&a.ackMissedOffTuned, snap.AckMissedOffTuned)
+	atomic.StoreUint64(&a.linkQualityLosses, snap.LinkQualityLosses)
+	atomic.StoreUint64(&a.deadZoneLosses, snap.DeadZoneLosses)
+	a.listener.drops.Store(snap.ListenerDrops)
+	if a.listener.tuner != nil {
+		a.listener.tuner.retunes.Store(snap.ReceiverRetunes)
+	}
+
+	a.positionMutex.Lock()
+	a.deadZoneTime = snap.DeadZoneTime
+	if !a.trackSince.IsZero() {
+		a.trackSince = time.Now() // 之前的停留时间已包含在快照中
+	}
+	a.positionMutex.Unlock()
+
+	atomic.StoreUint64(&a.datisReceived, snap.DATISReceived)
+	a.datisMutex.Lock()
+	a.latestDATIS = snap.LatestDATIS
+	a.datisMutex.Unlock()
+	atomic.StoreUint64(&a.weatherRequestsSent, snap.WeatherRequestsSent)
+	atomic.StoreUint64(&a.weatherRepliesReceived, snap.WeatherRepliesReceived)
+	a.weatherMutex.Lock()
+	a.latestWeather = snap.LatestWeather
+	a.weatherMutex.Unlock()
+
+	a.linkTestMutex.Lock()
+	a.linkTestRTTs = append([]time.Duration(nil), snap.LinkTestRTTs...)
+	a.linkTestMutex.Unlock()
+
+	var ackRttNs int64
+	for _, rtt := range snap.AckRTTs {
+		ackRttNs += rtt.Nanoseconds()
+	}
+	a.ackRttNs.Store(ackRttNs)
+	a.ackRTTMutex.Lock()
+	a.ackRTTs = append([]time.Duration(nil), snap.AckRTTs...)
+	a.ackRTTMutex.Unlock()
+
+	a.maxWaitMutex.Lock()
+	a.maxWaitByPriority = maps.Clone(snap.MaxWaitByPriority)
+	a.maxWaitMutex.Unlock()
+
+	a.latencies.restore(snap.Latencies)
+	a.stormLatencies.restore(snap.StormLatencies)
+	a.deadLetters.restoreDeadLetters(snap.Outbound.DeadLetters)
+}
+
+// GroundControlCenterSnapshot 记录一个地面站的全部统计与仍在发送中的报文。
+type GroundControlCenterSnapshot struct {
+	ID          string              `json:"id"`
+	Transmitter TransmitterSnapshot `json:"transmitter"`
+
+	SuccessfulTx         uint64 `json:"successfulTx"`
+	TotalReceived        uint64 `json:"totalReceived"`
+	OutOfCoverageIgnored uint64 `json:"outOfCoverageIgnored"`
+	HandoversIn          uint64 `json:"handoversIn"`
+	DuplicatesReceived   uint64 `json:"duplicatesReceived"`
+	ChecksumFailures     uint64 `json:"checksumFailures"`
+	DATISBroadcasts      uint64 `json:"datisBroadcasts"`
+	WeatherReplies       uint64 `json:"weatherReplies"`
+	AckFramesSent        uint64 `json:"ackFramesSent"`
+	AcksSent             uint64 `json:"acksSent"`
+	NacksSent            uint64 `json:"nacksSent"`
+	OutageBuffered       uint64 `json:"outageBuffered"`
+	OutageDropped        uint64 `json:"outageDropped"`
+	ListenerDrops        uint64 `json:"listenerDrops"`
+
+	RecentMessageIDs []string           `json:"recentMessageIDs,omitempty"` // 重复报文检测缓存，最久未出现的在前
+	Outbound         DeadLetterSnapshot `json:"outbound"`
+
+	InboundQueueDepth int `json:"inboundQueueDepth"` // 快照时收件箱中未处理的报文数 (仅供参考，不恢复)
+}
+
+// Snapshot 捕获地面站当前的状态。
+func (gcc *GroundControlCenter) Snapshot() GroundControlCenterSnapshot {
+	gcc.outageMutex.Lock()
+	outageBuffered, outageDropped := gcc.outageBuffered, gcc.outageDropped
+	gcc.outageMutex.Unlock()
+	return GroundControlCenterSnapshot{
+		ID:          gcc.ID,
+		Transmitter: gcc.Transmitter.snapshot(),
+
+		SuccessfulTx:         atomic.LoadUint64(&gcc.successfulTx),
+		TotalReceived:        atomic.LoadUint64(&gcc.totalReceived),
+		OutOfCoverageIgnored: atomic.LoadUint64(&gcc.outOfCoverageIgnored),
+		HandoversIn:          atomic.LoadUint64(&gcc.handoversIn),
+		DuplicatesReceived:   atomic.LoadUint64(&gcc.duplicatesReceived),
+		ChecksumFailures:     atomic.LoadUint64(&gcc.checksumFailures),
+		DATISBroadcasts:      atomic.LoadUint64(&gcc.datisBroadcasts),
+		WeatherReplies:       atomic.LoadUint64(&gcc.weatherReplies),
+		AckFramesSent:        atomic.LoadUint64(&gcc.ackFramesSent),
+		AcksSent:             atomic.LoadUint64(&gcc.acksSent),
+		NacksSent:            atomic.LoadUint64(&gcc.nacksSent),
+		OutageBuffered:       outageBuffered,
+		OutageDropped:        outageDropped,
+		ListenerDrops:        gcc.listener.Drops(),
+
+		RecentMessageIDs: gcc.recentMessages.ids(),
+		Outbound:         gcc.deadLetters.snapshot(),
+
+		InboundQueueDepth: len(gcc.inboundQueue),
+	}
+}
+
+// Restore 将快照中的统计恢复到地面站上。仍在发送中的报文由 SimulationState.RestoreInto 重新发送。
+func (gcc *GroundControlCenter) Restore(snap GroundControlCenterSnapshot) {
+	gcc.Transmitter.restore(snap.Transmitter)
+	atomic.StoreUint64(&gcc.successfulTx, snap.SuccessfulTx)
+	atomic.StoreUint64(&gcc.totalReceived, snap.TotalReceived)
+	atomic.StoreUint64(&gcc.outOfCoverageIgnored, snap.OutOfCoverageIgnored)
+	atomic.StoreUint64(&gcc.handoversIn, snap.HandoversIn)
+	atomic.StoreUint64(&gcc.duplicatesReceived, snap.DuplicatesReceived)
+	atomic.StoreUint64(&gcc.checksumFailures, snap.ChecksumFailures)
+	atomic.StoreUint64(&gcc.datisBroadcasts, snap.DATISBroadcasts)
+	atomic.StoreUint64(&gcc.weatherReplies, snap.WeatherReplies)
+	atomic.StoreUint64(&gcc.ackFramesSent, snap.AckFramesSent)
+	atomic.StoreUint64(&gcc.acksSent, snap.AcksSent)
+	atomic.StoreUint64(&gcc.nacksSent, snap.NacksSent)
+	gcc.outageMutex.Lock()
+	gcc.outageBuffered, gcc.outageDropped = snap.OutageBuffered, snap.OutageDropped
+	gcc.outageMutex.Unlock()
+	gcc.listener.drops.Store(snap.ListenerDrops)
+
+	for _, id := range snap.RecentMessageIDs {
+		gcc.recentMessages.seen(id)
+	}
+	gcc.deadLetters.restoreDeadLetters(snap.Outbound.DeadLetters)
+}
+
+// SimulationState 汇总了整个模拟在某一时刻的状态，可序列化为 JSON 以便调试时复现。
+type SimulationState struct {
+	CapturedAt     time.Time                     `json:"capturedAt"`
+	Comms          CommunicationSystemSnapshot   `json:"comms"`
+	Aircraft       []AircraftSnapshot            `json:"aircraft"`
+	GroundStations []GroundControlCenterSnapshot `json:"groundStations"`
+}
+
+// CaptureSimulationState 捕获通信系统、所有飞机和地面站的当前状态。
+func CaptureSimulationState(comms *CommunicationSystem, aircraftList []*Aircraft, stations []*GroundControlCenter) SimulationState {
+	state := SimulationState{
+		CapturedAt: time.Now(),
+		Comms:      comms.Snapshot(),
+	}
+	for _, a := range aircraftList {
+		state.Aircraft = append(state.Aircraft, a.Snapshot())
+	}
+	for _, gcc := range stations {
+		state.GroundStations = append(state.GroundStations, gcc.Snapshot())
+	}
+	return state
+}
+
+// RestoreInto 将状态恢复到一组新创建的实体中，实体按 ICAO 地址 / 地面站ID 匹配。
+// 恢复包括：所有统计计数器与样本、信道上正在进行的传输 (按剩余时间继续占用信道)、死信记录，
+// 以及重新注册各实体的监听者。快照时仍在发送中的报文 (正在竞争信道或等待 ACK) 会作为新的发送流程重新发送，
+// 其已进行的重传次数不保留。
+// 以下状态不恢复：收件箱中尚未处理的报文 (无法在不取出的情况下读取)、地面站尚未发出的合并 ACK 与未收齐的分片
+// (飞机会因等不到 ACK 而重传)、滑动窗口统计，以及飞机的航迹 (由飞行计划重新驱动)。
+// comms 须已调用 StartDispatching；实体处理收件箱的 StartListening 仍由调用方启动。
+func (s SimulationState) RestoreInto(comms *CommunicationSystem, aircraftList []*Aircraft, stations []*GroundControlCenter) error {
+	if err := comms.Restore(s.Comms); err != nil {
+		return err
+	}
+
+	aircraftByICAO := make(map[string]*Aircraft, len(aircraftList))
+	for _, a := range aircraftList {
+		aircraftByICAO[a.ICAOAddress] = a
+	}
+	for _, snap := range s.Aircraft {
+		a, ok := aircraftByICAO[snap.ICAOAddress]
+		if !ok {
+			return fmt.Errorf("快照中的飞机 %s 在当前模拟中不存在", snap.ICAOAddress)
+		}
+		a.Restore(snap)
+		comms.RegisterReceiver(a.listener, true)
+		for _, pending := range snap.Outbound.Pending {
+			go a.SendMessage(pending.message(), comms)
+		}
+	}
+
+	stationByID := make(map[string]*GroundControlCenter, len(stations))
+	for _, gcc := range stations {
+		stationByID[gcc.ID] = gcc
+	}
+	for _, snap := range s.GroundStations {
+		gcc, ok := stationByID[snap.ID]
+		if !ok {
+			return fmt.Errorf("快照中的地面站 %s 在当前模拟中不存在", snap.ID)
+		}
+		gcc.Restore(snap)
+		comms.RegisterReceiver(gcc.listener, false)
+		for _, pending := range snap.Outbound.Pending {
+			go gcc.SendMessage(pending.message(), comms)
+		}
+	}
+	return nil
+}
+
+// SaveToFile 将模拟状态以 JSON 格式写入文件。
+func (s SimulationState) SaveToFile(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadSimulationState 从 JSON 文件中读取模拟状态。
+func LoadSimulationState(path string) (SimulationState, error) {
+	var state SimulationState
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(data, &state)
+	return state, err
+}
@@ -0,0 +1,113 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// 模拟中途保存快照，恢复到一组新实体后，统计应保持不变，发送中的报文应被重新发送并送达。
+func TestSnapshotRestoreMidRun(t *testing.T) {
+	comms := newTestComms(newTestChannel("Primary"), nil)
+	gcc := newTestStation("GND")
+	a := newTestAircraft("A00001", "CCA101")
+	gcc.TrackAircraft([]*Aircraft{a})
+	startTestEntities(comms, []*Aircraft{a}, []*GroundControlCenter{gcc})
+
+	delivered := newTestMessage(t, a, "CCA101-POS-1", MsgTypePosition, config.HighPriority, a.GetPosition())
+	a.SendMessage(delivered, comms)
+	if got := a.GetRawStats().SuccessfulTx; got != 1 {
+		t.Fatalf("快照前成功发送数 = %d，期望 1", got)
+	}
+
+	// 模拟一个刚开始发送流程、尚未竞争到信道的报文
+	inFlight := newTestMessage(t, a, "CCA101-FUEL-1", MsgTypeFuel, config.HighPriority, FuelReportData{RemainingFuelKG: 9000})
+	if _, ok := a.deadLetters.admit(inFlight, time.Now(), 0, ""); !ok {
+		t.Fatal("发送中的报文未能登记")
+	}
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := CaptureSimulationState(comms, []*Aircraft{a}, []*GroundControlCenter{gcc}).SaveToFile(path); err != nil {
+		t.Fatalf("保存快照失败: %v", err)
+	}
+	state, err := LoadSimulationState(path)
+	if err != nil {
+		t.Fatalf("读取快照失败: %v", err)
+	}
+	if len(state.Aircraft) != 1 || len(state.Aircraft[0].Outbound.Pending) != 1 {
+		t.Fatalf("快照应包含 1 个发送中的报文: %+v", state.Aircraft)
+	}
+
+	restoredComms := newTestComms(newTestChannel("Primary"), nil)
+	restoredGCC := newTestStation("GND")
+	restoredA := newTestAircraft("A00001", "CCA101")
+	restoredGCC.TrackAircraft([]*Aircraft{restoredA})
+	if err := state.RestoreInto(restoredComms, []*Aircraft{restoredA}, []*GroundControlCenter{restoredGCC}); err != nil {
+		t.Fatalf("恢复快照失败: %v", err)
+	}
+	go restoredA.StartListening(restoredComms)
+	go restoredGCC.StartListening(restoredComms)
+
+	// 重新发送的报文至少需要一个传输时间才能送达，此时统计应与快照时一致
+	before, after := a.GetRawStats(), restoredA.GetRawStats()
+	if after.SuccessfulTx != before.SuccessfulTx || after.AckRTTCount != before.AckRTTCount || after.AckRTTAvg != before.AckRTTAvg {
+		t.Errorf("飞机统计未恢复: 恢复前 %+v，恢复后 %+v", before, after)
+	}
+	if after.Latency != before.Latency {
+		t.Errorf("时延统计未恢复: 恢复前 %+v，恢复后 %+v", before.Latency, after.Latency)
+	}
+	if got, want := restoredGCC.GetRawStats().TotalReceived, gcc.GetRawStats().TotalReceived; got != want {
+		t.Errorf("地面站接收数 = %d，期望 %d", got, want)
+	}
+	if !slices.Contains(restoredGCC.recentMessages.ids(), delivered.GetBaseMessage().MessageID) {
+		t.Error("地面站的重复报文检测缓存未恢复")
+	}
+	if got, want := restoredComms.PrimaryChannel.GetRawStats().TotalMessagesTransmitted, comms.PrimaryChannel.GetRawStats().TotalMessagesTransmitted; got != want {
+		t.Errorf("信道传输数 = %d，期望 %d", got, want)
+	}
+
+	waitFor(t, 10*time.Second, "发送中的报文在恢复后送达", func() bool {
+		return restoredA.GetRawStats().SuccessfulTx == 2 && len(restoredA.deadLetters.pendingMessages()) == 0
+	})
+	if got := restoredGCC.GetRawStats().TotalReceived; got != 2 {
+		t.Errorf("恢复后地面站接收数 = %d，期望 2", got)
+	}
+}
+
+// 快照时正在进行的传输在恢复后按剩余时间继续占用信道，结束时不投递任何报文。
+func TestChannelRestoreKeepsActiveTransmission(t *testing.T) {
+	ch := newTestChannel("Primary")
+	ch.StartDispatching()
+	inbox := make(chan ACARSMessageInterface, 1)
+	ch.RegisterListener(NewListener("A00001", inbox))
+
+	ch.Restore(ChannelSnapshot{
+		ID:                       "Primary",
+		TotalMessagesTransmitted: 5,
+		TotalBusyTime:            time.Second,
+		Active:                   []ActiveTransmissionSnapshot{{Priority: config.HighPriority, Elapsed: 40 * time.Millisecond, Remaining: 200 * time.Millisecond}},
+		PValues:                  config.PrimaryPMap,
+		TimeSlot:                 config.PrimaryTimeSlot,
+	})
+	if !ch.IsBusy() {
+		t.Fatal("恢复后信道应处于忙碌状态")
+	}
+	waitFor(t, 2*time.Second, "恢复的传输结束后信道空闲", func() bool { return !ch.IsBusy() })
+
+	if got := ch.GetTotalBusyTime(); got < time.Second+150*time.Millisecond {
+		t.Errorf("忙碌时间 = %v，应包含恢复的传输占用的时间", got)
+	}
+	if got := ch.GetRawStats().TotalMessagesTransmitted; got != 5 {
+		t.Errorf("传输数 = %d，恢复的传输不应计为一次新的传输", got)
+	}
+	if recent := ch.GetWindowedStats(time.Minute); recent.Transmitted != 0 || recent.Corrupted != 0 {
+		t.Errorf("滑动窗口记录了 %d 次送达、%d 次损坏，恢复的传输两者都不应计入", recent.Transmitted, recent.Corrupted)
+	}
+	select {
+	case msg := <-inbox:
+		t.Errorf("恢复的传输不应投递报文，收到 %s", msg.GetBaseMessage().MessageID)
+	default:
+	}
+}
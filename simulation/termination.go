@@ -0,0 +1,92 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 模拟运行可选的终止条件
+const (
+	TerminateByFlights      = "flights"       // 所有飞行计划完成
+	TerminateBySimMinutes   = "sim-minutes"   // 运行固定的模拟分钟数
+	TerminateByMessageCount = "message-count" // 送达固定数量的报文
+)
+
+// messageCountPollInterval 是 "message-count" 终止条件下检查送达报文数的间隔。
+const messageCountPollInterval = time.Second
+
+// CheckRunTermination 检查 config.RunTermination 是否为已知的终止条件。
+func CheckRunTermination() error {
+	switch config.RunTermination {
+	case TerminateByFlights, TerminateBySimMinutes, TerminateByMessageCount:
+		return nil
+	default:
+		return fmt.Errorf("未知的运行终止条件 %q", config.RunTermination)
+	}
+}
+
+// WaitForTermination 阻塞直到按 config.RunTermination 判定模拟运行结束，返回结束原因。
+// wg 跟踪所有飞行计划 (与回放轨迹)；所有飞行计划完成时运行总是结束。
+// 其他条件先满足时关闭 stop 通知飞行计划停止，并等待它们退出。已发出的报文仍按正常流程完成重传与 ACK。
+func WaitForTermination(wg *sync.WaitGroup, aircraftList []*Aircraft, stop chan<- struct{}) string {
+	flightsDone := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(flightsDone)
+	}()
+
+	var deadline, poll <-chan time.Time
+	switch config.RunTermination {
+	case TerminateBySimMinutes:
+		deadline = time.After(Scaled(time.Duration(config.RunTerminationMinutes) * time.Minute))
+	case TerminateByMessageCount:
+		ticker := time.NewTicker(messageCountPollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	for {
+		var reason string
+		select {
+		case <-flightsDone:
+			return "所有飞行计划已执行完毕"
+		case <-deadline:
+			reason = fmt.Sprintf("已运行 %d 分钟模拟时间", config.RunTerminationMinutes)
+		case <-poll:
+			delivered := deliveredMessages(aircraftList)
+			if delivered < config.RunTerminationMessages {
+				continue
+			}
+			reason = fmt.Sprintf("已送达 %d 个报文", delivered)
+		}
+		slog.Info("⏹️  运行终止条件已满足，停止尚未完成的飞行计划", "reason", reason)
+		close(stop)
+		<-flightsDone
+		return reason
+	}
+}
+
+// deliveredMessages 返回所有飞机已成功送达的报文总数。
+func deliveredMessages(aircraftList []*Aircraft) uint64 {
+	var total uint64
+	for _, a := range aircraftList {
+		total += atomic.LoadUint64(&a.successfulTx)
+	}
+	return total
+}
+
+// sleepUntilStopped 等待模拟时长 d，期间 stop 被关闭时提前返回 false。
+func sleepUntilStopped(d time.Duration, stop <-chan struct{}) bool {
+	timer := time.NewTimer(Scaled(d))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
@@ -0,0 +1,69 @@
+package simulation
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckRunTermination(t *testing.T) {
+	if err := CheckRunTermination(); err != nil {
+		t.Errorf("默认配置的终止条件无效: %v", err)
+	}
+}
+
+func TestDeliveredMessagesSumsFleet(t *testing.T) {
+	a, b := newTestAircraft("A00001", "CCA101"), newTestAircraft("A00002", "CCA102")
+	atomic.StoreUint64(&a.successfulTx, 3)
+	atomic.StoreUint64(&b.successfulTx, 4)
+	if got := deliveredMessages([]*Aircraft{a, b}); got != 7 {
+		t.Errorf("deliveredMessages = %d，期望 7", got)
+	}
+	if got := deliveredMessages(nil); got != 0 {
+		t.Errorf("没有飞机时 deliveredMessages = %d，期望 0", got)
+	}
+}
+
+func TestSleepUntilStopped(t *testing.T) {
+	if !sleepUntilStopped(10*time.Millisecond, make(chan struct{})) {
+		t.Error("未被停止时 sleepUntilStopped 应返回 true")
+	}
+
+	stop := make(chan struct{})
+	close(stop)
+	start := time.Now()
+	if sleepUntilStopped(time.Hour, stop) {
+		t.Error("已停止时 sleepUntilStopped 应返回 false")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("已停止时等待了 %v，应立即返回", elapsed)
+	}
+}
+
+// 所有飞行计划完成时运行总是结束，且不关闭 stop。
+func TestWaitForTerminationWhenFlightsFinish(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		time.Sleep(50 * time.Millisecond)
+	}()
+	stop := make(chan struct{})
+	done := make(chan string, 1)
+	go func() { done <- WaitForTermination(&wg, nil, stop) }()
+
+	select {
+	case reason := <-done:
+		if reason != "所有飞行计划已执行完毕" {
+			t.Errorf("结束原因 = %q", reason)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("飞行计划完成后 WaitForTermination 未返回")
+	}
+	select {
+	case <-stop:
+		t.Error("飞行计划自然完成时不应关闭 stop")
+	default:
+	}
+}
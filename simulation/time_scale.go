@@ -0,0 +1,48 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// timeScale 保存当前生效的时间缩放系数 (float64 的位模式)，初始为 config.TimeScale。
+// 以原子方式读写，仍有 goroutine 在运行时 (例如测试在两次运行之间) 更换系数也不会产生数据竞争。
+var timeScale atomic.Uint64
+
+func init() {
+	SetTimeScale(config.TimeScale)
+}
+
+// TimeScale 返回当前生效的时间缩放系数。
+func TimeScale() float64 {
+	return math.Float64frombits(timeScale.Load())
+}
+
+// SetTimeScale 更换时间缩放系数，应在模拟开始前调用；运行中更换会打乱已在等待的时长之间的相对时序。
+func SetTimeScale(scale float64) {
+	timeScale.Store(math.Float64bits(scale))
+}
+
+// Scaled 将模拟时长 d 按时间缩放系数换算为实际等待的时长。
+// 所有消耗时长的地方 (睡眠、定时器、传输时间、与时长阈值的比较) 都应经过它，使相对时序不随缩放系数改变。
+func Scaled(d time.Duration) time.Duration {
+	if scale := TimeScale(); scale > 0 && scale != 1 {
+		return time.Duration(float64(d) * scale)
+	}
+	return d
+}
+
+// Unscaled 将实际测得的时长换算回模拟时长，用于统计与报告。
+func Unscaled(d time.Duration) time.Duration {
+	if scale := TimeScale(); scale > 0 && scale != 1 {
+		return time.Duration(float64(d) / scale)
+	}
+	return d
+}
+
+// simSince 返回自 t 起经过的模拟时长。
+func simSince(t time.Time) time.Duration {
+	return Unscaled(time.Since(t))
+}
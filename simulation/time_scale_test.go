@@ -0,0 +1,99 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+// withTimeScale 在测试期间使用时间缩放系数 scale。
+func withTimeScale(t *testing.T, scale float64) {
+	t.Helper()
+	saved := TimeScale()
+	SetTimeScale(scale)
+	t.Cleanup(func() { SetTimeScale(saved) })
+}
+
+// hiddenTerminal 是一个 Scheduler：地面站的第 i 个帧进入信道后，一架听不到地面站的飞机 (隐藏终端)
+// 在该帧开始后 offsets[i % len(offsets)] 不监听信道直接尝试发送一次。尝试相对于帧的开始时刻安排，
+// 因此落在帧的碰撞窗口内、窗口之后的占用期内还是帧结束之后，只取决于各时长之间的比例。
+type hiddenTerminal struct {
+	ch      *Channel
+	id      string
+	offsets []time.Duration
+	msgs    []ACARSMessageInterface
+
+	wg     sync.WaitGroup
+	frames int // 只由地面站的发送 goroutine 访问
+}
+
+// Admit 实现 Scheduler。
+func (h *hiddenTerminal) Admit(attempt TransmitAttempt) func() {
+	if attempt.SenderID != h.id && h.frames < len(h.msgs) {
+		offset, msg := h.offsets[h.frames%len(h.offsets)], h.msgs[h.frames]
+		h.frames++
+		h.wg.Add(1)
+		go func() {
+			defer h.wg.Done()
+			time.Sleep(Scaled(offset))
+			h.ch.AttemptTransmit(msg, h.id)
+		}()
+	}
+	return func() {}
+}
+
+// runHiddenTerminalContention 让一个地面站以 1-坚持 CSMA 连续发送 frames 个报文，每个帧都伴随一次隐藏终端的尝试，
+// 依次在帧开始后 30ms (碰撞窗口内，发生碰撞)、150ms (窗口之后、帧结束之前，侦听到忙而放弃) 与 300ms (帧已结束，发送成功)。
+// 各时长之间至少相差 50ms 模拟时间，缩放后仍远大于调度延迟。返回信道上的碰撞率 (重叠碰撞数 / 传输尝试数)。
+func runHiddenTerminalContention(t *testing.T, frames int) float64 {
+	t.Helper()
+	ch := NewChannel("Primary", config.PrimaryPMap, 400*time.Millisecond)
+	ch.CollisionWindow = 100 * time.Millisecond
+	comms := newTestComms(ch, OnePersistentCSMA{})
+	gcc := newTestStation("GND")
+	a := newTestAircraft("A00001", "CCA101")
+
+	hidden := &hiddenTerminal{ch: ch, id: a.CurrentFlightID, offsets: []time.Duration{30 * time.Millisecond, 150 * time.Millisecond, 300 * time.Millisecond}}
+	msgs := make([]ACARSMessageInterface, frames)
+	for i := range msgs {
+		msgs[i] = newTestMessage(t, a, fmt.Sprintf("GND-POS-%d", i), MsgTypePosition, config.HighPriority, a.GetPosition())
+		hidden.msgs = append(hidden.msgs, newTestMessage(t, a, fmt.Sprintf("CCA101-POS-%d", i), MsgTypePosition, config.HighPriority, a.GetPosition()))
+	}
+	ch.Scheduler = hidden
+
+	for _, msg := range msgs {
+		gcc.SendMessage(msg, comms)
+	}
+	hidden.wg.Wait()
+	waitFor(t, 2*time.Second, "传输结束", func() bool { return ch.Occupancy() == 0 })
+
+	stats := ch.GetRawStats()
+	if stats.Efficiency.TransmitAttempts == 0 {
+		t.Fatal("没有任何传输尝试")
+	}
+	return float64(stats.OverlapCollisions) / float64(stats.Efficiency.TransmitAttempts)
+}
+
+// 所有时长按同一系数缩放，相对时序不变：同样的争用在 TimeScale 为 1 与 0.2 时碰撞率相同。
+// 若有任何时长 (帧长、时隙、碰撞窗口、隐藏终端的等待) 漏掉缩放，隐藏终端的尝试会落入另一个区间，碰撞率随之改变。
+func TestCollisionRateInvariantUnderTimeScale(t *testing.T) {
+	withTransmissionTimes(t, map[MessageType]time.Duration{MsgTypePosition: 200 * time.Millisecond})
+
+	rates := make(map[float64]float64)
+	for _, scale := range []float64{1, 0.2} {
+		withTimeScale(t, scale)
+		rates[scale] = runHiddenTerminalContention(t, 6)
+		t.Logf("TimeScale %v: 碰撞率 %.3f", scale, rates[scale])
+	}
+	if rates[1] == 0 {
+		t.Fatal("实时运行没有发生碰撞，无法比较")
+	}
+	const relativeTolerance = 0.03
+	if diff := math.Abs(rates[1]-rates[0.2]) / rates[1]; diff > relativeTolerance {
+		t.Errorf("碰撞率在 TimeScale 1 时为 %.3f，0.2 时为 %.3f，相对相差 %.1f%%，超过 %.0f%%",
+			rates[1], rates[0.2], diff*100, relativeTolerance*100)
+	}
+}
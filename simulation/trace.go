@@ -0,0 +1,163 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// 可通过配置选择的报文轨迹模式
+const (
+	TraceModeOff    = ""       // 不记录也不回放
+	TraceModeRecord = "record" // 记录飞机生成的每一份报告
+	TraceModeReplay = "replay" // 按记录的时刻回放报告，替代飞行过程中生成的报告
+)
+
+// TraceRecord 是报文轨迹中的一条记录，对应飞机生成的一份报告 (分片前)。
+type TraceRecord struct {
+	Offset    time.Duration   `json:"offset"` // 相对轨迹开始的时刻
+	Origin    string          `json:"origin"` // 发送方的 ICAO 地址
+	FlightID  string          `json:"flightID"`
+	MessageID string          `json:"messageID"`
+	Type      MessageType     `json:"type"`
+	Priority  config.Priority `json:"priority"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// TraceRecorder 将飞机生成的报告逐行写入 JSON Lines 格式的轨迹文件。
+type TraceRecorder struct {
+	mutex   sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	start   time.Time
+	count   int
+}
+
+// NewTraceRecorder 创建轨迹文件 (必要时创建其所在目录)，记录时刻从此时开始计算。
+func NewTraceRecorder(path string) (*TraceRecorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	writer := bufio.NewWriter(file)
+	return &TraceRecorder{file: file, writer: writer, encoder: json.NewEncoder(writer), start: time.Now()}, nil
+}
+
+// record 记录一份报告，写入失败时只记录日志，不影响模拟。
+func (r *TraceRecorder) record(base ACARSBaseMessage, priority config.Priority, data interface{}) {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		slog.Error("无法记录报文轨迹", "flight", base.FlightID, "msgID", base.MessageID, "err", err)
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	entry := TraceRecord{
+		Offset:    simSince(r.start),
+		Origin:    base.AircraftICAOAddress,
+		FlightID:  base.FlightID,
+		MessageID: base.MessageID,
+		Type:      base.Type,
+		Priority:  priority,
+		Data:      rawData,
+	}
+	if err := r.encoder.Encode(entry); err != nil {
+		slog.Error("无法记录报文轨迹", "flight", base.FlightID, "msgID", base.MessageID, "err", err)
+		return
+	}
+	r.count++
+}
+
+// Close 将缓冲的记录写入文件并关闭文件，返回记录的报告数。
+func (r *TraceRecorder) Close() (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.writer.Flush(); err != nil {
+		r.file.Close()
+		return r.count, err
+	}
+	return r.count, r.file.Close()
+}
+
+// TraceSource 按记录的时刻回放报文轨迹。
+// 回放时飞行计划照常执行 (飞机的位置、空域准入不变)，但飞行过程中生成的报告被丢弃，
+// 所有报告都来自轨迹，因此不同的信道接入策略可以在完全相同的流量下比较。
+type TraceSource struct {
+	Records []TraceRecord
+}
+
+// LoadTrace 读取 NewTraceRecorder 写出的轨迹文件。
+func LoadTrace(path string) (*TraceSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var source TraceSource
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	for decoder.More() {
+		var entry TraceRecord
+		if err := decoder.Decode(&entry); err != nil {
+			return nil, fmt.Errorf("第 %d 条轨迹记录无法解析: %w", len(source.Records)+1, err)
+		}
+		source.Records = append(source.Records, entry)
+	}
+	return &source, nil
+}
+
+// Run 在一个单独的goroutine中按记录的时刻重新发送轨迹中的报告，时刻从调用时开始计算。
+// 发送方按 ICAO 地址对应到 aircraftList 中的飞机，找不到对应飞机的记录会被跳过。
+// 调用后飞行过程中生成的报告不再发送。stop 被关闭时停止回放。
+func (s *TraceSource) Run(wg *sync.WaitGroup, commsSystem *CommunicationSystem, aircraftList []*Aircraft, stop <-chan struct{}) {
+	aircraftByICAO := make(map[string]*Aircraft, len(aircraftList))
+	for _, a := range aircraftList {
+		aircraftByICAO[a.ICAOAddress] = a
+	}
+	replayingTrace = true
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		start := time.Now()
+		skipped := 0
+		for i, entry := range s.Records {
+			a, ok := aircraftByICAO[entry.Origin]
+			if !ok {
+				skipped++
+				continue
+			}
+			if !sleepUntilStopped(entry.Offset-simSince(start), stop) {
+				slog.Info("📼 运行已终止，停止回放报文轨迹", "replayed", i-skipped)
+				return
+			}
+			baseMsg := ACARSBaseMessage{
+				AircraftICAOAddress: entry.Origin, FlightID: entry.FlightID,
+				MessageID: entry.MessageID, Type: entry.Type,
+			}
+			transmitReport(a, baseMsg, entry.Priority, entry.Data, commsSystem, nil)
+		}
+		slog.Info("📼 报文轨迹回放完毕", "records", len(s.Records), "skipped", skipped)
+	}()
+}
+
+// SetTraceRecorder 设置记录飞机生成报告的轨迹记录器，为 nil 时不记录。必须在模拟开始前调用。
+func SetTraceRecorder(r *TraceRecorder) {
+	traceRecorder = r
+}
+
+var (
+	traceRecorder  *TraceRecorder // 非 nil 时记录飞机生成的每一份报告
+	replayingTrace bool           // 为 true 时飞行过程中生成的报告被丢弃，报告只来自回放的轨迹
+)
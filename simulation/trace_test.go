@@ -0,0 +1,119 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordTestTrace 记录一架飞机间隔 gap 生成的一组报告，返回轨迹文件路径。
+func recordTestTrace(t *testing.T, a *Aircraft, types []MessageType, gap time.Duration) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+	recorder, err := NewTraceRecorder(path)
+	if err != nil {
+		t.Fatalf("创建轨迹文件失败: %v", err)
+	}
+	for i, msgType := range types {
+		if i > 0 {
+			time.Sleep(gap)
+		}
+		recorder.record(ACARSBaseMessage{
+			AircraftICAOAddress: a.ICAOAddress, FlightID: a.CurrentFlightID,
+			MessageID: a.CurrentFlightID + "-" + string(msgType), Type: msgType,
+		}, config.HighPriority, map[string]int{"seq": i})
+	}
+	if count, err := recorder.Close(); err != nil || count != len(types) {
+		t.Fatalf("关闭轨迹文件: 记录了 %d 条，err = %v", count, err)
+	}
+	return path
+}
+
+// replayTrace 在一个新建的单信道系统上以 access 策略回放轨迹，返回地面站依次收到的报文及其相对回放开始的时刻。
+func replayTrace(t *testing.T, source *TraceSource, access MediumAccess) ([]MessageType, []time.Duration, *GroundControlCenter) {
+	t.Helper()
+	comms := newTestComms(newTestChannel("Primary"), access)
+	a := newTestAircraft("A00001", "CCA101")
+	gcc := newTestStation("GND")
+	gcc.TrackAircraft([]*Aircraft{a})
+	startTestEntities(comms, []*Aircraft{a}, []*GroundControlCenter{gcc})
+	inbox := make(chan ACARSMessageInterface, 16)
+	capture := NewListener("CAPTURE", inbox)
+	capture.ground = true
+	comms.RegisterListener(capture)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	var wg sync.WaitGroup
+	start := time.Now()
+	source.Run(&wg, comms, []*Aircraft{a}, stop)
+
+	var types []MessageType
+	var offsets []time.Duration
+	timeout := time.After(10 * time.Second)
+	for len(types) < len(source.Records) {
+		select {
+		case msg := <-inbox:
+			if base := msg.GetBaseMessage(); !base.FromGround() {
+				types = append(types, base.Type)
+				offsets = append(offsets, simSince(start))
+			}
+		case <-timeout:
+			t.Fatalf("%s: 回放超时，只收到 %v", access.Name(), types)
+		}
+	}
+	waitFor(t, 5*time.Second, access.Name()+": 所有回放的报文都已确认", func() bool {
+		return a.GetRawStats().SuccessfulTx == uint64(len(source.Records))
+	})
+	return types, offsets, gcc
+}
+
+// 记录的轨迹按相同的类型顺序与时刻回放；不同的接入策略回放同一轨迹时，送达的报文相同。
+func TestTraceRecordAndReplay(t *testing.T) {
+	t.Cleanup(func() { replayingTrace = false })
+	types := []MessageType{MsgTypePosition, MsgTypeFuel, MsgTypeWeather}
+	const gap = 500 * time.Millisecond
+	path := recordTestTrace(t, newTestAircraft("A00001", "CCA101"), types, gap)
+
+	source, err := LoadTrace(path)
+	if err != nil {
+		t.Fatalf("读取轨迹失败: %v", err)
+	}
+	if len(source.Records) != len(types) {
+		t.Fatalf("轨迹有 %d 条记录，期望 %d", len(source.Records), len(types))
+	}
+	for i, entry := range source.Records {
+		if entry.Type != types[i] || entry.Origin != "A00001" {
+			t.Errorf("第 %d 条记录 = %s/%s，期望 %s/A00001", i, entry.Type, entry.Origin, types[i])
+		}
+		if i > 0 && entry.Offset-source.Records[i-1].Offset < gap {
+			t.Errorf("第 %d 条记录的时刻 %v 与上一条的间隔小于 %v", i, entry.Offset, gap)
+		}
+	}
+
+	// 信道空闲时 1-坚持 CSMA 立即发送，报文按记录的顺序送达，且只比记录的时刻晚一个传输时间左右
+	got, offsets, _ := replayTrace(t, source, OnePersistentCSMA{})
+	for i, entry := range source.Records {
+		if got[i] != entry.Type {
+			t.Errorf("第 %d 个回放报文为 %s，期望 %s", i, got[i], entry.Type)
+		}
+		if late := offsets[i] - entry.Offset; late < 0 || late > config.TransmissionTime+100*time.Millisecond {
+			t.Errorf("第 %d 个报文在 %v 送达，记录的时刻为 %v", i, offsets[i], entry.Offset)
+		}
+	}
+
+	// p-坚持 CSMA 可能推迟若干时隙，报文的先后可能改变，但送达的报文与地面站的统计应与 1-坚持 CSMA 相同
+	want := slices.Sorted(slices.Values(got))
+	for _, access := range []MediumAccess{OnePersistentCSMA{}, PPersistentCSMA{}} {
+		got, _, gcc := replayTrace(t, source, access)
+		if slices.Sort(got); !slices.Equal(got, want) {
+			t.Errorf("%s: 回放送达的报文为 %v，期望 %v", access.Name(), got, want)
+		}
+		if received := gcc.GetRawStats().TotalReceived; received != uint64(len(types)) {
+			t.Errorf("%s: 地面站收到 %d 个报文，期望 %d", access.Name(), received, len(types))
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package simulation
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// 可通过配置选择的交通模型
+const (
+	TrafficModelScripted = "scripted" // 脚本化或按 NumAircraft 随机生成的固定飞行计划
+	TrafficModelPoisson  = "poisson"  // 按泊松过程到达的随机交通流
+)
+
+// PoissonTrafficSource 按泊松过程生成飞行计划：相邻两架飞机进入空域的间隔服从
+// 均值为 1/RatePerMinute 分钟的指数分布，每架飞机的飞行时长在 [MinDuration, MaxDuration] 内均匀分布。
+//
+// 到达时刻在模拟开始前一次性抽取，飞机实例也随之预先创建，以便地面站和数据收集器在启动时即可登记所有飞机；
+// 每架飞机在其到达时刻才开始飞行和通信，效果与到达时才生成飞机相同。相同的 Seed 总是生成相同的交通流。
+type PoissonTrafficSource struct {
+	RatePerMinute float64       // 平均每分钟到达的飞机数
+	Window        time.Duration // 生成到达的时间范围 [0, Window)
+	MinDuration   time.Duration // 飞行时长下限
+	MaxDuration   time.Duration // 飞行时长上限
+	Seed          int64
+}
+
+// Generate 生成所有到达时刻落在 Window 内的飞行计划，离港与进港各占约一半。
+func (s PoissonTrafficSource) Generate() []FlightPlan {
+	if s.RatePerMinute <= 0 || s.Window <= 0 {
+		return nil
+	}
+	rng := rand.New(rand.NewPCG(uint64(s.Seed), 0x9e3779b97f4a7c15))
+	meanGap := float64(time.Minute) / s.RatePerMinute
+
+	var plans []FlightPlan
+	for arrival := time.Duration(rng.ExpFloat64() * meanGap); arrival < s.Window; arrival += time.Duration(rng.ExpFloat64() * meanGap) {
+		planType := "Departing"
+		if rng.IntN(2) == 1 {
+			planType = "Arriving"
+		}
+		duration := s.MinDuration
+		if s.MaxDuration > s.MinDuration {
+			duration += time.Duration(rng.Int64N(int64(s.MaxDuration - s.MinDuration)))
+		}
+		plans = append(plans, FlightPlan{
+			Type:             planType,
+			StartTimeMinutes: int(arrival / time.Minute),
+			StartOffset:      max(arrival, time.Nanosecond), // 保证非零，使精确到达时刻生效
+			Duration:         duration,
+		})
+	}
+	return plans
+}
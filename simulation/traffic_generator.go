@@ -0,0 +1,173 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// FlightPhase 是飞机当前所处的飞行阶段，供流量生成器按阶段调整例行报告。
+type FlightPhase string
+
+const (
+	PhaseDepartureCruise FlightPhase = "DEPARTURE_CRUISE" // 离港航班初始爬升结束后至飞出空域
+	PhaseArrival         FlightPhase = "ARRIVAL"          // 进港航班进入空域后至降落
+)
+
+// 可通过配置选择的例行报告流量生成器
+const (
+	TrafficGeneratorPeriodic = "periodic" // 各类报告按各自的 (带抖动的) 固定间隔发送
+	TrafficGeneratorUniform  = "uniform"  // 报告间隔在 [0, 2×平均间隔] 内均匀分布
+	TrafficGeneratorBursty   = "bursty"   // 报告成串密集发送，串与串之间长时间静默
+)
+
+// TrafficGenerator 决定飞机的例行报告 (位置、燃油、气象) 的种类与发送时刻。
+// OOOI、引擎报告、链路测试与故障报告与飞行阶段绑定，仍由 simulateFlight 的阶段状态机发送。
+// 每个航班使用独立的生成器实例，实例无需并发安全。
+type TrafficGenerator interface {
+	// Next 返回飞机在 phase 阶段的下一份例行报告的种类，以及从现在起到发送它之前需要等待的时间。
+	Next(a *Aircraft, phase FlightPhase) (MessageType, time.Duration)
+}
+
+// TrafficGeneratorFactory 为一个航班创建流量生成器，rng 是该航班专用的随机数生成器。
+type TrafficGeneratorFactory func(a *Aircraft, rng *rand.Rand) TrafficGenerator
+
+// NewTrafficGeneratorFactory 根据生成器名称返回对应的流量生成器工厂。
+// 各生成器的平均报告速率都由飞机的例行报告间隔 (经 LoadMultiplier 缩放) 决定，只有到达过程的分布不同。
+func NewTrafficGeneratorFactory(name string) (TrafficGeneratorFactory, error) {
+	switch name {
+	case TrafficGeneratorPeriodic, "":
+		return func(a *Aircraft, rng *rand.Rand) TrafficGenerator {
+			return newPeriodicTraffic(a, rng)
+		}, nil
+	case TrafficGeneratorUniform:
+		return func(a *Aircraft, rng *rand.Rand) TrafficGenerator {
+			return &uniformTraffic{mix: newRoutineMix(a), rng: rng}
+		}, nil
+	case TrafficGeneratorBursty:
+		return func(a *Aircraft, rng *rand.Rand) TrafficGenerator {
+			return &burstyTraffic{mix: newRoutineMix(a), rng: rng}
+		}, nil
+	default:
+		return nil, fmt.Errorf("未知的流量生成器: %q", name)
+	}
+}
+
+// routineReportTypes 是由流量生成器决定发送时刻的例行报告种类。
+var routineReportTypes = []MessageType{MsgTypePosition, MsgTypeFuel, MsgTypeWeather}
+
+// routineIntervals 返回飞机各类例行报告经 LoadMultiplier 缩放后的间隔，顺序与 routineReportTypes 相同。
+func routineIntervals(a *Aircraft) []time.Duration {
+	pos, fuel, weather := a.reportIntervals()
+	return []time.Duration{scaleInterval(pos), scaleInterval(fuel), scaleInterval(weather)}
+}
+
+// periodicTraffic 让每类报告按各自的间隔 ± ReportIntervalJitter (两者均经 LoadMultiplier 缩放) 周期发送，是默认的生成器。
+type periodicTraffic struct {
+	schedules []*jitteredSchedule // 与 routineReportTypes 一一对应
+}
+
+// jitteredSchedule 是一类报告的下一次发送时刻，间隔的抖动方式与 jitteredTicker 相同。
+type jitteredSchedule struct {
+	due  time.Time
+	tick jitteredTicker
+}
+
+func newPeriodicTraffic(a *Aircraft, rng *rand.Rand) *periodicTraffic {
+	now := time.Now()
+	g := &periodicTraffic{}
+	for _, interval := range routineIntervals(a) {
+		s := &jitteredSchedule{tick: jitteredTicker{interval: interval, jitter: reportJitter(), rng: rng}}
+		s.due = now.Add(Scaled(s.tick.next()))
+		g.schedules = append(g.schedules, s)
+	}
+	return g
+}
+
+func (g *periodicTraffic) Next(_ *Aircraft, _ FlightPhase) (MessageType, time.Duration) {
+	earliest := 0
+	for i, s := range g.schedules {
+		if s.due.Before(g.schedules[earliest].due) {
+			earliest = i
+		}
+	}
+	s := g.schedules[earliest]
+	wait := Unscaled(max(time.Until(s.due), 0))
+	s.due = s.due.Add(Scaled(s.tick.next()))
+	return routineReportTypes[earliest], wait
+}
+
+// routineMix 描述飞机例行报告的总体平均间隔，以及每份报告属于各类报告的概率 (与各自的发送速率成正比)。
+type routineMix struct {
+	meanGap time.Duration
+	weights []float64 // 与 routineReportTypes 一一对应，总和为 1
+}
+
+func newRoutineMix(a *Aircraft) routineMix {
+	intervals := routineIntervals(a)
+	rates := make([]float64, len(intervals))
+	var totalRate float64
+	for i, interval := range intervals {
+		rates[i] = 1 / interval.Seconds()
+		totalRate += rates[i]
+	}
+	for i := range rates {
+		rates[i] /= totalRate
+	}
+	return routineMix{meanGap: time.Duration(float64(time.Second) / totalRate), weights: rates}
+}
+
+// pick 按权重随机选择一类报告。
+func (m routineMix) pick(rng *rand.Rand) MessageType {
+	r := rng.Float64()
+	for i, w := range m.weights {
+		if r < w {
+			return routineReportTypes[i]
+		}
+		r -= w
+	}
+	return routineReportTypes[len(routineReportTypes)-1]
+}
+
+// uniformTraffic 的报告间隔在 [0, 2×meanGap] 内均匀分布，平均速率与 periodic 相同但没有固定周期。
+type uniformTraffic struct {
+	mix routineMix
+	rng *rand.Rand
+}
+
+func (g *uniformTraffic) Next(_ *Aircraft, _ FlightPhase) (MessageType, time.Duration) {
+	return g.mix.pick(g.rng), time.Duration(g.rng.Int64N(int64(2*g.mix.meanGap) + 1))
+}
+
+// burstyTraffic 每次连续发送 TrafficBurstSize 份报告 (间隔 TrafficBurstSpacing)，然后静默，
+// 静默时长使平均速率与 periodic 相同。用于观察突发流量下的信道争用。
+type burstyTraffic struct {
+	mix       routineMix
+	rng       *rand.Rand
+	remaining int // 当前这串报告中还要发送的份数
+}
+
+func (g *burstyTraffic) Next(_ *Aircraft, _ FlightPhase) (MessageType, time.Duration) {
+	size := max(config.TrafficBurstSize, 1)
+	wait := config.TrafficBurstSpacing
+	if g.remaining == 0 {
+		// 新的一串：先静默，使每串 size 份报告平均占用 size×meanGap 的时间
+		g.remaining = size
+		wait = max(time.Duration(size)*g.mix.meanGap-time.Duration(size-1)*config.TrafficBurstSpacing, 0)
+	}
+	g.remaining--
+	return g.mix.pick(g.rng), wait
+}
+
+// sendRoutineReport 发送一份由流量生成器决定的例行报告。
+func sendRoutineReport(a *Aircraft, msgType MessageType, commsSystem *CommunicationSystem) {
+	switch msgType {
+	case MsgTypePosition:
+		sendPositionReport(a, commsSystem)
+	case MsgTypeFuel:
+		sendFuelReport(a, commsSystem)
+	case MsgTypeWeather:
+		sendWeatherReport(a, commsSystem)
+	}
+}
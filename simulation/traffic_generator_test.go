@@ -0,0 +1,71 @@
+package simulation
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestNewTrafficGeneratorFactoryRejectsUnknown(t *testing.T) {
+	for _, name := range []string{"", TrafficGeneratorPeriodic, TrafficGeneratorUniform, TrafficGeneratorBursty} {
+		if _, err := NewTrafficGeneratorFactory(name); err != nil {
+			t.Errorf("NewTrafficGeneratorFactory(%q) = %v", name, err)
+		}
+	}
+	if _, err := NewTrafficGeneratorFactory("poisson-burst"); err == nil {
+		t.Error("未知的生成器名称应返回错误")
+	}
+}
+
+func TestRoutineMixWeights(t *testing.T) {
+	a := newTestAircraft("A00001", "CCA101")
+	mix := newRoutineMix(a)
+	intervals := routineIntervals(a)
+	var totalRate, sum float64
+	for _, interval := range intervals {
+		totalRate += 1 / interval.Seconds()
+	}
+	for i, w := range mix.weights {
+		sum += w
+		if want := (1 / intervals[i].Seconds()) / totalRate; math.Abs(w-want) > 1e-9 {
+			t.Errorf("%s 的权重 = %v，期望 %v", routineReportTypes[i], w, want)
+		}
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("权重之和 = %v，期望 1", sum)
+	}
+	if want := time.Duration(float64(time.Second) / totalRate); mix.meanGap != want {
+		t.Errorf("平均间隔 = %v，期望 %v", mix.meanGap, want)
+	}
+}
+
+// 各生成器的到达过程不同，但长期来看每类报告的发送速率都等于 1 / 该类报告的间隔。
+func TestTrafficGeneratorsMatchRoutineRates(t *testing.T) {
+	a := newTestAircraft("A00001", "CCA101")
+	intervals := routineIntervals(a)
+	for _, name := range []string{TrafficGeneratorPeriodic, TrafficGeneratorUniform, TrafficGeneratorBursty} {
+		factory, _ := NewTrafficGeneratorFactory(name)
+		g := factory(a, rand.New(rand.NewPCG(7, 11)))
+
+		// Next 不会真的等待：periodic 返回距下一次到期的时间，其余生成器返回相邻报告的间隔
+		const reports = 20000
+		counts := make(map[MessageType]int)
+		var elapsed time.Duration
+		for range reports {
+			msgType, wait := g.Next(a, PhaseDepartureCruise)
+			counts[msgType]++
+			if name == TrafficGeneratorPeriodic {
+				elapsed = max(elapsed, wait)
+			} else {
+				elapsed += wait
+			}
+		}
+		for i, msgType := range routineReportTypes {
+			want := elapsed.Seconds() / intervals[i].Seconds()
+			if got := float64(counts[msgType]); math.Abs(got-want) > 0.05*want {
+				t.Errorf("%s: %s 报告 %v 份，期望约 %.0f 份", name, msgType, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Transmitter 是飞机与地面站共用的信道接入逻辑及其统计，嵌入在 Aircraft 与 GroundControlCenter 中。
+// 它负责在每个时隙选择信道、执行接入策略并尝试传输，直到报文获得信道；
+// 是否等待 ACK、失败后是否重传等由嵌入它的实体决定。
+type Transmitter struct {
+	MaxQueueLength int // 同时处于发送流程的报文上限，0 表示不限制
+
+	totalTxAttempts    uint64       // 总传输尝试次数 (接入策略允许传输的次数)
+	totalCollisions    uint64       // 传输尝试失败 (碰撞) 的次数
+	totalRqTunnel      uint64       // 总请求信道次数 (每个时隙一次)
+	totalFailRqTunnel  uint64       // 请求信道时信道忙的次数
+	totalWaitTimeNs    atomic.Int64 // 从开始发送到获得信道的总等待时间 (纳秒)
+	forcedSwitchovers  uint64       // 因等待预算耗尽而强制切换到备用信道的次数
+	recentEvents       eventWindow  // 最近的收发事件，用于滑动窗口统计
+	queueOverflowDrops uint64       // 因发送中的报文已达上限而被拒绝或被放弃的报文数
+}
+
+// sender 描述接入信道的发送方，用于日志与信道上的发送方标识。
+type sender struct {
+	logKey string // 日志中标识发送方的键，例如 "flight" 或 "station"
+	id     string
+
+	// prepare 在每次传输前对已更新发送时间的帧做最后处理 (例如记录接收功率)，可以为 nil
+	prepare func(ACARSMessageInterface) ACARSMessageInterface
+
+	// halfDuplex 非 nil 时为发送方的收件箱，报文占用信道期间发送方收不到任何报文
+	halfDuplex *Listener
+
+	// receiver 非 nil 时为发送方的可调谐接收机，报文发出后调谐到发出报文的信道
+	receiver *Listener
+
+	// uplink 为 true 表示发送方是地面站；上下行分频时决定发送方使用的信道
+	uplink bool
+
+	// outbound 是发送方的发送中报文记录，用于察觉报文已因发送队列溢出被放弃，可以为 nil
+	outbound *deadLetterBook
+}
+
+// acquireChannel 执行信道接入，直到报文在某个信道上开始传输，返回该信道、发出时刻与自 sendStartTime 起的等待时间。
+// 报文在等待期间因发送队列溢出被放弃时返回 nil 信道。
+// 每个时隙都按报文等待后的有效优先级重新选择信道，以适应信道状态变化。
+func (t *Transmitter) acquireChannel(msg ACARSMessageInterface, comms *CommunicationSystem, from sender, sendStartTime time.Time) (*Channel, time.Time, time.Duration) {
+	msgID := msg.GetBaseMessage().MessageID
+	var waiting contention // 当前在哪个信道上等待，用于优先级反转检测
+	defer waiting.leave()
+
+	sw := newSwitchover()                     // 切换抽签与强制切换状态在本次发送的各时隙之间保持
+	jitterRand := newSendRand(from.id, msgID) // 本次发送各时隙等待的抖动序列，同一配置下可复现
+	for slot := 0; ; slot++ {
+		if from.outbound != nil && from.outbound.isAbandoned(msgID) {
+			return nil, time.Time{}, 0
+		}
+		// 等待越久的报文有效优先级越高，用于信道选择和 p 值
+		priority := agedPriority(msg.GetPriority(), simSince(sendStartTime))
+		targetChannel, forced := comms.selectChannel(msg, priority, from, sw)
+		if forced {
+			atomic.AddUint64(&t.forcedSwitchovers, 1)
+		}
+		p := targetChannel.GetPForMessage(priority)
+		// 从选定的目标信道获取其专属的时隙
+		timeSlotForChannel := targetChannel.GetCurrentTimeSlot()
+		waiting.wait(targetChannel, msg.GetPriority())
+
+		atomic.AddUint64(&t.totalRqTunnel, 1)
+		// 信道须持续空闲至少一个帧间间隔才视为空闲
+		channelBusy := !targetChannel.idleFor(config.InterFrameSpace)
+		if channelBusy {
+			atomic.AddUint64(&t.totalFailRqTunnel, 1)
+			if comms.isPrimary(targetChannel) {
+				sw.busyStreak++
+			}
+		} else if comms.isPrimary(targetChannel) {
+			sw.busyStreak = 0
+		}
+		// 抢占与占用腾出的容量在信道内一次完成，不再经过接入策略的 p 判断，以免白白中止了其他传输
+		if channelBusy && config.EnableCriticalPreemption && canPreempt(msg) {
+			frame, txTime := from.frame(msg)
+			if targetChannel.preemptTransmit(frame, from.id, from.halfDuplex) {
+				atomic.AddUint64(&t.totalTxAttempts, 1)
+				t.recentEvents.record(eventTxAttempt, 0)
+				return t.transmitted(targetChannel, msgID, from, sendStartTime, txTime)
+			}
+		}
+
+		transmit, waitSlots := comms.MediumAccess.ShouldTransmit(channelBusy, p, slot)
+		if transmit {
+			// 只有在接入策略允许时才真正尝试传输，这构成一次“传输尝试”
+			atomic.AddUint64(&t.totalTxAttempts, 1)
+			t.recentEvents.record(eventTxAttempt, 0)
+			frame, txTime := from.frame(msg)
+			if targetChannel.attemptTransmit(frame, from.id, from.halfDuplex) {
+				return t.transmitted(targetChannel, msgID, from, sendStartTime, txTime)
+			}
+			// 传输失败，即发生碰撞
+			logMessageEvent(MessageEventCollided, from.id, msgID, targetChannel.ID, "")
+			atomic.AddUint64(&t.totalCollisions, 1)
+			t.recentEvents.record(eventCollision, 0)
+			slog.Debug("💥 发生碰撞", from.logKey, from.id, "msgID", msgID, "channel", targetChannel.ID)
+		} else if channelBusy {
+			slog.Debug("⏳ 信道忙，持续监听", from.logKey, from.id, "msgID", msgID, "channel", targetChannel.ID)
+		} else {
+			slog.Debug("🤔 信道空闲，但决定延迟", from.logKey, from.id, "msgID", msgID, "channel", targetChannel.ID, "p", p)
+		}
+		// 使用从信道获取的专属时隙进行等待
+		time.Sleep(slotWait(waitSlots, timeSlotForChannel, targetChannel.SlotJitter, jitterRand))
+	}
+}
+
+// frame 为一次传输准备帧：更新发送时间并执行 prepare，返回帧与发送时刻。
+func (from sender) frame(msg ACARSMessageInterface) (ACARSMessageInterface, time.Time) {
+	txTime := time.Now()
+	frame := withTimestamp(msg, txTime)
+	if from.prepare != nil {
+		frame = from.prepare(frame)
+	}
+	return frame, txTime
+}
+
+// transmitted 记录报文已在信道 ch 上开始传输，返回 acquireChannel 的结果。
+func (t *Transmitter) transmitted(ch *Channel, msgID string, from sender, sendStartTime, txTime time.Time) (*Channel, time.Time, time.Duration) {
+	waitTime := simSince(sendStartTime)
+	t.totalWaitTimeNs.Add(waitTime.Nanoseconds())
+	logMessageEvent(MessageEventTransmitted, from.id, msgID, ch.ID, "")
+	if from.receiver != nil {
+		from.receiver.retune(ch)
+	}
+	return ch, txTime, waitTime
+}
+
+// canPreempt 判断报文能否抢占信道：只有本身即为 CRITICAL 的报文可以，因等待而老化到 CRITICAL 的报文不行。
+// ACK/NACK 虽然也是 CRITICAL，但它们确认的往往正是被抢占的数据报文，因此同样不能抢占。
+func canPreempt(msg ACARSMessageInterface) bool {
+	return msg.GetPriority() == config.CriticalPriority && msg.GetBaseMessage().Type != MsgTypeAck
+}
+
+// resetTransmitStats 重置信道接入统计与滑动窗口事件。
+func (t *Transmitter) resetTransmitStats() {
+	atomic.StoreUint64(&t.totalTxAttempts, 0)
+	atomic.StoreUint64(&t.totalCollisions, 0)
+	atomic.StoreUint64(&t.totalRqTunnel, 0)
+	atomic.StoreUint64(&t.totalFailRqTunnel, 0)
+	atomic.StoreUint64(&t.forcedSwitchovers, 0)
+	atomic.StoreUint64(&t.queueOverflowDrops, 0)
+	t.totalWaitTimeNs.Store(0)
+	t.recentEvents.reset()
+}
@@ -0,0 +1,55 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"testing"
+	"time"
+)
+
+// 飞机与地面站共用 Transmitter：相同的流量经各自的发送流程，在相同状态的新信道上得到相同的接入统计。
+func TestTransmitterCountersMatchForAircraftAndStation(t *testing.T) {
+	withTransmissionTimes(t, map[MessageType]time.Duration{MsgTypeEngineReport: 200 * time.Millisecond})
+
+	// run 在一个先被占用 200ms 的新信道上，以 1-坚持 CSMA 经 send 发送一个报文，返回发送方的接入统计
+	run := func(send func(comms *CommunicationSystem, msg ACARSMessageInterface) *Transmitter) TransmitterSnapshot {
+		ch := newTestChannel("Primary")
+		comms := newTestComms(ch, OnePersistentCSMA{})
+		blocker := newTestAircraft("B00001", "BLK001")
+		if !ch.AttemptTransmit(newTestMessage(t, blocker, "BLK001-ENG-1", MsgTypeEngineReport, config.LowPriority, map[string]int{"n1": 85}), blocker.CurrentFlightID) {
+			t.Fatal("空闲信道拒绝了占用信道的报文")
+		}
+		a := newTestAircraft("A00001", "CCA101")
+		msg := newTestMessage(t, a, "CCA101-FREE-1", MsgTypeFreeText, config.HighPriority, map[string]string{"text": "HELLO"})
+		return send(comms, msg).snapshot()
+	}
+
+	aircraftStats := run(func(comms *CommunicationSystem, msg ACARSMessageInterface) *Transmitter {
+		a := newTestAircraft("A00001", "CCA101")
+		gcc := newTestStation("GND")
+		gcc.TrackAircraft([]*Aircraft{a})
+		startTestEntities(comms, []*Aircraft{a}, []*GroundControlCenter{gcc})
+		a.SendMessage(msg, comms)
+		if got := a.GetRawStats().SuccessfulTx; got != 1 {
+			t.Fatalf("飞机成功发送数 = %d，期望 1", got)
+		}
+		return &a.Transmitter
+	})
+	stationStats := run(func(comms *CommunicationSystem, msg ACARSMessageInterface) *Transmitter {
+		gcc := newTestStation("GND")
+		gcc.SendMessage(msg, comms)
+		return &gcc.Transmitter
+	})
+
+	// 信道在第一个时隙忙，下一个时隙空闲时立即发送
+	want := TransmitterSnapshot{TotalTxAttempts: 1, TotalRqTunnel: 2, TotalFailRqTunnel: 1}
+	for name, got := range map[string]TransmitterSnapshot{"飞机": aircraftStats, "地面站": stationStats} {
+		wait := time.Duration(got.TotalWaitTimeNs)
+		got.TotalWaitTimeNs = 0
+		if got != want {
+			t.Errorf("%s的接入统计 = %+v，期望 %+v", name, got, want)
+		}
+		if slot := config.PrimaryTimeSlot; wait < slot || wait > slot+100*time.Millisecond {
+			t.Errorf("%s的等待时间 = %v，期望约一个时隙 (%v)", name, wait, slot)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package simulation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// faultCodePattern 是故障代码的格式，例如 "ENG1-OVHT"。
+var faultCodePattern = regexp.MustCompile(`^[A-Z0-9]+(-[A-Z0-9]+)*$`)
+
+// faultSeverities 是故障报告允许的严重性等级。
+var faultSeverities = map[string]bool{"CRITICAL": true, "MAJOR": true, "MINOR": true, "ADVISORY": true}
+
+// knownMessageTypes 是地面站能够处理的报文类型。
+var knownMessageTypes = map[MessageType]bool{
+	MsgTypeAircraftFault: true, MsgTypeATCMessage: true,
+	MsgTypeOOOI: true, MsgTypePosition: true, MsgTypeFuel: true,
+	MsgTypeEngineReport: true, MsgTypeWeather: true, MsgTypeWeatherRequest: true, MsgTypePDC: true,
+	MsgTypeFreeText: true, MsgTypeLinkTest: true,
+}
+
+// validateMessage 检查地面站收到的报文是否格式正确，返回的错误即为 NACK 中的拒绝原因。
+// 重传无法修复这类错误，因此发送方收到 NACK 后应立即放弃该报文。
+// 分片报文只检查头部，其数据在重组前无法解析。
+func validateMessage(msg ACARSMessageInterface) error {
+	baseMsg := msg.GetBaseMessage()
+	switch {
+	case baseMsg.MessageID == "":
+		return errors.New("缺少报文ID")
+	case baseMsg.FlightID == "":
+		return errors.New("缺少航班号")
+	case !knownMessageTypes[baseMsg.Type]:
+		return fmt.Errorf("未知的报文类型 %q", baseMsg.Type)
+	}
+	if baseMsg.IsFragment() {
+		return nil
+	}
+
+	rawData, ok := msg.GetData().(json.RawMessage)
+	if !ok {
+		return errors.New("报文数据不是 JSON")
+	}
+	switch baseMsg.Type {
+	case MsgTypeAircraftFault:
+		var fault AircraftFaultData
+		if err := json.Unmarshal(rawData, &fault); err != nil {
+			return fmt.Errorf("故障报告无法解析: %w", err)
+		}
+		if !faultCodePattern.MatchString(fault.FaultCode) {
+			return fmt.Errorf("无效的故障代码 %q", fault.FaultCode)
+		}
+		if !faultSeverities[fault.Severity] {
+			return fmt.Errorf("无效的故障严重性 %q", fault.Severity)
+		}
+		if fault.System == "" {
+			return errors.New("故障报告缺少故障系统")
+		}
+	case MsgTypeATCMessage:
+		var atc ATCMessageData
+		if err := json.Unmarshal(rawData, &atc); err != nil {
+			return fmt.Errorf("ATC 消息无法解析: %w", err)
+		}
+		if atc.ATCMsgType == "" || atc.Content == "" {
+			return errors.New("ATC 消息缺少类型或内容")
+		}
+	case MsgTypePosition:
+		var pos PositionReportData
+		if err := json.Unmarshal(rawData, &pos); err != nil {
+			return fmt.Errorf("位置报告无法解析: %w", err)
+		}
+		if pos.Latitude < -90 || pos.Latitude > 90 || pos.Longitude < -180 || pos.Longitude > 180 {
+			return fmt.Errorf("位置超出范围 (%.4f, %.4f)", pos.Latitude, pos.Longitude)
+		}
+	default:
+		if !json.Valid(rawData) {
+			return errors.New("报文数据不是有效的 JSON")
+		}
+	}
+	return nil
+}
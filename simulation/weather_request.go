@@ -0,0 +1,81 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// weatherRequestLocation 是飞机请求气象信息的机场，与 D-ATIS 广播的机场相同。
+const weatherRequestLocation = "ZBAA"
+
+// sendWeatherRequest 向地面站请求气象信息 (METAR)，地面站的上行回复按请求报文ID关联，见 receiveWeather。
+func sendWeatherRequest(a *Aircraft, commsSystem *CommunicationSystem) {
+	slog.Debug("🌦️  准备发送气象请求", "flight", a.CurrentFlightID)
+	baseMsg := ACARSBaseMessage{
+		AircraftICAOAddress: a.ICAOAddress, FlightID: a.CurrentFlightID,
+		MessageID: fmt.Sprintf("%s-WXRQ-%d", a.CurrentFlightID, time.Now().UnixNano()),
+		Type:      MsgTypeWeatherRequest,
+	}
+	a.weatherRequests.Store(baseMsg.MessageID, struct{}{})
+	atomic.AddUint64(&a.weatherRequestsSent, 1)
+	sendReport(a, baseMsg, config.MediumPriority, WeatherData{RequestType: "METAR", Location: weatherRequestLocation}, commsSystem)
+}
+
+// receiveWeather 处理地面站上行的气象信息：只接受本机仍在等待的请求的回复，记录为最新气象信息。
+func (a *Aircraft) receiveWeather(msg ACARSMessageInterface) {
+	var data WeatherData
+	rawData, ok := msg.GetData().(json.RawMessage)
+	if !ok || json.Unmarshal(rawData, &data) != nil {
+		return
+	}
+	if _, waiting := a.weatherRequests.LoadAndDelete(data.RequestID); !waiting {
+		return // 其他飞机的回复，或重复的回复
+	}
+	atomic.AddUint64(&a.weatherRepliesReceived, 1)
+	a.weatherMutex.Lock()
+	a.latestWeather = &data
+	a.weatherMutex.Unlock()
+	slog.Debug("🌦️  收到气象信息", "flight", a.CurrentFlightID, "station", msg.GetBaseMessage().AircraftICAOAddress, "requestID", data.RequestID)
+}
+
+// LatestWeather 返回最近收到的气象信息回复，尚未收到时返回 nil。
+func (a *Aircraft) LatestWeather() *WeatherData {
+	a.weatherMutex.Lock()
+	defer a.weatherMutex.Unlock()
+	return a.latestWeather
+}
+
+// replyWeather 为飞机的气象请求合成 METAR，并上行发送给请求方。
+func (gcc *GroundControlCenter) replyWeather(request ACARSMessageInterface, commsSystem *CommunicationSystem) {
+	requestBase := request.GetBaseMessage()
+	var requested WeatherData
+	if rawData, ok := request.GetData().(json.RawMessage); !ok || json.Unmarshal(rawData, &requested) != nil {
+		return
+	}
+	data := WeatherData{
+		RequestType: requested.RequestType,
+		Location:    requested.Location,
+		Content:     fmt.Sprintf("METAR %s %sZ 27012KT 9999 FEW030 M05/M12 Q1013", requested.Location, time.Now().UTC().Format("021504")),
+		RequestID:   requestBase.MessageID,
+	}
+	baseMsg := ACARSBaseMessage{
+		AircraftICAOAddress: gcc.ID,
+		FlightID:            "GND_CTL",
+		MessageID:           fmt.Sprintf("WX-%s", requestBase.MessageID),
+		Timestamp:           time.Now(),
+		Type:                MsgTypeWeather,
+		Origin:              OriginGround,
+		Recipients:          []string{requestBase.AircraftICAOAddress},
+	}
+	reply, err := NewMediumLowPriorityMessage(baseMsg, data)
+	if err != nil {
+		slog.Error("创建气象回复失败", "station", gcc.ID, "requestID", requestBase.MessageID, "err", err)
+		return
+	}
+	atomic.AddUint64(&gcc.weatherReplies, 1)
+	go gcc.SendMessage(reply, commsSystem)
+}
@@ -0,0 +1,154 @@
+package simulation
+
+import (
+	"Air-Simulator/config"
+	"sync"
+	"time"
+)
+
+// windowEventKind 是滑动窗口统计中记录的事件类型。
+type windowEventKind uint8
+
+const (
+	eventTxAttempt windowEventKind = iota // 发送方的一次传输尝试
+	eventCollision                        // 发送方的一次碰撞 (传输尝试失败)
+	eventSuccess                          // 发送方的一次成功传输
+	eventRetry                            // 飞机的一次重传
+	eventReceived                         // 地面站收到并处理的一个覆盖范围内的报文 (不含重复报文)
+	eventDelivered                        // 信道上一次未损坏的传输结束，value 为其占用时间
+	eventCorrupted                        // 信道上一次损坏的传输结束，value 为其占用时间
+)
+
+type windowEvent struct {
+	at    time.Time
+	kind  windowEventKind
+	value time.Duration
+}
+
+// eventWindow 是一个有界的环形缓冲，保存最近的带时间戳事件，用于计算最近一段时间内的计数与速率。
+// 零值可直接使用，容量为 config.WindowedStatsCapacity；缓冲写满后最早的事件被覆盖。
+type eventWindow struct {
+	mutex  sync.Mutex
+	events []windowEvent
+	next   int
+	full   bool
+}
+
+// record 记录一个发生在当前时刻的事件。
+func (w *eventWindow) record(kind windowEventKind, value time.Duration) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.events == nil {
+		w.events = make([]windowEvent, max(config.WindowedStatsCapacity, 1))
+	}
+	w.events[w.next] = windowEvent{at: time.Now(), kind: kind, value: value}
+	w.next = (w.next + 1) % len(w.events)
+	if w.next == 0 {
+		w.full = true
+	}
+}
+
+// reset 清空所有事件。
+func (w *eventWindow) reset() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.events = nil
+	w.next = 0
+	w.full = false
+}
+
+// summarize 统计最近 window 内的事件。
+func (w *eventWindow) summarize(window time.Duration) WindowedStats {
+	stats := WindowedStats{Window: window}
+	since := time.Now().Add(-Scaled(window))
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.full && w.events[w.next].at.After(since) {
+		stats.Truncated = true // 缓冲中最早的事件仍在窗口内，更早的事件已被覆盖
+	}
+	for _, event := range w.events {
+		if event.at.IsZero() || event.at.Before(since) {
+			continue
+		}
+		switch event.kind {
+		case eventTxAttempt:
+			stats.TxAttempts++
+		case eventCollision:
+			stats.Collisions++
+		case eventSuccess:
+			stats.SuccessfulTx++
+		case eventRetry:
+			stats.Retries++
+		case eventReceived:
+			stats.Received++
+		case eventDelivered:
+			stats.Transmitted++
+			stats.BusyTime += event.value
+		case eventCorrupted:
+			stats.Corrupted++
+			stats.BusyTime += event.value
+		}
+	}
+	return stats
+}
+
+// WindowedStats 是最近一段时间 (Window) 内的计数，供实时仪表盘显示“当前”速率，而无需对累计计数做差分。
+// 飞机使用发送相关字段与 Retries，地面站使用发送相关字段与 Received，信道使用 Transmitted、Corrupted 与 BusyTime。
+type WindowedStats struct {
+	Window    time.Duration
+	Truncated bool // 事件缓冲容量不足以覆盖整个窗口，计数偏低
+
+	// --- 发送方 (飞机与地面站) ---
+	TxAttempts   uint64
+	Collisions   uint64
+	SuccessfulTx uint64
+	Retries      uint64
+
+	// --- 地面站 ---
+	Received uint64
+
+	// --- 信道 ---
+	Transmitted uint64        // 成功传输的报文数
+	Corrupted   uint64        // 因重叠碰撞损坏的传输数
+	BusyTime    time.Duration // 窗口内结束的传输占用信道的时间之和
+}
+
+// PerSecond 将窗口内的计数换算为每秒速率。
+func (s WindowedStats) PerSecond(count uint64) float64 {
+	if s.Window <= 0 {
+		return 0
+	}
+	return float64(count) / s.Window.Seconds()
+}
+
+// CollisionRate 返回窗口内传输尝试的碰撞比例。
+func (s WindowedStats) CollisionRate() float64 {
+	if s.TxAttempts == 0 {
+		return 0
+	}
+	return float64(s.Collisions) / float64(s.TxAttempts)
+}
+
+// Utilization 返回窗口内信道被占用的时间比例 (未按容量归一化)。
+func (s WindowedStats) Utilization() float64 {
+	if s.Window <= 0 {
+		return 0
+	}
+	return float64(s.BusyTime) / float64(s.Window)
+}
+
+// GetWindowedStats 返回飞机最近 window 内的发送统计。
+func (a *Aircraft) GetWindowedStats(window time.Duration) WindowedStats {
+	return a.recentEvents.summarize(window)
+}
+
+// GetWindowedStats 返回地面站最近 window 内的收发统计。
+func (gcc *GroundControlCenter) GetWindowedStats(window time.Duration) WindowedStats {
+	return gcc.recentEvents.summarize(window)
+}
+
+// GetWindowedStats 返回信道最近 window 内的传输统计。
+func (c *Channel) GetWindowedStats(window time.Duration) WindowedStats {
+	return c.recentEvents.summarize(window)
+}